@@ -0,0 +1,339 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merkletree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"sort"
+)
+
+var (
+	errEmptyTree       = errors.New("the tree is empty")
+	errNoIndices       = errors.New("no index to prove")
+	errIndexOutOfRange = errors.New("index out of range")
+	errInvalidProof    = errors.New("invalid multi-proof encoding")
+)
+
+// RetainedTree is a Merkle tree whose leaves and nodes are all kept in
+// memory, so that any set of leaves can be proven after the tree is built.
+// It has the same shape and root as a Tree built by pushing the same leaves:
+// at each level, a node without sibling is promoted to the next level.
+type RetainedTree struct {
+	hash   hash.Hash
+	leaves [][]byte
+
+	// levels[0] are the hashes of the leaves, levels[len(levels)-1] holds
+	// the root
+	levels [][][]byte
+}
+
+// MultiProof proves that several leaves belong to a Merkle tree. The
+// siblings shared by the paths of several leaves, or that can be computed
+// from the proven leaves, appear only once.
+type MultiProof struct {
+	// NumLeaves is the number of leaves of the tree
+	NumLeaves uint64
+
+	// Indices of the proven leaves, in increasing order
+	Indices []uint64
+
+	// Leaves data of the proven leaves
+	Leaves [][]byte
+
+	// Siblings are the nodes needed to recompute the root, level by level
+	// starting from the leaves, and from left to right within a level
+	Siblings [][]byte
+}
+
+// NewRetainedTree builds the Merkle tree of leaves, using h as hash function
+func NewRetainedTree(h hash.Hash, leaves [][]byte) *RetainedTree {
+	t := &RetainedTree{
+		hash:   h,
+		leaves: leaves,
+	}
+	if len(leaves) == 0 {
+		return t
+	}
+
+	level := make([][]byte, len(leaves))
+	for i := range leaves {
+		level[i] = leafSum(h, leaves[i])
+	}
+	t.levels = append(t.levels, level)
+	for len(level) > 1 {
+		next := make([][]byte, (len(level)+1)/2)
+		for i := range next {
+			if 2*i+1 < len(level) {
+				next[i] = nodeSum(h, level[2*i], level[2*i+1])
+			} else {
+				next[i] = level[2*i]
+			}
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+	return t
+}
+
+// Root returns the Merkle root of the tree, nil if the tree is empty
+func (t *RetainedTree) Root() []byte {
+	if len(t.levels) == 0 {
+		return nil
+	}
+	root := t.levels[len(t.levels)-1][0]
+	return append(root[:0:0], root...)
+}
+
+// NumLeaves returns the number of leaves of the tree
+func (t *RetainedTree) NumLeaves() uint64 {
+	return uint64(len(t.leaves))
+}
+
+// Prove returns a proof that the leaf at index is in the tree, in the format
+// of Tree.Prove, to be checked with VerifyProof.
+func (t *RetainedTree) Prove(index uint64) (proofSet [][]byte, err error) {
+	if index >= t.NumLeaves() {
+		return nil, errIndexOutOfRange
+	}
+	proofSet = append(proofSet, t.leaves[index])
+	pos := index
+	for _, level := range t.levels[:len(t.levels)-1] {
+		if sibling := pos ^ 1; sibling < uint64(len(level)) {
+			proofSet = append(proofSet, level[sibling])
+		}
+		pos /= 2
+	}
+	return proofSet, nil
+}
+
+// ProveMulti returns a proof that the leaves at indices are in the tree.
+// indices need not be sorted, duplicates are ignored.
+func (t *RetainedTree) ProveMulti(indices []uint64) (MultiProof, error) {
+	if len(t.levels) == 0 {
+		return MultiProof{}, errEmptyTree
+	}
+	if len(indices) == 0 {
+		return MultiProof{}, errNoIndices
+	}
+
+	sorted := append([]uint64(nil), indices...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	proof := MultiProof{NumLeaves: t.NumLeaves()}
+	for i, index := range sorted {
+		if i > 0 && index == sorted[i-1] {
+			continue
+		}
+		if index >= proof.NumLeaves {
+			return MultiProof{}, errIndexOutOfRange
+		}
+		proof.Indices = append(proof.Indices, index)
+		proof.Leaves = append(proof.Leaves, t.leaves[index])
+	}
+
+	// the known nodes of a level are the ones computable from the proven
+	// leaves; a sibling is added when it is not known
+	known := proof.Indices
+	for _, level := range t.levels[:len(t.levels)-1] {
+		parents := make([]uint64, 0, len(known))
+		for i := 0; i < len(known); i++ {
+			pos := known[i]
+			sibling := pos ^ 1
+			switch {
+			case sibling >= uint64(len(level)):
+				// promoted node
+			case i+1 < len(known) && known[i+1] == sibling:
+				i++
+			default:
+				proof.Siblings = append(proof.Siblings, level[sibling])
+			}
+			parents = append(parents, pos/2)
+		}
+		known = parents
+	}
+
+	return proof, nil
+}
+
+// VerifyMultiProof returns true if proof proves that its leaves are in the
+// Merkle tree of root merkleRoot.
+func VerifyMultiProof(h hash.Hash, merkleRoot []byte, proof *MultiProof) bool {
+	if merkleRoot == nil || len(proof.Indices) == 0 || len(proof.Indices) != len(proof.Leaves) {
+		return false
+	}
+	for i, index := range proof.Indices {
+		if index >= proof.NumLeaves || (i > 0 && index <= proof.Indices[i-1]) {
+			return false
+		}
+	}
+
+	// positions and hashes of the known nodes of the current level
+	positions := append([]uint64(nil), proof.Indices...)
+	sums := make([][]byte, len(proof.Leaves))
+	for i := range proof.Leaves {
+		sums[i] = leafSum(h, proof.Leaves[i])
+	}
+	siblings := proof.Siblings
+
+	for levelSize := proof.NumLeaves; levelSize > 1; levelSize = (levelSize + 1) / 2 {
+		nbParents := 0
+		for i := 0; i < len(positions); i++ {
+			pos := positions[i]
+			sibling := pos ^ 1
+			var parent []byte
+			switch {
+			case sibling >= levelSize:
+				parent = sums[i]
+			case i+1 < len(positions) && positions[i+1] == sibling:
+				parent = nodeSum(h, sums[i], sums[i+1])
+				i++
+			default:
+				if len(siblings) == 0 {
+					return false
+				}
+				if pos&1 == 0 {
+					parent = nodeSum(h, sums[i], siblings[0])
+				} else {
+					parent = nodeSum(h, siblings[0], sums[i])
+				}
+				siblings = siblings[1:]
+			}
+			positions[nbParents] = pos / 2
+			sums[nbParents] = parent
+			nbParents++
+		}
+		positions = positions[:nbParents]
+		sums = sums[:nbParents]
+	}
+
+	return len(siblings) == 0 && bytes.Equal(sums[0], merkleRoot)
+}
+
+// WriteTo writes the binary encoding of the proof to w: the number of leaves,
+// the indices, then the leaves and the siblings, each prefixed by its length.
+// All the integers are encoded on 8 bytes in big endian.
+// It returns the number of bytes written.
+func (proof *MultiProof) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	writeUint64 := func(v uint64) error {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], v)
+		m, err := w.Write(buf[:])
+		n += int64(m)
+		return err
+	}
+	writeSlices := func(s [][]byte) error {
+		if err := writeUint64(uint64(len(s))); err != nil {
+			return err
+		}
+		for i := range s {
+			if err := writeUint64(uint64(len(s[i]))); err != nil {
+				return err
+			}
+			m, err := w.Write(s[i])
+			n += int64(m)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := writeUint64(proof.NumLeaves); err != nil {
+		return n, err
+	}
+	if err := writeUint64(uint64(len(proof.Indices))); err != nil {
+		return n, err
+	}
+	for _, index := range proof.Indices {
+		if err := writeUint64(index); err != nil {
+			return n, err
+		}
+	}
+	if err := writeSlices(proof.Leaves); err != nil {
+		return n, err
+	}
+	err := writeSlices(proof.Siblings)
+	return n, err
+}
+
+// ReadFrom reads a proof encoded by WriteTo from r.
+// It returns the number of bytes read.
+func (proof *MultiProof) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	readUint64 := func() (uint64, error) {
+		var buf [8]byte
+		m, err := io.ReadFull(r, buf[:])
+		n += int64(m)
+		return binary.BigEndian.Uint64(buf[:]), err
+	}
+	// the lengths are not trusted: the slices grow as the data is read
+	readBytes := func() ([]byte, error) {
+		length, err := readUint64()
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		m, err := io.CopyN(&buf, r, int64(length))
+		n += m
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return buf.Bytes(), err
+	}
+	readSlices := func() ([][]byte, error) {
+		count, err := readUint64()
+		if err != nil {
+			return nil, err
+		}
+		var res [][]byte
+		for i := uint64(0); i < count; i++ {
+			b, err := readBytes()
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, b)
+		}
+		return res, nil
+	}
+
+	var err error
+	if proof.NumLeaves, err = readUint64(); err != nil {
+		return n, err
+	}
+	nbIndices, err := readUint64()
+	if err != nil {
+		return n, err
+	}
+	if nbIndices > proof.NumLeaves {
+		return n, errInvalidProof
+	}
+	proof.Indices = nil
+	for i := uint64(0); i < nbIndices; i++ {
+		index, err := readUint64()
+		if err != nil {
+			return n, err
+		}
+		proof.Indices = append(proof.Indices, index)
+	}
+	if proof.Leaves, err = readSlices(); err != nil {
+		return n, err
+	}
+	proof.Siblings, err = readSlices()
+	return n, err
+}
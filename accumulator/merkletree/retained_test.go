@@ -0,0 +1,170 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merkletree
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomLeaves(rnd *rand.Rand, n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		leaves[i] = make([]byte, 1+rnd.Intn(64))
+		rnd.Read(leaves[i])
+	}
+	return leaves
+}
+
+func TestRetainedTreeRoot(t *testing.T) {
+	assert := require.New(t)
+	rnd := rand.New(rand.NewSource(0)) //#nosec G404 weak rng is fine for tests
+
+	assert.Nil(NewRetainedTree(sha256.New(), nil).Root())
+
+	for n := 1; n <= 33; n++ {
+		leaves := randomLeaves(rnd, n)
+		tree := New(sha256.New())
+		for i := range leaves {
+			tree.Push(leaves[i])
+		}
+		retained := NewRetainedTree(sha256.New(), leaves)
+		assert.Equal(tree.Root(), retained.Root(), "n = %d", n)
+
+		// single proofs are the ones of Tree
+		for index := uint64(0); index < uint64(n); index++ {
+			proofSet, err := retained.Prove(index)
+			assert.NoError(err)
+			assert.True(VerifyProof(sha256.New(), retained.Root(), proofSet, index, uint64(n)))
+
+			tree := New(sha256.New())
+			assert.NoError(tree.SetIndex(index))
+			for i := range leaves {
+				tree.Push(leaves[i])
+			}
+			_, expected, _, _ := tree.Prove()
+			assert.Equal(expected, proofSet)
+		}
+	}
+}
+
+func TestMultiProof(t *testing.T) {
+	assert := require.New(t)
+	rnd := rand.New(rand.NewSource(0)) //#nosec G404 weak rng is fine for tests
+
+	for _, n := range []int{1, 2, 3, 7, 8, 13, 64, 100} {
+		leaves := randomLeaves(rnd, n)
+		tree := NewRetainedTree(sha256.New(), leaves)
+		root := tree.Root()
+
+		for _, nbIndices := range []int{1, 2, 5, n} {
+			indices := make([]uint64, nbIndices)
+			for i := range indices {
+				indices[i] = uint64(rnd.Intn(n))
+			}
+			proof, err := tree.ProveMulti(indices)
+			assert.NoError(err)
+			assert.True(VerifyMultiProof(sha256.New(), root, &proof))
+
+			// shared siblings are deduplicated
+			nbSiblings := 0
+			for _, index := range proof.Indices {
+				proofSet, _ := tree.Prove(index)
+				nbSiblings += len(proofSet) - 1
+			}
+			assert.LessOrEqual(len(proof.Siblings), nbSiblings)
+
+			// serialization
+			var buf bytes.Buffer
+			written, err := proof.WriteTo(&buf)
+			assert.NoError(err)
+			var decoded MultiProof
+			read, err := decoded.ReadFrom(bytes.NewReader(buf.Bytes()))
+			assert.NoError(err)
+			assert.Equal(written, read)
+			assert.True(VerifyMultiProof(sha256.New(), root, &decoded))
+			_, err = decoded.ReadFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+			assert.Error(err)
+
+			// wrong leaf
+			wrong := proof
+			wrong.Leaves = append([][]byte(nil), proof.Leaves...)
+			wrong.Leaves[0] = append([]byte{1}, proof.Leaves[0]...)
+			assert.False(VerifyMultiProof(sha256.New(), root, &wrong))
+
+			// wrong index
+			if len(proof.Indices) < n {
+				wrong = proof
+				wrong.Indices = append([]uint64(nil), proof.Indices...)
+				for i := range wrong.Indices {
+					if wrong.Indices[i] > 0 && (i == 0 || wrong.Indices[i-1] < wrong.Indices[i]-1) {
+						wrong.Indices[i]--
+						break
+					}
+					if wrong.Indices[i] < uint64(n)-1 && (i == len(wrong.Indices)-1 || wrong.Indices[i+1] > wrong.Indices[i]+1) {
+						wrong.Indices[i]++
+						break
+					}
+				}
+				assert.False(VerifyMultiProof(sha256.New(), root, &wrong))
+			}
+
+			// missing or extra sibling
+			if len(proof.Siblings) > 0 {
+				wrong = proof
+				wrong.Siblings = proof.Siblings[1:]
+				assert.False(VerifyMultiProof(sha256.New(), root, &wrong))
+			}
+			wrong = proof
+			wrong.Siblings = append(append([][]byte(nil), proof.Siblings...), root)
+			assert.False(VerifyMultiProof(sha256.New(), root, &wrong))
+		}
+	}
+
+	tree := NewRetainedTree(sha256.New(), randomLeaves(rnd, 4))
+	_, err := tree.ProveMulti(nil)
+	assert.Error(err)
+	_, err = tree.ProveMulti([]uint64{4})
+	assert.Error(err)
+	_, err = NewRetainedTree(sha256.New(), nil).ProveMulti([]uint64{0})
+	assert.Error(err)
+}
+
+func BenchmarkProveMulti(b *testing.B) {
+	rnd := rand.New(rand.NewSource(0)) //#nosec G404 weak rng is fine for tests
+	leaves := randomLeaves(rnd, 1<<16)
+	tree := NewRetainedTree(sha256.New(), leaves)
+	indices := make([]uint64, 128)
+	for i := range indices {
+		indices[i] = uint64(rnd.Intn(len(leaves)))
+	}
+	proof, _ := tree.ProveMulti(indices)
+	root := tree.Root()
+
+	b.Run("prove", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = tree.ProveMulti(indices)
+		}
+	})
+	b.Run("verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = VerifyMultiProof(sha256.New(), root, &proof)
+		}
+	})
+}
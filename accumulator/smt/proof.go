@@ -0,0 +1,234 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package smt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"math/bits"
+)
+
+var errInvalidProof = errors.New("invalid proof encoding")
+
+// Proof proves that a key is in the tree with a given value, or that it is
+// not in the tree. It holds the siblings of the path of the key that are not
+// roots of empty subtrees.
+type Proof struct {
+	// Bitmap has its bit of index depth set if the sibling at depth is not
+	// the root of an empty subtree, the most significant bit of Bitmap[0]
+	// being the one of depth 0.
+	Bitmap [Depth / 8]byte
+
+	// Siblings are the siblings that are not roots of empty subtrees, from
+	// the root to the leaf
+	Siblings [][]byte
+}
+
+func (proof *Proof) hasSibling(depth int) bool {
+	return (*Key)(&proof.Bitmap).bit(depth) == 1
+}
+
+func (proof *Proof) addSibling(depth int, sibling []byte) {
+	proof.Bitmap[depth/8] |= 1 << (7 - depth%8)
+	proof.Siblings = append(proof.Siblings, sibling)
+}
+
+// Prove returns a proof for key: an inclusion proof if key is in the tree,
+// and an exclusion proof otherwise.
+func (t *SMT) Prove(key Key) (Proof, error) {
+	var proof Proof
+	hash := t.root
+	for depth := 0; depth < Depth; depth++ {
+		left, right, leaf, err := t.readNode(hash, Depth-depth)
+		if err != nil {
+			return Proof{}, err
+		}
+		if leaf != nil {
+			if leaf.key != key {
+				// the paths of key and of the leaf diverge below, where the
+				// leaf is the only key of the sibling subtree
+				d := depth
+				for key.bit(d) == leaf.key.bit(d) {
+					d++
+				}
+				sibling, err := leafHash(t.hash, t.defaults, &leaf.key, leaf.value, Depth-d-1)
+				if err != nil {
+					return Proof{}, err
+				}
+				proof.addSibling(d, sibling)
+			}
+			break
+		}
+		if left == nil {
+			// empty subtree
+			break
+		}
+		if key.bit(depth) == 0 {
+			proof.addSibling(depth, right)
+			hash = left
+		} else {
+			proof.addSibling(depth, left)
+			hash = right
+		}
+	}
+	return proof, nil
+}
+
+// Verifier verifies proofs against the roots of trees using a given hash. It
+// computes the roots of the empty subtrees once, so that it should be reused
+// to verify many proofs. It is not safe for concurrent use.
+type Verifier struct {
+	hash hash.Hash
+
+	// defaults[h] is the root of the empty subtree of height h
+	defaults [][]byte
+}
+
+// NewVerifier returns a verifier of the proofs of trees using h
+func NewVerifier(h hash.Hash) *Verifier {
+	return &Verifier{
+		hash:     h,
+		defaults: defaultHashes(h),
+	}
+}
+
+// Verifier returns a verifier of the proofs of t, sharing its hash
+func (t *SMT) Verifier() *Verifier {
+	return &Verifier{
+		hash:     t.hash,
+		defaults: t.defaults,
+	}
+}
+
+// VerifyProof returns true if proof proves that key is in the tree of the
+// given root with value. It returns false if value is empty, or is not a
+// valid input of the hash.
+func (v *Verifier) VerifyProof(root []byte, key Key, value []byte, proof *Proof) bool {
+	if len(value) == 0 {
+		return false
+	}
+	leaf, err := leafSum(v.hash, &key, value)
+	if err != nil {
+		return false
+	}
+	return v.verify(root, &key, leaf, proof)
+}
+
+// VerifyExclusionProof returns true if proof proves that key is not in the
+// tree of the given root.
+func (v *Verifier) VerifyExclusionProof(root []byte, key Key, proof *Proof) bool {
+	return v.verify(root, &key, make([]byte, v.hash.Size()), proof)
+}
+
+// VerifyProof is Verifier.VerifyProof with a new verifier using h. To verify
+// many proofs, use a Verifier instead.
+func VerifyProof(h hash.Hash, root []byte, key Key, value []byte, proof *Proof) bool {
+	return NewVerifier(h).VerifyProof(root, key, value, proof)
+}
+
+// VerifyExclusionProof is Verifier.VerifyExclusionProof with a new verifier
+// using h. To verify many proofs, use a Verifier instead.
+func VerifyExclusionProof(h hash.Hash, root []byte, key Key, proof *Proof) bool {
+	return NewVerifier(h).VerifyExclusionProof(root, key, proof)
+}
+
+// verify recomputes the root from the leaf hash and the siblings
+func (v *Verifier) verify(root []byte, key *Key, leaf []byte, proof *Proof) bool {
+	nbSiblings := 0
+	for i := range proof.Bitmap {
+		nbSiblings += bits.OnesCount8(proof.Bitmap[i])
+	}
+	if nbSiblings != len(proof.Siblings) {
+		return false
+	}
+
+	cur := leaf
+	j := len(proof.Siblings) - 1
+	for depth := Depth - 1; depth >= 0; depth-- {
+		sibling := v.defaults[Depth-depth-1]
+		if proof.hasSibling(depth) {
+			sibling = proof.Siblings[j]
+			j--
+		}
+		if key.bit(depth) == 0 {
+			cur = nodeSum(v.hash, cur, sibling)
+		} else {
+			cur = nodeSum(v.hash, sibling, cur)
+		}
+	}
+	return bytes.Equal(cur, root)
+}
+
+// WriteTo writes the binary encoding of the proof to w: the bitmap, the size
+// of the siblings on 8 bytes in big endian, and the siblings.
+// It returns the number of bytes written.
+func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
+	var size uint64
+	if len(proof.Siblings) > 0 {
+		size = uint64(len(proof.Siblings[0]))
+	}
+	var bSize [8]byte
+	binary.BigEndian.PutUint64(bSize[:], size)
+
+	var n int64
+	for _, b := range append([][]byte{proof.Bitmap[:], bSize[:]}, proof.Siblings...) {
+		m, err := w.Write(b)
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// ReadFrom reads a proof encoded by WriteTo from r.
+// It returns the number of bytes read.
+func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	read := func(b []byte) error {
+		m, err := io.ReadFull(r, b)
+		n += int64(m)
+		return err
+	}
+
+	if err := read(proof.Bitmap[:]); err != nil {
+		return n, err
+	}
+	var bSize [8]byte
+	if err := read(bSize[:]); err != nil {
+		return n, err
+	}
+	size := binary.BigEndian.Uint64(bSize[:])
+	nbSiblings := 0
+	for i := range proof.Bitmap {
+		nbSiblings += bits.OnesCount8(proof.Bitmap[i])
+	}
+	// digests larger than 1KiB are not expected
+	if size > 1024 || (size == 0 && nbSiblings > 0) {
+		return n, errInvalidProof
+	}
+
+	proof.Siblings = make([][]byte, nbSiblings)
+	for i := range proof.Siblings {
+		proof.Siblings[i] = make([]byte, size)
+		if err := read(proof.Siblings[i]); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,378 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package smt implements a sparse Merkle tree over a 256-bit key space.
+//
+// Each key is a path from the root to its own leaf, the leaves of the absent
+// keys being empty. The root of an empty subtree of height h is a constant,
+// so that only the non-empty subtrees are stored, in a pluggable Store. A
+// subtree holding a single key is stored as that key and its value.
+//
+// The tree supports updates, deletions and batches of them, and proves that
+// a key is in the tree with a given value (inclusion) or that it is not
+// (exclusion). The siblings that are roots of empty subtrees are omitted from
+// the proofs.
+//
+// Any hash.Hash can be used, including the field-native hashes of the hash
+// package. The leaves are hashed as H(0x01, key[:16], key[16:], value), each
+// of the first three inputs being written in a separate call, and the nodes
+// as H(left, right). With a field-native hash, the values must hence be
+// encodings of field elements, for instance hashes of the actual data.
+package smt
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"sort"
+)
+
+const (
+	// Depth of the tree
+	Depth = 256
+
+	// KeySize size in bytes of the keys
+	KeySize = Depth / 8
+)
+
+// Key of a leaf. The most significant bit of key[0] chooses the child of the
+// root.
+type Key [KeySize]byte
+
+// bit returns the bit of k choosing the child of the node at depth
+func (k *Key) bit(depth int) int {
+	return int(k[depth/8]>>(7-depth%8)) & 1
+}
+
+// types of the stored nodes
+const (
+	internalNode byte = iota
+	leafNode
+)
+
+var (
+	ErrLengthMismatch = errors.New("the number of keys and of values differ")
+	errInvalidNode    = errors.New("invalid stored node")
+)
+
+// SMT is a sparse Merkle tree. It is not safe for concurrent use.
+type SMT struct {
+	hash  hash.Hash
+	store Store
+	root  []byte
+
+	// defaults[h] is the root of the empty subtree of height h
+	defaults [][]byte
+}
+
+// kv is a key and its value. An empty value stands for an absent key.
+type kv struct {
+	key   Key
+	value []byte
+}
+
+// node is the hash of a subtree, along with its only key if the subtree
+// holds exactly one key
+type node struct {
+	hash []byte
+	leaf *kv
+}
+
+// New returns an empty sparse Merkle tree, storing its nodes in store
+func New(h hash.Hash, store Store) *SMT {
+	t := &SMT{
+		hash:     h,
+		store:    store,
+		defaults: defaultHashes(h),
+	}
+	t.root = t.defaults[Depth]
+	return t
+}
+
+// Open returns the sparse Merkle tree of the given root, whose nodes are in
+// store
+func Open(h hash.Hash, store Store, root []byte) *SMT {
+	t := New(h, store)
+	t.root = append([]byte(nil), root...)
+	return t
+}
+
+// defaultHashes returns the roots of the empty subtrees of each height
+func defaultHashes(h hash.Hash) [][]byte {
+	defaults := make([][]byte, Depth+1)
+	defaults[0] = make([]byte, h.Size())
+	for i := 1; i <= Depth; i++ {
+		defaults[i] = nodeSum(h, defaults[i-1], defaults[i-1])
+	}
+	return defaults
+}
+
+// Root returns the root of the tree
+func (t *SMT) Root() []byte {
+	return append([]byte(nil), t.root...)
+}
+
+// Get returns the value of key, nil if key is not in the tree
+func (t *SMT) Get(key Key) ([]byte, error) {
+	hash := t.root
+	for depth := 0; depth <= Depth; depth++ {
+		left, right, leaf, err := t.readNode(hash, Depth-depth)
+		if err != nil {
+			return nil, err
+		}
+		if leaf != nil {
+			if leaf.key == key {
+				return leaf.value, nil
+			}
+			return nil, nil
+		}
+		if left == nil {
+			// empty subtree
+			return nil, nil
+		}
+		if key.bit(depth) == 0 {
+			hash = left
+		} else {
+			hash = right
+		}
+	}
+	// the nodes at depth Depth are leaves
+	return nil, errInvalidNode
+}
+
+// Update sets the value of key. An empty value deletes key.
+func (t *SMT) Update(key Key, value []byte) error {
+	return t.BatchUpdate([]Key{key}, [][]byte{value})
+}
+
+// Delete removes key from the tree
+func (t *SMT) Delete(key Key) error {
+	return t.Update(key, nil)
+}
+
+// BatchUpdate sets the values of keys, empty values deleting their keys. The
+// nodes shared by the paths of the keys are updated once. If a key appears
+// several times, its last value is kept.
+func (t *SMT) BatchUpdate(keys []Key, values [][]byte) error {
+	if len(keys) != len(values) {
+		return ErrLengthMismatch
+	}
+	updates := make([]kv, len(keys))
+	for i := range keys {
+		updates[i] = kv{key: keys[i], value: values[i]}
+	}
+	sort.SliceStable(updates, func(i, j int) bool {
+		return bytes.Compare(updates[i].key[:], updates[j].key[:]) < 0
+	})
+	// keep the last value of each key
+	n := 0
+	for i := range updates {
+		if n > 0 && updates[n-1].key == updates[i].key {
+			n--
+		}
+		updates[n] = updates[i]
+		n++
+	}
+	updates = updates[:n]
+
+	root, err := t.update(t.root, Depth, updates)
+	if err != nil {
+		return err
+	}
+	if root.leaf != nil {
+		if err := t.store.Set(root.hash, encodeLeaf(root.leaf)); err != nil {
+			return err
+		}
+	}
+	t.root = root.hash
+	return nil
+}
+
+// update applies the updates, sorted by key, to the subtree of the given
+// height and root hash, and returns the new root of the subtree.
+//
+// The stored nodes are the internal nodes, the single key subtrees whose
+// parent is an internal node, and the root. update removes the nodes it
+// replaces, and stores the internal nodes it creates along with their single
+// key children. The caller stores the returned node if needed.
+func (t *SMT) update(hash []byte, height int, updates []kv) (node, error) {
+	if len(updates) == 0 {
+		_, _, leaf, err := t.readNode(hash, height)
+		return node{hash: hash, leaf: leaf}, err
+	}
+
+	left, right, leaf, err := t.readNode(hash, height)
+	if err != nil {
+		return node{}, err
+	}
+	if leaf != nil {
+		// the key of a single key subtree is moved down along with the
+		// updates, unless it is updated
+		i := sort.Search(len(updates), func(i int) bool {
+			return bytes.Compare(updates[i].key[:], leaf.key[:]) >= 0
+		})
+		if i == len(updates) || updates[i].key != leaf.key {
+			updates = append(updates[:i:i], append([]kv{*leaf}, updates[i:]...)...)
+		}
+		left, right = nil, nil
+	}
+
+	var res node
+	if height == 0 {
+		// the keys are deduplicated, and all equal at this height
+		u := updates[0]
+		if len(u.value) == 0 {
+			res.hash = t.defaults[0]
+		} else {
+			if res.hash, err = leafSum(t.hash, &u.key, u.value); err != nil {
+				return node{}, err
+			}
+			res.leaf = &u
+		}
+	} else {
+		if left == nil {
+			left, right = t.defaults[height-1], t.defaults[height-1]
+		}
+		depth := Depth - height
+		i := sort.Search(len(updates), func(i int) bool {
+			return updates[i].key.bit(depth) == 1
+		})
+		l, err := t.update(left, height-1, updates[:i])
+		if err != nil {
+			return node{}, err
+		}
+		r, err := t.update(right, height-1, updates[i:])
+		if err != nil {
+			return node{}, err
+		}
+		if res, err = t.join(l, r, height); err != nil {
+			return node{}, err
+		}
+	}
+
+	if !bytes.Equal(hash, res.hash) && !bytes.Equal(hash, t.defaults[height]) {
+		if err := t.store.Delete(hash); err != nil {
+			return node{}, err
+		}
+	}
+	return res, nil
+}
+
+// join returns the node of the given height whose children are l and r
+func (t *SMT) join(l, r node, height int) (node, error) {
+	lEmpty := bytes.Equal(l.hash, t.defaults[height-1])
+	rEmpty := bytes.Equal(r.hash, t.defaults[height-1])
+	switch {
+	case lEmpty && rEmpty:
+		return node{hash: t.defaults[height]}, nil
+	case rEmpty && l.leaf != nil:
+		// the single key subtree moves up, its parent is no longer internal
+		if err := t.store.Delete(l.hash); err != nil {
+			return node{}, err
+		}
+		return node{hash: nodeSum(t.hash, l.hash, r.hash), leaf: l.leaf}, nil
+	case lEmpty && r.leaf != nil:
+		if err := t.store.Delete(r.hash); err != nil {
+			return node{}, err
+		}
+		return node{hash: nodeSum(t.hash, l.hash, r.hash), leaf: r.leaf}, nil
+	}
+
+	for _, child := range []node{l, r} {
+		if child.leaf != nil {
+			if err := t.store.Set(child.hash, encodeLeaf(child.leaf)); err != nil {
+				return node{}, err
+			}
+		}
+	}
+	res := node{hash: nodeSum(t.hash, l.hash, r.hash)}
+	record := make([]byte, 0, 1+len(l.hash)+len(r.hash))
+	record = append(record, internalNode)
+	record = append(record, l.hash...)
+	record = append(record, r.hash...)
+	if err := t.store.Set(res.hash, record); err != nil {
+		return node{}, err
+	}
+	return res, nil
+}
+
+// readNode returns the children of the internal node of the given hash, or
+// its key and value if it is a single key subtree. All the results are nil if
+// the subtree is empty.
+func (t *SMT) readNode(hash []byte, height int) (left, right []byte, leaf *kv, err error) {
+	if bytes.Equal(hash, t.defaults[height]) {
+		return
+	}
+	record, err := t.store.Get(hash)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	size := t.hash.Size()
+	switch {
+	case len(record) == 1+2*size && record[0] == internalNode && height > 0:
+		return record[1 : 1+size], record[1+size:], nil, nil
+	case len(record) > 1+KeySize && record[0] == leafNode:
+		leaf = &kv{value: record[1+KeySize:]}
+		copy(leaf.key[:], record[1:1+KeySize])
+		return nil, nil, leaf, nil
+	}
+	return nil, nil, nil, errInvalidNode
+}
+
+func encodeLeaf(leaf *kv) []byte {
+	record := make([]byte, 0, 1+KeySize+len(leaf.value))
+	record = append(record, leafNode)
+	record = append(record, leaf.key[:]...)
+	return append(record, leaf.value...)
+}
+
+// leafHash returns the root of the subtree of the given height holding only
+// key
+func leafHash(h hash.Hash, defaults [][]byte, key *Key, value []byte, height int) ([]byte, error) {
+	res, err := leafSum(h, key, value)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < height; i++ {
+		if key.bit(Depth-1-i) == 0 {
+			res = nodeSum(h, res, defaults[i])
+		} else {
+			res = nodeSum(h, defaults[i], res)
+		}
+	}
+	return res, nil
+}
+
+// leafSum returns H(0x01, key[:16], key[16:], value)
+func leafSum(h hash.Hash, key *Key, value []byte) ([]byte, error) {
+	h.Reset()
+	for _, d := range [][]byte{{leafNode}, key[:KeySize/2], key[KeySize/2:], value} {
+		if _, err := h.Write(d); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// nodeSum returns H(a, b), a and b being digests
+func nodeSum(h hash.Hash, a, b []byte) []byte {
+	h.Reset()
+	for _, d := range [][]byte{a, b} {
+		// digests are valid inputs
+		if _, err := h.Write(d); err != nil {
+			panic(err)
+		}
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,269 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package smt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+	"math/rand"
+	"sort"
+	"testing"
+
+	gchash "github.com/consensys/gnark-crypto/hash"
+	"github.com/stretchr/testify/require"
+)
+
+// naiveRoot computes the root of the tree holding the given keys, sorted,
+// without any shortcut
+func naiveRoot(h hash.Hash, defaults [][]byte, keys []Key, values map[Key][]byte, height int) []byte {
+	if len(keys) == 0 {
+		return defaults[height]
+	}
+	if height == 0 {
+		res, err := leafSum(h, &keys[0], values[keys[0]])
+		if err != nil {
+			panic(err)
+		}
+		return res
+	}
+	depth := Depth - height
+	i := sort.Search(len(keys), func(i int) bool { return keys[i].bit(depth) == 1 })
+	return nodeSum(h,
+		naiveRoot(h, defaults, keys[:i], values, height-1),
+		naiveRoot(h, defaults, keys[i:], values, height-1))
+}
+
+func sortedKeys(m map[Key][]byte) []Key {
+	keys := make([]Key, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i][:], keys[j][:]) < 0 })
+	return keys
+}
+
+// randomKey returns a random key, sharing a prefix with the previous one
+// from time to time so that the paths diverge deep in the tree
+func randomKey(rnd *rand.Rand, previous *Key) Key {
+	var k Key
+	rnd.Read(k[:])
+	if previous != nil && rnd.Intn(3) == 0 {
+		copy(k[:], previous[:rnd.Intn(KeySize)])
+		if rnd.Intn(2) == 0 {
+			k = *previous
+			k[KeySize-1] ^= 1
+		}
+	}
+	return k
+}
+
+func TestSMT(t *testing.T) {
+	assert := require.New(t)
+	rnd := rand.New(rand.NewSource(0)) //#nosec G404 weak rng is fine for tests
+
+	h := sha256.New()
+	store := NewMemoryStore()
+	tree := New(h, store)
+	defaults := defaultHashes(h)
+	emptyRoot := tree.Root()
+
+	reference := make(map[Key][]byte)
+	var keys []Key
+	for i := 0; i < 200; i++ {
+		var previous *Key
+		if len(keys) > 0 {
+			previous = &keys[len(keys)-1]
+		}
+		key := randomKey(rnd, previous)
+		value := make([]byte, 1+rnd.Intn(40))
+		rnd.Read(value)
+
+		// insert, overwrite or delete
+		switch {
+		case len(keys) > 0 && rnd.Intn(4) == 0:
+			key = keys[rnd.Intn(len(keys))]
+			assert.NoError(tree.Delete(key))
+			delete(reference, key)
+		case len(keys) > 0 && rnd.Intn(4) == 0:
+			key = keys[rnd.Intn(len(keys))]
+			fallthrough
+		default:
+			assert.NoError(tree.Update(key, value))
+			reference[key] = value
+			keys = append(keys, key)
+		}
+
+		assert.Equal(naiveRoot(h, defaults, sortedKeys(reference), reference, Depth), tree.Root())
+	}
+
+	verifier := tree.Verifier()
+	for _, key := range keys {
+		value, err := tree.Get(key)
+		assert.NoError(err)
+		assert.Equal(reference[key], value)
+
+		proof, err := tree.Prove(key)
+		assert.NoError(err)
+		if expected, ok := reference[key]; ok {
+			assert.True(verifier.VerifyProof(tree.Root(), key, expected, &proof))
+			assert.False(verifier.VerifyExclusionProof(tree.Root(), key, &proof))
+			assert.False(verifier.VerifyProof(tree.Root(), key, append(expected, 0), &proof))
+			assert.True(VerifyProof(h, tree.Root(), key, expected, &proof))
+		} else {
+			assert.True(verifier.VerifyExclusionProof(tree.Root(), key, &proof))
+			assert.True(VerifyExclusionProof(h, tree.Root(), key, &proof))
+		}
+	}
+
+	// the tree can be reopened from its root
+	reopened := Open(sha256.New(), store, tree.Root())
+	for key, expected := range reference {
+		value, err := reopened.Get(key)
+		assert.NoError(err)
+		assert.Equal(expected, value)
+	}
+
+	// deleting all the keys empties the store
+	for key := range reference {
+		assert.NoError(tree.Delete(key))
+	}
+	assert.Equal(emptyRoot, tree.Root())
+	assert.Equal(0, store.Len())
+}
+
+func TestBatchUpdate(t *testing.T) {
+	assert := require.New(t)
+	rnd := rand.New(rand.NewSource(1)) //#nosec G404 weak rng is fine for tests
+
+	sequential := New(sha256.New(), NewMemoryStore())
+	batchStore := NewMemoryStore()
+	batch := New(sha256.New(), batchStore)
+
+	for round := 0; round < 5; round++ {
+		keys := make([]Key, 50)
+		values := make([][]byte, len(keys))
+		for i := range keys {
+			var previous *Key
+			if i > 0 {
+				previous = &keys[i-1]
+			}
+			keys[i] = randomKey(rnd, previous)
+			if round > 0 && rnd.Intn(5) == 0 {
+				// deletion
+				continue
+			}
+			values[i] = make([]byte, 8)
+			rnd.Read(values[i])
+		}
+		// a key updated twice keeps its last value
+		keys[7] = keys[3]
+
+		for i := range keys {
+			assert.NoError(sequential.Update(keys[i], values[i]))
+		}
+		assert.NoError(batch.BatchUpdate(keys, values))
+		assert.Equal(sequential.Root(), batch.Root())
+	}
+
+	assert.ErrorIs(batch.BatchUpdate(make([]Key, 2), make([][]byte, 1)), ErrLengthMismatch)
+}
+
+func TestFieldNativeHash(t *testing.T) {
+	assert := require.New(t)
+
+	h := gchash.MIMC_BN254.New()
+	tree := New(h, NewMemoryStore())
+
+	// values are encodings of field elements
+	var k1, k2, k3 Key
+	k1[0] = 0xff
+	k2[0] = 0xff
+	k2[KeySize-1] = 1
+	k3[5] = 42
+	v1 := make([]byte, h.BlockSize())
+	v1[h.BlockSize()-1] = 1
+	v2 := make([]byte, 2*h.BlockSize())
+	v2[0] = 3
+
+	assert.NoError(tree.BatchUpdate([]Key{k1, k2}, [][]byte{v1, v2}))
+
+	for _, c := range []struct {
+		key   Key
+		value []byte
+	}{{k1, v1}, {k2, v2}} {
+		proof, err := tree.Prove(c.key)
+		assert.NoError(err)
+		assert.True(VerifyProof(h, tree.Root(), c.key, c.value, &proof))
+	}
+	proof, err := tree.Prove(k3)
+	assert.NoError(err)
+	assert.True(VerifyExclusionProof(h, tree.Root(), k3, &proof))
+
+	// values that are not field elements are rejected by the hash
+	invalid := bytes.Repeat([]byte{0xff}, h.BlockSize())
+	assert.Error(tree.Update(k3, invalid))
+}
+
+func TestProofSerialization(t *testing.T) {
+	assert := require.New(t)
+	rnd := rand.New(rand.NewSource(2)) //#nosec G404 weak rng is fine for tests
+
+	h := sha256.New()
+	tree := New(h, NewMemoryStore())
+	keys := make([]Key, 64)
+	values := make([][]byte, len(keys))
+	for i := range keys {
+		keys[i] = randomKey(rnd, nil)
+		values[i] = []byte{byte(i + 1)}
+	}
+	assert.NoError(tree.BatchUpdate(keys, values))
+
+	proof, err := tree.Prove(keys[5])
+	assert.NoError(err)
+	// the empty siblings are compressed
+	assert.Less(len(proof.Siblings), 16)
+
+	var buf bytes.Buffer
+	written, err := proof.WriteTo(&buf)
+	assert.NoError(err)
+	assert.Equal(int64(Depth/8+8+len(proof.Siblings)*h.Size()), written)
+
+	verifier := NewVerifier(h)
+	var decoded Proof
+	read, err := decoded.ReadFrom(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Equal(written, read)
+	assert.Equal(proof, decoded)
+	assert.True(verifier.VerifyProof(tree.Root(), keys[5], values[5], &decoded))
+
+	_, err = decoded.ReadFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	assert.Error(err)
+
+	// tampered proofs
+	decoded.Siblings = decoded.Siblings[1:]
+	assert.False(verifier.VerifyProof(tree.Root(), keys[5], values[5], &decoded))
+	assert.False(verifier.VerifyProof(tree.Root(), keys[6], values[5], &proof))
+}
+
+func BenchmarkUpdate(b *testing.B) {
+	rnd := rand.New(rand.NewSource(0)) //#nosec G404 weak rng is fine for tests
+	tree := New(gchash.MIMC_BN254.New(), NewMemoryStore())
+	value := make([]byte, 32)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = tree.Update(randomKey(rnd, nil), value)
+	}
+}
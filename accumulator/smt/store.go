@@ -0,0 +1,76 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package smt
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNodeNotFound is returned by a Store when the requested node is missing
+var ErrNodeNotFound = errors.New("node not found")
+
+// Store persists the nodes of a sparse Merkle tree, indexed by their hash.
+// Implementations must return ErrNodeNotFound when a node is missing.
+type Store interface {
+	Get(key []byte) ([]byte, error)
+	Set(key, value []byte) error
+	Delete(key []byte) error
+}
+
+// MemoryStore is an in-memory Store, safe for concurrent use
+type MemoryStore struct {
+	lock  sync.RWMutex
+	nodes map[string][]byte
+}
+
+// NewMemoryStore returns an empty in-memory Store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{nodes: make(map[string][]byte)}
+}
+
+// Get returns the node stored under key
+func (s *MemoryStore) Get(key []byte) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	value, ok := s.nodes[string(key)]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+	return value, nil
+}
+
+// Set stores a copy of value under key
+func (s *MemoryStore) Set(key, value []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.nodes[string(key)] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete removes the node stored under key, if any
+func (s *MemoryStore) Delete(key []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.nodes, string(key))
+	return nil
+}
+
+// Len returns the number of stored nodes
+func (s *MemoryStore) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.nodes)
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package bls provides BLS signature schemes on the bls12-377 curve.
+//
+// Both variants of the IETF draft are implemented:
+//   - minimal-pubkey-size (min-pk): public keys in G1, signatures in G2;
+//   - minimal-signature-size (min-sig): public keys in G2, signatures in G1.
+//
+// Each variant comes with the three schemes of the draft (basic, message
+// augmentation and proof of possession), selected through a [Suite]. The
+// domain separation tags follow the ciphersuite naming of the draft and the
+// hash to curve is the one of the bls12377 package.
+//
+// Documentation:
+//   - IETF draft: https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05
+//   - Boneh, Lynn, Shacham: https://www.iacr.org/archive/asiacrypt2001/22480516.pdf
+//   - Boneh, Drijvers, Neven: https://eprint.iacr.org/2018/483.pdf
+//
+// # See also
+//
+// https://en.wikipedia.org/wiki/BLS_digital_signature
+package bls
@@ -0,0 +1,351 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fri
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrNoColumns     = errors.New("at least one column is needed")
+	ErrPointInDomain = errors.New("the evaluation point belongs to the evaluation domain")
+	ErrDeepQuotient  = errors.New("the committed rows do not match the DEEP quotient")
+)
+
+// CommittedColumns are polynomials (the columns) whose evaluations on the domain
+// of a Scheme are committed row-wise: the i-th leaf of the Merkle tree is the
+// row p₀(ωⁱ) ∥ p₁(ωⁱ) ∥ .. ∥ pₘ₋₁(ωⁱ).
+type CommittedColumns struct {
+
+	// columns polynomials in canonical basis
+	columns [][]fr.Element
+
+	// evals[c] evaluation of the c-th column on the domain, in natural order
+	evals [][]fr.Element
+
+	tree *merkletree.RetainedTree
+}
+
+// BatchProof proves the evaluations of committed columns at out of domain
+// points.
+//
+// Using a random γ, the columns and their claimed evaluations are combined in
+// the DEEP quotient
+//
+//	g(X) = ∑ₗ∑꜀ γˡᵐ⁺ᶜ (p꜀(X)-p꜀(zₗ))/(X-zₗ)
+//
+// (g = ∑꜀ γᶜp꜀ when there is no point), which is a polynomial of degree < size
+// if and only if the claims are correct. The proof of proximity of g is
+// completed by the rows of the columns at the queried positions, from which
+// the verifier recomputes g.
+type BatchProof struct {
+
+	// Evaluations[l][c] value of the c-th column at the l-th point
+	Evaluations [][]fr.Element
+
+	// Rows opening of the rows of the columns hit by the queries of the proof
+	// of proximity of g
+	Rows merkletree.MultiProof
+
+	// Proof proof of proximity of g
+	Proof Proof
+}
+
+// CommitColumns commits to the columns, given in canonical basis. All the
+// columns are padded to the size of the scheme.
+func (s *Scheme) CommitColumns(columns [][]fr.Element) (*CommittedColumns, error) {
+
+	if len(columns) == 0 {
+		return nil, ErrNoColumns
+	}
+
+	res := &CommittedColumns{
+		columns: columns,
+		evals:   make([][]fr.Element, len(columns)),
+	}
+	for c := range columns {
+		if uint64(len(columns[c])) > s.size {
+			return nil, ErrPolySize
+		}
+		res.evals[c] = make([]fr.Element, s.domain.Cardinality)
+		copy(res.evals[c], columns[c])
+		s.domain.FFT(res.evals[c], fft.DIF)
+		fft.BitReverse(res.evals[c])
+	}
+
+	rows := make([][]byte, s.domain.Cardinality)
+	for i := range rows {
+		rows[i] = make([]byte, 0, len(columns)*fr.Bytes)
+		for c := range columns {
+			b := res.evals[c][i].Bytes()
+			rows[i] = append(rows[i], b[:]...)
+		}
+	}
+	res.tree = merkletree.NewRetainedTree(s.h, rows)
+
+	return res, nil
+}
+
+// Root returns the Merkle root of the rows of the columns
+func (cc *CommittedColumns) Root() []byte {
+	return cc.tree.Root()
+}
+
+// BuildBatchProof evaluates the committed columns at points and proves the
+// evaluations. The points must be derived by the caller after the commitment
+// (for instance by binding Root() to a Fiat Shamir transcript), and must lie
+// outside the evaluation domain.
+func (s *Scheme) BuildBatchProof(cc *CommittedColumns, points []fr.Element) (BatchProof, error) {
+
+	if err := s.checkOutOfDomain(points); err != nil {
+		return BatchProof{}, err
+	}
+
+	var proof BatchProof
+	proof.Evaluations = make([][]fr.Element, len(points))
+	for l := range points {
+		proof.Evaluations[l] = make([]fr.Element, len(cc.columns))
+		for c, p := range cc.columns {
+			for k := len(p) - 1; k >= 0; k-- {
+				proof.Evaluations[l][c].Mul(&proof.Evaluations[l][c], &points[l]).
+					Add(&proof.Evaluations[l][c], &p[k])
+			}
+		}
+	}
+
+	gamma, err := s.deriveBatchChallenge(cc.Root(), points, proof.Evaluations)
+	if err != nil {
+		return BatchProof{}, err
+	}
+
+	// evaluate the DEEP quotient on the domain, the denominators are batch inverted
+	n := int(s.domain.Cardinality)
+	g := make([]fr.Element, n)
+	row := make([]fr.Element, len(cc.columns))
+	if len(points) == 0 {
+		for i := 0; i < n; i++ {
+			for c := range row {
+				row[c] = cc.evals[c][i]
+			}
+			g[i] = s.deepQuotient(row, nil, points, proof.Evaluations, gamma)
+		}
+	} else {
+		denominators := make([]fr.Element, n*len(points))
+		var x fr.Element
+		x.SetOne()
+		for i := 0; i < n; i++ {
+			for l := range points {
+				denominators[i*len(points)+l].Sub(&x, &points[l])
+			}
+			x.Mul(&x, &s.domain.Generator)
+		}
+		denominators = fr.BatchInvert(denominators)
+		for i := 0; i < n; i++ {
+			for c := range row {
+				row[c] = cc.evals[c][i]
+			}
+			g[i] = s.deepQuotient(row, denominators[i*len(points):(i+1)*len(points)], points, proof.Evaluations, gamma)
+		}
+	}
+
+	// the proof of proximity is bound to γ, hence to the commitment and the
+	// claims, so that the queries are not known before the columns are committed
+	var queries []uint64
+	proof.Proof, queries, err = s.proveEvaluations(g, gamma.Marshal())
+	if err != nil {
+		return BatchProof{}, err
+	}
+
+	// the verifier needs the full fibers of the first folding
+	proof.Rows, err = cc.tree.ProveMulti(s.fiberPositions(queries))
+	if err != nil {
+		return BatchProof{}, err
+	}
+
+	return proof, nil
+}
+
+// VerifyBatchProof verifies that the columns committed in root evaluate to
+// proof.Evaluations at points.
+func (s *Scheme) VerifyBatchProof(root []byte, points []fr.Element, proof BatchProof) error {
+
+	if err := s.checkOutOfDomain(points); err != nil {
+		return err
+	}
+	if len(proof.Evaluations) != len(points) || len(proof.Rows.Leaves) == 0 ||
+		proof.Rows.NumLeaves != s.domain.Cardinality {
+		return ErrProofShape
+	}
+	nbColumns := len(proof.Rows.Leaves[0]) / fr.Bytes
+	if nbColumns == 0 {
+		return ErrProofShape
+	}
+	for l := range proof.Evaluations {
+		if len(proof.Evaluations[l]) != nbColumns {
+			return ErrProofShape
+		}
+	}
+
+	gamma, err := s.deriveBatchChallenge(root, points, proof.Evaluations)
+	if err != nil {
+		return err
+	}
+	queries, err := s.verify(proof.Proof, gamma.Marshal())
+	if err != nil {
+		return err
+	}
+
+	if !merkletree.VerifyMultiProof(s.h, root, &proof.Rows) {
+		return ErrMerklePath
+	}
+	rows := make(map[uint64][]fr.Element, len(proof.Rows.Indices))
+	for i, index := range proof.Rows.Indices {
+		if len(proof.Rows.Leaves[i]) != nbColumns*fr.Bytes {
+			return ErrProofShape
+		}
+		row := make([]fr.Element, nbColumns)
+		for c := range row {
+			if err := row[c].SetBytesCanonical(proof.Rows.Leaves[i][c*fr.Bytes : (c+1)*fr.Bytes]); err != nil {
+				return ErrProofShape
+			}
+		}
+		rows[index] = row
+	}
+
+	// the fibers of the first folding were checked against the commitment of g by
+	// verify, they must agree with the rows
+	opening := &proof.Proof.Openings[0]
+	fibers := make(map[uint64][]fr.Element, len(opening.Indices))
+	for l, index := range opening.Indices {
+		fibers[index], err = s.parseLeaf(opening.Leaves[l])
+		if err != nil {
+			return err
+		}
+	}
+
+	nbFibers := s.domain.Cardinality / uint64(s.foldingFactor)
+	denominators := make([]fr.Element, len(points))
+	for _, position := range queries {
+		j := position % nbFibers
+		for t := 0; t < s.foldingFactor; t++ {
+			i := j + uint64(t)*nbFibers
+			row, ok := rows[i]
+			if !ok {
+				return ErrProofShape
+			}
+			var x fr.Element
+			x.Exp(s.domain.Generator, new(big.Int).SetUint64(i))
+			for l := range points {
+				denominators[l].Sub(&x, &points[l]).Inverse(&denominators[l])
+			}
+			g := s.deepQuotient(row, denominators, points, proof.Evaluations, gamma)
+			if !g.Equal(&fibers[j][t]) {
+				return ErrDeepQuotient
+			}
+		}
+	}
+
+	return nil
+}
+
+// deepQuotient returns g(x) from the row of the columns at x, where
+// denominators[l] = 1/(x-zₗ).
+func (s *Scheme) deepQuotient(row, denominators, points []fr.Element, evaluations [][]fr.Element, gamma fr.Element) fr.Element {
+
+	var res, acc, tmp fr.Element
+	acc.SetOne()
+	if len(points) == 0 {
+		for c := range row {
+			tmp.Mul(&row[c], &acc)
+			res.Add(&res, &tmp)
+			acc.Mul(&acc, &gamma)
+		}
+		return res
+	}
+
+	for l := range points {
+		var sum fr.Element
+		for c := range row {
+			tmp.Sub(&row[c], &evaluations[l][c]).Mul(&tmp, &acc)
+			sum.Add(&sum, &tmp)
+			acc.Mul(&acc, &gamma)
+		}
+		sum.Mul(&sum, &denominators[l])
+		res.Add(&res, &sum)
+	}
+	return res
+}
+
+// fiberPositions returns the positions in the domain of the fibers of the first
+// folding containing the queries
+func (s *Scheme) fiberPositions(queries []uint64) []uint64 {
+	nbFibers := s.domain.Cardinality / uint64(s.foldingFactor)
+	res := make([]uint64, 0, len(queries)*s.foldingFactor)
+	for _, position := range queries {
+		j := position % nbFibers
+		for t := 0; t < s.foldingFactor; t++ {
+			res = append(res, j+uint64(t)*nbFibers)
+		}
+	}
+	return res
+}
+
+// checkOutOfDomain returns an error if one of the points is in the evaluation
+// domain, that is zᴺ = 1
+func (s *Scheme) checkOutOfDomain(points []fr.Element) error {
+	bCardinality := new(big.Int).SetUint64(s.domain.Cardinality)
+	for l := range points {
+		var zn fr.Element
+		zn.Exp(points[l], bCardinality)
+		if zn.IsOne() {
+			return ErrPointInDomain
+		}
+	}
+	return nil
+}
+
+// deriveBatchChallenge derives γ from the commitment to the columns and the
+// claimed evaluations
+func (s *Scheme) deriveBatchChallenge(root []byte, points []fr.Element, evaluations [][]fr.Element) (fr.Element, error) {
+	var res fr.Element
+	fs := fiatshamir.NewTranscript(s.h, "gamma")
+	if err := fs.Bind("gamma", root); err != nil {
+		return res, err
+	}
+	for l := range points {
+		if err := fs.Bind("gamma", points[l].Marshal()); err != nil {
+			return res, err
+		}
+		for c := range evaluations[l] {
+			if err := fs.Bind("gamma", evaluations[l][c].Marshal()); err != nil {
+				return res, err
+			}
+		}
+	}
+	b, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return res, err
+	}
+	res.SetBytes(b)
+	return res, nil
+}
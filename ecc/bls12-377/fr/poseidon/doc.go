@@ -0,0 +1,38 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package poseidon provides the Poseidon permutation and hash function
+// over the scalar field of bls12-377.
+//
+// The permutation alternates full rounds, where the S-box x ↦ x^α is applied
+// to every element of the state, and partial rounds, where it is only applied
+// to the first one. The width and the number of rounds are configurable; the
+// round constants and the Cauchy MDS matrix are derived from the Grain LFSR
+// of the reference implementation.
+//
+// On top of the permutation, the package provides a sponge hash function
+// implementing hash.Hash and a 2-to-1 compression function for Merkle trees.
+//
+// See https://eprint.iacr.org/2019/458.pdf for the specification.
+//
+// # Hash input format
+//
+// As for MiMC, the input to the hash function is a byte slice interpreted as
+// a sequence of field elements, so its length must be a multiple of the field
+// modulus size and each element must be canonical. The sequence is padded
+// with a one followed by zeros up to a multiple of the rate, so that inputs
+// of different lengths, e.g. [a] and [a, 0], have different digests.
+package poseidon
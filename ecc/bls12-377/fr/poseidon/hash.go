@@ -0,0 +1,152 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// digest represents the partial evaluation of the checksum
+// along with the params of the Poseidon sponge
+type digest struct {
+	perm      *Permutation
+	data      []fr.Element // data to hash
+	byteOrder fr.ByteOrder
+}
+
+// NewPoseidon returns a Poseidon sponge hasher.
+//
+// The first element of the state is the capacity, the Width-1 others are the
+// rate. The field elements written to the hasher are padded with a one
+// followed by zeros up to a multiple of the rate, absorbed rate by rate, and
+// the digest is the first element of the state. The hasher thus accepts
+// inputs of any length; fixed width hashing of two elements, for Merkle
+// trees, is provided by Permutation.Compress.
+func NewPoseidon(opts ...Option) hash.Hash {
+	cfg := poseidonOptions(opts...)
+	if cfg.params.Width < 2 {
+		panic("poseidon: the sponge requires a width of at least 2")
+	}
+	d := &digest{
+		perm:      NewPermutationWithParameters(cfg.params),
+		byteOrder: cfg.byteOrder,
+	}
+	d.Reset()
+	return d
+}
+
+// Reset resets the Hash to its initial state.
+func (d *digest) Reset() {
+	d.data = d.data[:0]
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (d *digest) Sum(b []byte) []byte {
+	h := d.checksum()
+	hash := h.Bytes()
+	return append(b, hash[:]...)
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *digest) Size() int {
+	return BlockSize
+}
+
+// BlockSize returns the hash's underlying block size.
+// The Write method must be able to accept any amount
+// of data, but it may operate more efficiently if all writes
+// are a multiple of the block size.
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+// Write (via the embedded io.Writer interface) adds more data to the running hash.
+//
+// Each []byte block of size BlockSize represents a fr.Element, encoded with
+// the byte order of the hasher (big endian by default).
+//
+// If len(p) is not a multiple of BlockSize and any of the []byte in p represent an integer
+// larger than fr.Modulus, this function returns an error.
+//
+// To hash arbitrary data ([]byte not representing canonical field elements) use fr.Hash first
+func (d *digest) Write(p []byte) (int, error) {
+	// as for MiMC, short inputs (e.g. Fiat-Shamir transcripts) are left-padded
+	if len(p) > 0 && len(p) < BlockSize {
+		pp := make([]byte, BlockSize)
+		copy(pp[len(pp)-len(p):], p)
+		p = pp
+	}
+
+	if len(p)%BlockSize != 0 {
+		return 0, errors.New("invalid input length: must represent a list of field elements, expects a []byte of len m*BlockSize")
+	}
+
+	for start := 0; start < len(p); start += BlockSize {
+		if elem, err := d.byteOrder.Element((*[BlockSize]byte)(p[start : start+BlockSize])); err == nil {
+			d.data = append(d.data, elem)
+		} else {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// WriteElements adds field elements to the running hash
+func (d *digest) WriteElements(elems ...fr.Element) {
+	d.data = append(d.data, elems...)
+}
+
+// checksum absorbs the data in the sponge and returns the first element of
+// the state. The data is padded with a one followed by zeros up to a multiple
+// of the rate: the padding is injective, so that inputs of different lengths
+// are absorbed differently.
+func (d *digest) checksum() fr.Element {
+	width := d.perm.params.Width
+	rate := width - 1
+	state := make([]fr.Element, width)
+
+	padded := make([]fr.Element, len(d.data)+1, len(d.data)+rate)
+	copy(padded, d.data)
+	padded[len(d.data)].SetOne()
+	for len(padded)%rate != 0 {
+		padded = append(padded, fr.Element{})
+	}
+
+	for start := 0; start < len(padded); start += rate {
+		for i := 0; i < rate; i++ {
+			state[1+i].Add(&state[1+i], &padded[start+i])
+		}
+		// the width of the permutation is checked at construction
+		_ = d.perm.Permutation(state)
+	}
+
+	return state[0]
+}
+
+// Sum computes the Poseidon hash of msg with the default parameters
+func Sum(msg []byte) ([]byte, error) {
+	d := NewPoseidon()
+	if _, err := d.Write(msg); err != nil {
+		return nil, err
+	}
+	return d.Sum(nil), nil
+}
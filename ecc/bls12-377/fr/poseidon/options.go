@@ -0,0 +1,62 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// Option defines option for altering the behavior of the Poseidon hasher.
+// See the descriptions of functions returning instances of this type for
+// particular options.
+type Option func(*poseidonConfig)
+
+type poseidonConfig struct {
+	byteOrder fr.ByteOrder
+	params    *Parameters
+}
+
+// default options
+func poseidonOptions(opts ...Option) poseidonConfig {
+	// apply options
+	opt := poseidonConfig{
+		byteOrder: fr.BigEndian,
+	}
+	for _, option := range opts {
+		option(&opt)
+	}
+	if opt.params == nil {
+		opt.params = GetDefaultParameters()
+	}
+	return opt
+}
+
+// WithByteOrder sets the byte order used to decode the input
+// in the Write method. Default is BigEndian.
+func WithByteOrder(byteOrder fr.ByteOrder) Option {
+	return func(opt *poseidonConfig) {
+		opt.byteOrder = byteOrder
+	}
+}
+
+// WithParameters sets the parameters of the underlying permutation.
+// Default is GetDefaultParameters().
+func WithParameters(params *Parameters) Option {
+	return func(opt *poseidonConfig) {
+		opt.params = params
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fieldhash "github.com/consensys/gnark-crypto/field/hash"
+)
+
+const (
+	// DefaultWidth is the width of the permutation used by the hash function
+	// and the compression function.
+	DefaultWidth = 3
+	// DefaultNbFullRounds is the number of full rounds for 128 bits of security.
+	DefaultNbFullRounds = 8
+	// DefaultNbPartialRounds is the number of partial rounds for 128 bits of security.
+	DefaultNbPartialRounds = 31
+	// SBoxDegree is the degree α of the S-box x ↦ x^α, the smallest integer
+	// such that gcd(α, r-1) = 1.
+	SBoxDegree = 17
+	// BlockSize is the size in bytes of the blocks consumed by the compression function.
+	BlockSize = fr.Bytes
+)
+
+var (
+	ErrInvalidSizebuffer = errors.New("the size of the input should match the size of the hash buffer")
+	errInvalidWidth      = errors.New("the compression function requires a width of at least 3")
+)
+
+// Parameters describe the Poseidon permutation
+type Parameters struct {
+	// Width is the number of field elements of the state
+	Width int
+
+	// NbFullRounds is the number of full rounds, split evenly at the
+	// beginning and at the end of the permutation
+	NbFullRounds int
+
+	// NbPartialRounds is the number of partial rounds, where the S-box is
+	// only applied to the first element of the state
+	NbPartialRounds int
+
+	// RoundKeys are the round constants, Width of them per round
+	RoundKeys [][]fr.Element
+
+	// MDS is the Width×Width matrix of the linear layer
+	MDS [][]fr.Element
+}
+
+// NewParameters returns the parameters of the Poseidon permutation of the
+// given width and number of rounds. The round constants and the Cauchy MDS
+// matrix are derived from the Grain LFSR as in the reference implementation,
+// so that the instances used by other implementations (e.g. circomlib) are
+// reproduced.
+func NewParameters(width, nbFullRounds, nbPartialRounds int) *Parameters {
+	p := &Parameters{
+		Width:           width,
+		NbFullRounds:    nbFullRounds,
+		NbPartialRounds: nbPartialRounds,
+	}
+	grain := fieldhash.NewGrainLFSR(fr.Modulus(), width, nbFullRounds, nbPartialRounds)
+
+	nbRounds := nbFullRounds + nbPartialRounds
+	p.RoundKeys = make([][]fr.Element, nbRounds)
+	for i := range p.RoundKeys {
+		p.RoundKeys[i] = make([]fr.Element, width)
+		for j := range p.RoundKeys[i] {
+			p.RoundKeys[i][j].SetBigInt(grain.NextFieldElement())
+		}
+	}
+
+	// M[i][j] = 1/(xᵢ+yⱼ) with distinct xᵢ, yⱼ
+	var xy []fr.Element
+	for {
+		xy = make([]fr.Element, 2*width)
+		for i := range xy {
+			xy[i].SetBigInt(grain.NextBigInt())
+		}
+		if !hasDuplicates(xy) && !hasZeroSum(xy[:width], xy[width:]) {
+			break
+		}
+	}
+	p.MDS = make([][]fr.Element, width)
+	for i := range p.MDS {
+		p.MDS[i] = make([]fr.Element, width)
+		for j := range p.MDS[i] {
+			p.MDS[i][j].Add(&xy[i], &xy[width+j])
+		}
+		p.MDS[i] = fr.BatchInvert(p.MDS[i])
+	}
+
+	return p
+}
+
+func hasDuplicates(v []fr.Element) bool {
+	seen := make(map[fr.Element]struct{}, len(v))
+	for _, e := range v {
+		if _, ok := seen[e]; ok {
+			return true
+		}
+		seen[e] = struct{}{}
+	}
+	return false
+}
+
+func hasZeroSum(x, y []fr.Element) bool {
+	var s fr.Element
+	for i := range x {
+		for j := range y {
+			if s.Add(&x[i], &y[j]).IsZero() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String returns a string representation of the parameters
+func (p *Parameters) String() string {
+	return fmt.Sprintf("Poseidon-BLS12-377[t=%d,rF=%d,rP=%d,d=%d]", p.Width, p.NbFullRounds, p.NbPartialRounds, SBoxDegree)
+}
+
+var (
+	defaultParameters     *Parameters
+	defaultParametersOnce sync.Once
+)
+
+// GetDefaultParameters returns the parameters of width DefaultWidth and
+// default number of rounds, computed once.
+func GetDefaultParameters() *Parameters {
+	defaultParametersOnce.Do(func() {
+		defaultParameters = NewParameters(DefaultWidth, DefaultNbFullRounds, DefaultNbPartialRounds)
+	})
+	return defaultParameters
+}
+
+// Permutation stores the buffer of the Poseidon permutation and provides
+// Poseidon permutation methods on the buffer
+type Permutation struct {
+	params *Parameters
+}
+
+// NewPermutation returns a new Poseidon permutation instance.
+func NewPermutation(width, nbFullRounds, nbPartialRounds int) *Permutation {
+	return NewPermutationWithParameters(NewParameters(width, nbFullRounds, nbPartialRounds))
+}
+
+// NewPermutationWithParameters returns a new Poseidon permutation instance
+// with the given parameters.
+func NewPermutationWithParameters(params *Parameters) *Permutation {
+	return &Permutation{params: params}
+}
+
+// Parameters returns the parameters of the permutation
+func (h *Permutation) Parameters() *Parameters {
+	return h.params
+}
+
+// sBox applies the S-box x ↦ x^α to x
+func (h *Permutation) sBox(x *fr.Element) {
+	var tmp fr.Element
+	tmp.Set(x)
+	x.Square(x).
+		Square(x).
+		Square(x).
+		Square(x).
+		Mul(x, &tmp)
+}
+
+// matMulMDSInPlace replaces input with M⋅input
+func (h *Permutation) matMulMDSInPlace(input []fr.Element) {
+	res := make([]fr.Element, h.params.Width)
+	var tmp fr.Element
+	for i := range res {
+		for j := range input {
+			tmp.Mul(&h.params.MDS[i][j], &input[j])
+			res[i].Add(&res[i], &tmp)
+		}
+	}
+	copy(input, res)
+}
+
+func (h *Permutation) addRoundKeyInPlace(round int, input []fr.Element) {
+	for i := range input {
+		input[i].Add(&input[i], &h.params.RoundKeys[round][i])
+	}
+}
+
+// Permutation applies the permutation on input, and stores the result in input.
+func (h *Permutation) Permutation(input []fr.Element) error {
+	if len(input) != h.params.Width {
+		return ErrInvalidSizebuffer
+	}
+
+	rf := h.params.NbFullRounds / 2
+	round := 0
+	for i := 0; i < rf; i++ {
+		h.addRoundKeyInPlace(round, input)
+		for j := range input {
+			h.sBox(&input[j])
+		}
+		h.matMulMDSInPlace(input)
+		round++
+	}
+	for i := 0; i < h.params.NbPartialRounds; i++ {
+		h.addRoundKeyInPlace(round, input)
+		h.sBox(&input[0])
+		h.matMulMDSInPlace(input)
+		round++
+	}
+	for i := 0; i < rf; i++ {
+		h.addRoundKeyInPlace(round, input)
+		for j := range input {
+			h.sBox(&input[j])
+		}
+		h.matMulMDSInPlace(input)
+		round++
+	}
+
+	return nil
+}
+
+// Compress is used in context of Merkle trees: it hashes the two children
+// left and right into their parent, as the first element of the permutation
+// of (0, left, right, 0, …). With width 3, this is the 2-to-1 Poseidon hash of
+// circomlib.
+func (h *Permutation) Compress(left []byte, right []byte) ([]byte, error) {
+	if h.params.Width < 3 {
+		return nil, errInvalidWidth
+	}
+	if len(left) != BlockSize || len(right) != BlockSize {
+		return nil, ErrInvalidSizebuffer
+	}
+	x := make([]fr.Element, h.params.Width)
+	if err := x[1].SetBytesCanonical(left); err != nil {
+		return nil, err
+	}
+	if err := x[2].SetBytesCanonical(right); err != nil {
+		return nil, err
+	}
+	if err := h.Permutation(x); err != nil {
+		return nil, err
+	}
+	res := x[0].Bytes()
+	return res[:], nil
+}
+
+// BlockSize returns the size in bytes of the inputs of Compress
+func (h *Permutation) BlockSize() int {
+	return BlockSize
+}
@@ -0,0 +1,200 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon2
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermutation(t *testing.T) {
+	assert := require.New(t)
+
+	for _, width := range []int{2, 3, 4, 8, 12} {
+		h, err := NewPermutation(width, DefaultNbFullRounds, DefaultNbPartialRounds)
+		assert.NoError(err)
+		params := h.Parameters()
+		assert.Equal(DefaultNbFullRounds+DefaultNbPartialRounds, len(params.RoundKeys))
+		assert.Equal(width, len(params.RoundKeys[0]))
+		assert.Equal(1, len(params.RoundKeys[DefaultNbFullRounds/2]))
+
+		x := make([]fr.Element, width)
+		y := make([]fr.Element, width)
+		for i := range x {
+			x[i].SetRandom()
+		}
+		copy(y, x)
+		assert.NoError(h.Permutation(x))
+		assert.NoError(h.Permutation(y))
+		assert.Equal(x, y, "the permutation should be deterministic")
+
+		y[width-1].Add(&y[width-1], &params.RoundKeys[0][0])
+		assert.NoError(h.Permutation(y))
+		assert.NotEqual(x, y)
+
+		assert.Equal(ErrInvalidSizebuffer, h.Permutation(make([]fr.Element, width+1)))
+	}
+
+	for _, width := range []int{0, 1, 5, 6} {
+		_, err := NewParameters(width, DefaultNbFullRounds, DefaultNbPartialRounds)
+		assert.Error(err)
+	}
+}
+
+func TestExternalMatrix(t *testing.T) {
+	assert := require.New(t)
+
+	// the external matrix of width 8 is circ(2M4, M4)
+	m4 := [4][4]uint64{{5, 7, 1, 3}, {4, 6, 1, 1}, {1, 3, 5, 7}, {1, 1, 4, 6}}
+	h, err := NewPermutation(8, DefaultNbFullRounds, DefaultNbPartialRounds)
+	assert.NoError(err)
+
+	x := make([]fr.Element, 8)
+	for i := range x {
+		x[i].SetRandom()
+	}
+	expected := make([]fr.Element, 8)
+	var c, tmp fr.Element
+	for i := 0; i < 8; i++ {
+		for j := 0; j < 8; j++ {
+			c.SetUint64(m4[i%4][j%4])
+			if i/4 == j/4 {
+				c.Double(&c)
+			}
+			tmp.Mul(&c, &x[j])
+			expected[i].Add(&expected[i], &tmp)
+		}
+	}
+	h.matMulExternalInPlace(x)
+	assert.Equal(expected, x)
+}
+
+func TestCompress(t *testing.T) {
+	assert := require.New(t)
+
+	var left, right fr.Element
+	left.SetRandom()
+	right.SetRandom()
+	bLeft, bRight := left.Bytes(), right.Bytes()
+
+	h, err := NewPermutation(2, DefaultNbFullRounds, DefaultNbPartialRounds)
+	assert.NoError(err)
+	res, err := h.Compress(bLeft[:], bRight[:])
+	assert.NoError(err)
+
+	x := []fr.Element{left, right}
+	assert.NoError(h.Permutation(x))
+	x[0].Add(&x[0], &left)
+	expected := x[0].Bytes()
+	assert.Equal(expected[:], res)
+
+	_, err = h.Compress(bLeft[:], bRight[1:])
+	assert.Error(err)
+}
+
+func TestSponge(t *testing.T) {
+	assert := require.New(t)
+
+	elems := make([]fr.Element, 5)
+	buf := make([]byte, 0, len(elems)*BlockSize)
+	for i := range elems {
+		elems[i].SetRandom()
+		b := elems[i].Bytes()
+		buf = append(buf, b[:]...)
+	}
+
+	// the digest does not depend on how the data is written
+	h1 := NewPoseidon2()
+	_, err := h1.Write(buf)
+	assert.NoError(err)
+	h2 := NewPoseidon2()
+	for i := 0; i < len(buf); i += BlockSize {
+		_, err = h2.Write(buf[i : i+BlockSize])
+		assert.NoError(err)
+	}
+	d1 := h1.Sum(nil)
+	assert.Equal(d1, h2.Sum(nil))
+	assert.Equal(d1, h1.Sum(nil), "Sum should not change the state")
+
+	d, err := Sum(buf)
+	assert.NoError(err)
+	assert.Equal(d1, d)
+
+	// absorbing an extra block changes the digest
+	_, err = h2.Write(buf[:BlockSize])
+	assert.NoError(err)
+	assert.NotEqual(d1, h2.Sum(nil))
+
+	h1.Reset()
+	assert.NotEqual(d1, h1.Sum(nil))
+
+	_, err = h1.Write(make([]byte, BlockSize+1))
+	assert.Error(err)
+}
+
+func TestPadding(t *testing.T) {
+	assert := require.New(t)
+
+	var a fr.Element
+	a.SetRandom()
+	zero := fr.Element{}
+
+	digest := func(elems ...fr.Element) []byte {
+		h := NewPoseidon2()
+		for i := range elems {
+			b := elems[i].Bytes()
+			_, err := h.Write(b[:])
+			assert.NoError(err)
+		}
+		return h.Sum(nil)
+	}
+
+	// trailing zeros are not absorbed as padding
+	assert.NotEqual(digest(a), digest(a, zero))
+	assert.NotEqual(digest(), digest(zero))
+	assert.NotEqual(digest(zero), digest(zero, zero))
+	assert.NotEqual(digest(), digest(zero, zero))
+
+	// nor when the input fills the rate
+	rate := DefaultWidth - 1
+	full := make([]fr.Element, rate)
+	full[0] = a
+	assert.NotEqual(digest(full...), digest(append(full, zero)...))
+}
+
+func TestPoseidon2FiatShamir(t *testing.T) {
+	fs := fiatshamir.NewTranscript(NewPoseidon2(), "c0")
+	zero := make([]byte, BlockSize)
+	require.NoError(t, fs.Bind("c0", zero))
+	_, err := fs.ComputeChallenge("c0")
+	require.NoError(t, err)
+}
+
+func BenchmarkPermutation(b *testing.B) {
+	h := NewPermutationWithParameters(GetDefaultParameters())
+	x := make([]fr.Element, DefaultWidth)
+	for i := range x {
+		x[i].SetRandom()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Permutation(x)
+	}
+}
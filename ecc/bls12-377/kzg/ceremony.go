@@ -0,0 +1,338 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/transcript"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidSRS          = errors.New("the SRS is not made of powers of the same secret")
+	ErrInvalidUpdateProof  = errors.New("invalid proof of knowledge of the update")
+	ErrInvalidContribution = errors.New("the contributions do not lead from the initial SRS to the final one")
+	ErrInvalidSRSPoint     = errors.New("a point of the SRS is not in the correct subgroup")
+)
+
+// UpdateProof proves a contribution to a Powers of Tau ceremony, that is the
+// update of a SRS [τⁱ]G₁, [τ]G₂ to [(xτ)ⁱ]G₁, [xτ]G₂ by a random x known by the
+// contributor only.
+//
+// implements io.ReaderFrom and io.WriterTo
+type UpdateProof struct {
+	// RunningProduct [xτ]G₁, the first power of the SRS after the update
+	RunningProduct curve.G1Affine
+
+	// PotPubkey [x]G₂, linking the running products before and after the update
+	PotPubkey curve.G2Affine
+
+	// R, S Schnorr proof of knowledge of x such that RunningProduct = [x][τ]G₁:
+	// R = [k][τ]G₁ and S = k + cx, where c is derived from [τ]G₁, the running
+	// product, the public key and R
+	R curve.G1Affine
+	S fr.Element
+}
+
+// Contribute updates srs with a random secret x, and returns the proof of the
+// update. x is discarded.
+//
+// The first contribution of a ceremony is usually made on NewSRS(size, big.NewInt(1)).
+func Contribute(srs *SRS) (UpdateProof, error) {
+	var x fr.Element
+	if _, err := x.SetRandom(); err != nil {
+		return UpdateProof{}, err
+	}
+	return contribute(srs, x)
+}
+
+func contribute(srs *SRS, x fr.Element) (UpdateProof, error) {
+
+	if len(srs.Pk.G1) < 2 {
+		return UpdateProof{}, ErrMinSRSSize
+	}
+	if x.IsZero() {
+		return UpdateProof{}, ErrInvalidUpdateProof
+	}
+
+	var proof UpdateProof
+	var bX, bK big.Int
+	x.BigInt(&bX)
+	previous := srs.Pk.G1[1]
+
+	// [xⁱ][τⁱ]G₁
+	parallel.Execute(len(srs.Pk.G1)-1, func(start, end int) {
+		var xi fr.Element
+		var b big.Int
+		xi.Exp(x, big.NewInt(int64(start+1)))
+		for i := start + 1; i < end+1; i++ {
+			xi.BigInt(&b)
+			srs.Pk.G1[i].ScalarMultiplication(&srs.Pk.G1[i], &b)
+			xi.Mul(&xi, &x)
+		}
+	})
+	srs.Vk.G2[1].ScalarMultiplication(&srs.Vk.G2[1], &bX)
+	srs.Vk.Lines[1] = curve.PrecomputeLines(srs.Vk.G2[1])
+
+	_, _, _, g2 := curve.Generators()
+	proof.RunningProduct = srs.Pk.G1[1]
+	proof.PotPubkey.ScalarMultiplication(&g2, &bX)
+
+	// Schnorr proof of knowledge of x
+	var k, c fr.Element
+	if _, err := k.SetRandom(); err != nil {
+		return UpdateProof{}, err
+	}
+	k.BigInt(&bK)
+	proof.R.ScalarMultiplication(&previous, &bK)
+	c, err := deriveUpdateChallenge(&previous, &proof)
+	if err != nil {
+		return UpdateProof{}, err
+	}
+	proof.S.Mul(&c, &x).Add(&proof.S, &k)
+
+	return proof, nil
+}
+
+// VerifyContributions checks that srs results from the contributions proven by
+// proofs, starting from the SRS whose first power is initialTau ([τ₀]G₁, the
+// generator for NewSRS(size, big.NewInt(1))), and that srs is valid (see
+// VerifySRS). The links between the contributions are checked with a single
+// multi-pairing.
+func VerifyContributions(srs *SRS, initialTau curve.G1Affine, proofs []UpdateProof) error {
+
+	if len(srs.Pk.G1) < 2 {
+		return ErrMinSRSSize
+	}
+
+	if len(proofs) == 0 {
+		if !initialTau.Equal(&srs.Pk.G1[1]) {
+			return ErrInvalidContribution
+		}
+		return VerifySRS(srs)
+	}
+	if !proofs[len(proofs)-1].RunningProduct.Equal(&srs.Pk.G1[1]) {
+		return ErrInvalidContribution
+	}
+
+	// proofs of knowledge: [S][τ]G₁ - [c][xτ]G₁ = R
+	previous := initialTau
+	for i := range proofs {
+		if previous.IsInfinity() || proofs[i].RunningProduct.IsInfinity() || proofs[i].PotPubkey.IsInfinity() {
+			return ErrInvalidUpdateProof
+		}
+		c, err := deriveUpdateChallenge(&previous, &proofs[i])
+		if err != nil {
+			return err
+		}
+		var bS, bC big.Int
+		proofs[i].S.BigInt(&bS)
+		c.Neg(&c).BigInt(&bC)
+		var check curve.G1Jac
+		check.JointScalarMultiplication(&previous, &proofs[i].RunningProduct, &bS, &bC)
+		var r curve.G1Jac
+		r.FromAffine(&proofs[i].R)
+		if !check.Equal(&r) {
+			return ErrInvalidUpdateProof
+		}
+		previous = proofs[i].RunningProduct
+	}
+
+	runningProducts := make([]curve.G1Affine, len(proofs)+1)
+	pubkeys := make([]curve.G2Affine, len(proofs))
+	runningProducts[0] = initialTau
+	for i := range proofs {
+		runningProducts[i+1] = proofs[i].RunningProduct
+		pubkeys[i] = proofs[i].PotPubkey
+	}
+	if err := verifyRunningProducts(runningProducts, pubkeys); err != nil {
+		return err
+	}
+
+	return VerifySRS(srs)
+}
+
+// VerifySRS checks that srs is made of the powers of a same non zero secret τ:
+// the generators are the standard ones, the lines of the verifying key match
+// its G₂ points, and e([τⁱ]G₁, [τ]G₂) = e([τⁱ⁺¹]G₁, G₂) for all i, which is
+// checked with a single random linear combination.
+//
+// The points are assumed to be in the correct subgroups, see SRS.Validate.
+func VerifySRS(srs *SRS) error {
+	return verifySRS(srs, 0)
+}
+
+// Validate checks that srs is safe to use: its points are in the correct
+// subgroups and it is made of the powers of a same secret (see VerifySRS), with
+// a constant number of pairings. ReadFrom checks the subgroups but not the
+// powers, UnsafeReadFrom and ReadDump check neither.
+//
+// Validate runs on a single goroutine, see ValidateParallel.
+func (srs *SRS) Validate() error {
+	return srs.validate(1)
+}
+
+// ValidateParallel is Validate with the work split among nbTasks goroutines,
+// or among all the CPUs if nbTasks ≤ 0.
+func (srs *SRS) ValidateParallel(nbTasks int) error {
+	if nbTasks <= 0 {
+		nbTasks = runtime.NumCPU()
+	}
+	return srs.validate(nbTasks)
+}
+
+func (srs *SRS) validate(nbTasks int) error {
+
+	if len(srs.Pk.G1) < 2 {
+		return ErrMinSRSSize
+	}
+
+	for i := range srs.Vk.G2 {
+		if !srs.Vk.G2[i].IsOnCurve() || !srs.Vk.G2[i].IsInSubGroup() {
+			return ErrInvalidSRSPoint
+		}
+	}
+	var invalid atomic.Bool
+	parallel.Execute(len(srs.Pk.G1), func(start, end int) {
+		for i := start; i < end && !invalid.Load(); i++ {
+			if !srs.Pk.G1[i].IsOnCurve() || !srs.Pk.G1[i].IsInSubGroup() {
+				invalid.Store(true)
+			}
+		}
+	}, nbTasks)
+	if invalid.Load() {
+		return ErrInvalidSRSPoint
+	}
+
+	return verifySRS(srs, nbTasks)
+}
+
+// verifySRS implements VerifySRS, with multi-exponentiations on nbTasks
+// goroutines (all the CPUs if 0)
+func verifySRS(srs *SRS, nbTasks int) error {
+
+	n := len(srs.Pk.G1)
+	if n < 2 {
+		return ErrMinSRSSize
+	}
+
+	_, _, g1, g2 := curve.Generators()
+	if !srs.Pk.G1[0].Equal(&g1) || !srs.Vk.G1.Equal(&g1) || !srs.Vk.G2[0].Equal(&g2) {
+		return ErrInvalidSRS
+	}
+	if srs.Pk.G1[1].IsInfinity() || srs.Vk.G2[1].IsInfinity() {
+		return ErrInvalidSRS
+	}
+	if srs.Vk.Lines[0] != curve.PrecomputeLines(srs.Vk.G2[0]) || srs.Vk.Lines[1] != curve.PrecomputeLines(srs.Vk.G2[1]) {
+		return ErrInvalidSRS
+	}
+
+	// e(∑ᵢρⁱ[τⁱ]G₁, [τ]G₂) e(-∑ᵢρⁱ[τⁱ⁺¹]G₁, G₂) == 1
+	rhos := make([]fr.Element, n-1)
+	rhos[0].SetOne()
+	if n > 2 {
+		if _, err := rhos[1].SetRandom(); err != nil {
+			return err
+		}
+	}
+	for i := 2; i < len(rhos); i++ {
+		rhos[i].Mul(&rhos[i-1], &rhos[1])
+	}
+	config := ecc.MultiExpConfig{NbTasks: nbTasks}
+	var a, b curve.G1Affine
+	if _, err := a.MultiExp(srs.Pk.G1[:n-1], rhos, config); err != nil {
+		return err
+	}
+	if _, err := b.MultiExp(srs.Pk.G1[1:], rhos, config); err != nil {
+		return err
+	}
+	b.Neg(&b)
+	check, err := curve.PairingCheck(
+		[]curve.G1Affine{a, b},
+		[]curve.G2Affine{srs.Vk.G2[1], srs.Vk.G2[0]},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrInvalidSRS
+	}
+	return nil
+}
+
+// verifyRunningProducts checks that each running product is the previous one
+// multiplied by the secret of the corresponding public key, that is
+// e([xᵢτᵢ₋₁]G₁, G₂) = e([τᵢ₋₁]G₁, [xᵢ]G₂) for all i. The checks are batched with
+// random λᵢ into e(∑ᵢλᵢ[τᵢ]G₁, G₂) ∏ᵢ e([-λᵢτᵢ₋₁]G₁, [xᵢ]G₂) == 1.
+func verifyRunningProducts(runningProducts []curve.G1Affine, pubkeys []curve.G2Affine) error {
+
+	if len(runningProducts) != len(pubkeys)+1 {
+		return ErrInvalidContribution
+	}
+	if len(pubkeys) == 0 {
+		return nil
+	}
+
+	lambdas := make([]fr.Element, len(pubkeys))
+	for i := range lambdas {
+		if _, err := lambdas[i].SetRandom(); err != nil {
+			return err
+		}
+	}
+	P := make([]curve.G1Affine, len(pubkeys)+1)
+	Q := make([]curve.G2Affine, len(pubkeys)+1)
+	if _, err := P[0].MultiExp(runningProducts[1:], lambdas, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	_, _, _, Q[0] = curve.Generators()
+	for i := range pubkeys {
+		var bLambda big.Int
+		lambdas[i].Neg(&lambdas[i]).BigInt(&bLambda)
+		P[i+1].ScalarMultiplication(&runningProducts[i], &bLambda)
+		Q[i+1] = pubkeys[i]
+	}
+	check, err := curve.PairingCheck(P, Q)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrInvalidContribution
+	}
+	return nil
+}
+
+// deriveUpdateChallenge derives the challenge of the proof of knowledge of an
+// update of the running product previous
+func deriveUpdateChallenge(previous *curve.G1Affine, proof *UpdateProof) (fr.Element, error) {
+	fs := transcript.NewLegacy(fiatshamir.NewTranscript(sha256.New(), "pok"))
+	if err := fs.AppendPoint("pok", *previous, proof.RunningProduct, proof.R); err != nil {
+		return fr.Element{}, err
+	}
+	if err := fs.AppendMessage("pok", proof.PotPubkey.Marshal()); err != nil {
+		return fr.Element{}, err
+	}
+	return fs.ChallengeScalar("pok")
+}
@@ -0,0 +1,179 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package transcript
+
+import (
+	"errors"
+	"hash"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+// wideReductionBytes is the number of bytes from which a challenge is reduced
+// modulo r: the 16 extra bytes make the bias of the result negligible.
+const wideReductionBytes = fr.Bytes + 16
+
+// ErrMultipleChallenges is returned by Legacy.ChallengeScalars when more than one
+// challenge is requested for a label.
+var ErrMultipleChallenges = errors.New("legacy transcript derives a single challenge per label")
+
+// Transcript is a Fiat-Shamir transcript absorbing messages, scalars and points,
+// and squeezing scalar challenges.
+type Transcript interface {
+	// AppendMessage absorbs raw bytes under label
+	AppendMessage(label string, message []byte) error
+
+	// AppendScalar absorbs scalars under label
+	AppendScalar(label string, scalars ...fr.Element) error
+
+	// AppendPoint absorbs points under label
+	AppendPoint(label string, points ...curve.G1Affine) error
+
+	// ChallengeScalar returns a challenge bound to label and to the transcript so far
+	ChallengeScalar(label string) (fr.Element, error)
+
+	// ChallengeScalars returns n challenges bound to label and to the transcript so far
+	ChallengeScalars(label string, n int) ([]fr.Element, error)
+}
+
+// Streaming is a Transcript whose challenges need not be declared up front.
+type Streaming struct {
+	fs *fiatshamir.StreamingTranscript
+}
+
+// NewStreaming returns a new Streaming transcript using h, separated from other
+// uses of h by domain.
+func NewStreaming(h hash.Hash, domain string) *Streaming {
+	return &Streaming{fs: fiatshamir.NewStreamingTranscript(h, domain)}
+}
+
+// AppendMessage absorbs message under label
+func (t *Streaming) AppendMessage(label string, message []byte) error {
+	return t.fs.AppendMessage(label, message)
+}
+
+// AppendScalar absorbs the canonical big-endian encodings of the scalars, as a
+// single message under label.
+func (t *Streaming) AppendScalar(label string, scalars ...fr.Element) error {
+	buf := make([]byte, 0, len(scalars)*fr.Bytes)
+	for i := range scalars {
+		buf = append(buf, scalars[i].Marshal()...)
+	}
+	return t.fs.AppendMessage(label, buf)
+}
+
+// AppendPoint absorbs the compressed encodings of the points, as a single message
+// under label.
+func (t *Streaming) AppendPoint(label string, points ...curve.G1Affine) error {
+	buf := make([]byte, 0, len(points)*curve.SizeOfG1AffineCompressed)
+	for i := range points {
+		buf = append(buf, points[i].Marshal()...)
+	}
+	return t.fs.AppendMessage(label, buf)
+}
+
+// ChallengeScalar returns a challenge bound to label and to the transcript so far
+func (t *Streaming) ChallengeScalar(label string) (fr.Element, error) {
+	res, err := t.ChallengeScalars(label, 1)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	return res[0], nil
+}
+
+// ChallengeScalars returns n challenges bound to label and to the transcript so
+// far. Each challenge is reduced modulo r from fr.Bytes+16 bytes.
+func (t *Streaming) ChallengeScalars(label string, n int) ([]fr.Element, error) {
+	b, err := t.fs.ChallengeBytes(label, n, wideReductionBytes)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]fr.Element, n)
+	for i := range res {
+		res[i].SetBytes(b[i])
+	}
+	return res, nil
+}
+
+// Legacy adapts a fiatshamir.Transcript, or a fiatshamir.MerlinTranscript, to the
+// Transcript interface. The labels are the challenge identifiers of the
+// underlying transcript: the elements are bound to the challenge named by label,
+// which must have been declared when creating it.
+//
+// Each element is bound separately with its Marshal encoding, and the challenges
+// are set from the digests with SetBytes, so that protocols built on
+// fiatshamir.Transcript keep their transcripts unchanged.
+type Legacy struct {
+	fs fiatshamir.Challenger
+}
+
+// NewLegacy returns a Transcript on top of fs
+func NewLegacy(fs fiatshamir.Challenger) *Legacy {
+	return &Legacy{fs: fs}
+}
+
+// AppendMessage binds message to the challenge label
+func (t *Legacy) AppendMessage(label string, message []byte) error {
+	return t.fs.Bind(label, message)
+}
+
+// AppendScalar binds each scalar to the challenge label
+func (t *Legacy) AppendScalar(label string, scalars ...fr.Element) error {
+	for i := range scalars {
+		if err := t.fs.Bind(label, scalars[i].Marshal()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AppendPoint binds each point to the challenge label
+func (t *Legacy) AppendPoint(label string, points ...curve.G1Affine) error {
+	for i := range points {
+		if err := t.fs.Bind(label, points[i].Marshal()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ChallengeScalar computes the challenge label, and reduces it modulo r
+func (t *Legacy) ChallengeScalar(label string) (fr.Element, error) {
+	b, err := t.fs.ComputeChallenge(label)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var res fr.Element
+	res.SetBytes(b)
+	return res, nil
+}
+
+// ChallengeScalars computes the challenge label if n is 1. The underlying
+// transcript derives a single challenge per label, so ErrMultipleChallenges is
+// returned otherwise.
+func (t *Legacy) ChallengeScalars(label string, n int) ([]fr.Element, error) {
+	if n != 1 {
+		return nil, ErrMultipleChallenges
+	}
+	c, err := t.ChallengeScalar(label)
+	if err != nil {
+		return nil, err
+	}
+	return []fr.Element{c}, nil
+}
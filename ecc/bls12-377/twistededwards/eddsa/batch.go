@@ -0,0 +1,152 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package eddsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"hash"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
+)
+
+// ErrLengthMismatch is returned when the inputs of BatchVerify don't have the
+// same length.
+var ErrLengthMismatch = errors.New("public keys, messages and signatures don't have the same length")
+
+// sizeBatchCoefficient is the size in bytes of the random coefficients of the
+// linear combination, which bounds the probability that an invalid batch
+// passes the check by 2⁻¹²⁸.
+const sizeBatchCoefficient = 16
+
+// batchEntry is a parsed signature of a batch
+type batchEntry struct {
+	index int
+	A, R  twistededwards.PointAffine
+	s     big.Int // response of the signature
+	h     big.Int // H(R,A,M) mod order
+	z     big.Int // random coefficient of the linear combination
+}
+
+// BatchVerify verifies the eddsa signatures of the messages under the
+// corresponding public keys. It returns the indices of the invalid
+// signatures, in increasing order, or nil if all the signatures are valid.
+// Signatures that cannot be deserialized and public keys that are not on the
+// curve are reported as invalid.
+//
+// The signatures are checked at once with a random linear combination of
+// the verification equations: for random zᵢ,
+//
+//	cofactor⋅((∑ zᵢ⋅Sᵢ)⋅Base) = cofactor⋅(∑ zᵢ⋅Rᵢ + ∑ (zᵢ⋅H(Rᵢ,Aᵢ,Mᵢ))⋅Aᵢ)
+//
+// which costs a single multi-scalar multiplication (PointExtended.MultiExp).
+// If the check fails, the batch is bisected to find the invalid signatures.
+func BatchVerify(pubKeys []PublicKey, messages [][]byte, signatures [][]byte, hFunc hash.Hash) ([]int, error) {
+
+	// hFunc cannot be nil.
+	// We need a hash function for the Fiat-Shamir.
+	if hFunc == nil {
+		return nil, errHashNeeded
+	}
+	if len(messages) != len(pubKeys) || len(signatures) != len(pubKeys) {
+		return nil, ErrLengthMismatch
+	}
+
+	curveParams := twistededwards.GetEdwardsCurve()
+
+	var invalid []int
+	entries := make([]batchEntry, 0, len(pubKeys))
+	var buf [sizeBatchCoefficient]byte
+	for i := range pubKeys {
+		var sig Signature
+		if _, err := sig.SetBytes(signatures[i]); err != nil || !pubKeys[i].A.IsOnCurve() {
+			invalid = append(invalid, i)
+			continue
+		}
+		entries = append(entries, batchEntry{index: i, A: pubKeys[i].A, R: sig.R})
+		e := &entries[len(entries)-1]
+		e.s.SetBytes(sig.S[:])
+		if err := computeHRAM(&e.h, &sig.R, &pubKeys[i].A, messages[i], hFunc); err != nil {
+			return nil, err
+		}
+		e.h.Mod(&e.h, &curveParams.Order)
+		if _, err := rand.Read(buf[:]); err != nil {
+			return nil, err
+		}
+		e.z.SetBytes(buf[:])
+	}
+
+	invalid = append(invalid, bisect(entries, &curveParams)...)
+	if len(invalid) == 0 {
+		return nil, nil
+	}
+	sort.Ints(invalid)
+	return invalid, nil
+}
+
+// bisect returns the indices of the invalid signatures of the entries
+func bisect(entries []batchEntry, curveParams *twistededwards.CurveParams) []int {
+	if len(entries) == 0 || checkBatch(entries, curveParams) {
+		return nil
+	}
+	if len(entries) == 1 {
+		return []int{entries[0].index}
+	}
+	m := len(entries) / 2
+	return append(bisect(entries[:m], curveParams), bisect(entries[m:], curveParams)...)
+}
+
+// checkBatch returns true if the random linear combination of the
+// verification equations of the entries holds
+func checkBatch(entries []batchEntry, curveParams *twistededwards.CurveParams) bool {
+	n := len(entries)
+	points := make([]twistededwards.PointAffine, 2*n+1)
+	scalars := make([]fr.Element, 2*n+1)
+
+	// -(∑ zᵢ⋅Sᵢ)⋅Base + ∑ zᵢ⋅Rᵢ + ∑ (zᵢ⋅hᵢ)⋅Aᵢ
+	// the scalars are reduced modulo the order, which is smaller than the
+	// modulus of fr
+	var s, tmp big.Int
+	points[0].Set(&curveParams.Base)
+	for i := range entries {
+		tmp.Mul(&entries[i].z, &entries[i].s)
+		s.Add(&s, &tmp)
+		points[2*i+1].Set(&entries[i].R)
+		scalars[2*i+1].SetBigInt(&entries[i].z)
+		points[2*i+2].Set(&entries[i].A)
+		tmp.Mul(&entries[i].z, &entries[i].h).Mod(&tmp, &curveParams.Order)
+		scalars[2*i+2].SetBigInt(&tmp)
+	}
+	s.Mod(&s, &curveParams.Order)
+	s.Sub(&curveParams.Order, &s)
+	scalars[0].SetBigInt(&s)
+
+	var res twistededwards.PointExtended
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false
+	}
+
+	var bCofactor big.Int
+	curveParams.Cofactor.BigInt(&bCofactor)
+	res.ScalarMultiplication(&res, &bCofactor)
+
+	return res.IsZero()
+}
@@ -264,6 +264,79 @@ func TestEddsaSHA256(t *testing.T) {
 
 }
 
+func TestBatchVerify(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 weak rng is fine here
+
+	hFunc := sha256.New()
+
+	const n = 9
+	pubKeys := make([]PublicKey, n)
+	messages := make([][]byte, n)
+	signatures := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, err := GenerateKey(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKeys[i] = privKey.PublicKey
+		messages[i] = []byte(fmt.Sprintf("message %d", i))
+		signatures[i], err = privKey.Sign(messages[i], hFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// valid batch
+	invalid, err := BatchVerify(pubKeys, messages, signatures, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invalid) != 0 {
+		t.Fatal("BatchVerify of valid signatures should not report invalid ones")
+	}
+
+	// empty batch
+	invalid, err = BatchVerify(nil, nil, nil, hFunc)
+	if err != nil || len(invalid) != 0 {
+		t.Fatal("BatchVerify of an empty batch should succeed")
+	}
+
+	if _, err = BatchVerify(pubKeys, messages[1:], signatures, hFunc); err != ErrLengthMismatch {
+		t.Fatal("BatchVerify should raise a length mismatch error")
+	}
+	if _, err = BatchVerify(pubKeys, messages, signatures, nil); err != errHashNeeded {
+		t.Fatal("BatchVerify should raise an error if hFunc is nil")
+	}
+
+	// wrong messages and malformed signature
+	messages[1], messages[2] = messages[2], messages[1]
+	messages[7] = []byte("wrong message")
+	signatures[4] = signatures[4][1:]
+	invalid, err = BatchVerify(pubKeys, messages, signatures, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{1, 2, 4, 7}
+	if len(invalid) != len(expected) {
+		t.Fatalf("BatchVerify reported %v, expected %v", invalid, expected)
+	}
+	for i := range expected {
+		if invalid[i] != expected[i] {
+			t.Fatalf("BatchVerify reported %v, expected %v", invalid, expected)
+		}
+	}
+
+	// the reported indices are those rejected by Verify
+	for i := 0; i < n; i++ {
+		res, _ := pubKeys[i].Verify(signatures[i], messages[i], hFunc)
+		if res == (i == 1 || i == 2 || i == 4 || i == 7) {
+			t.Fatal("BatchVerify and Verify disagree")
+		}
+	}
+}
+
 // benchmarks
 
 func BenchmarkVerify(b *testing.B) {
@@ -289,3 +362,33 @@ func BenchmarkVerify(b *testing.B) {
 		pubKey.Verify(signature, msgBin[:], hFunc)
 	}
 }
+
+func BenchmarkBatchVerify(b *testing.B) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 weak rng is fine here
+
+	hFunc := hash.MIMC_BLS12_377.New()
+
+	const n = 64
+	pubKeys := make([]PublicKey, n)
+	messages := make([][]byte, n)
+	signatures := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, err := GenerateKey(r)
+		if err != nil {
+			b.Fatal(err)
+		}
+		pubKeys[i] = privKey.PublicKey
+		var frMsg fr.Element
+		frMsg.SetUint64(uint64(i))
+		msgBin := frMsg.Bytes()
+		messages[i] = msgBin[:]
+		signatures[i], _ = privKey.Sign(messages[i], hFunc)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchVerify(pubKeys, messages, signatures, hFunc)
+	}
+}
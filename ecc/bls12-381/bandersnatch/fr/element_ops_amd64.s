@@ -0,0 +1,6 @@
+// +build !purego
+
+// Code generated by gnark-crypto/generator. DO NOT EDIT.
+// We include the hash to force the Go compiler to recompile: 7408166788737592375
+#include "../../../../field/asm/element_4w_amd64.s"
+
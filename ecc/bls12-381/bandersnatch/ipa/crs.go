@@ -0,0 +1,223 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	fp "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// VerkleDomainSize is the size of the domain of the polynomials committed to
+// in Verkle trees
+const VerkleDomainSize = 256
+
+// crsSeed is the seed from which the basis of the CRS is derived
+const crsSeed = "eth_verkle_oct_2021"
+
+var (
+	ErrInvalidDomainSize  = errors.New("domain size must be a power of two larger than 1")
+	ErrInvalidPolynomial  = errors.New("polynomial is larger than the domain")
+	ErrInvalidPoint       = errors.New("evaluation point is not in the domain")
+	ErrLengthMismatch     = errors.New("inputs don't have the same length")
+	ErrVerifyOpeningProof = errors.New("can't verify opening proof")
+)
+
+// CRS is the common reference string of the IPA: the basis of the vector
+// commitments, derived by hashing, and the point Q binding the claimed values.
+// It also holds the precomputed data of the barycentric evaluation on the
+// domain {0, 1, …, n-1}.
+type CRS struct {
+	Basis []bandersnatch.PointAffine
+	Q     bandersnatch.PointAffine
+
+	// weights[i] = A'(i) = ∏_{j≠i} (i - j), invWeights[i] = 1 / A'(i)
+	weights    []fr.Element
+	invWeights []fr.Element
+	// invDomain[k] = 1/k, k < n
+	invDomain []fr.Element
+}
+
+// NewCRS returns the CRS for polynomials of domainSize evaluations. The basis
+// is the same as the one of go-ipa, NewCRS(VerkleDomainSize) being that of
+// Verkle trees. Q is the generator of bandersnatch.
+func NewCRS(domainSize uint64) (*CRS, error) {
+	if domainSize < 2 || bits.OnesCount64(domainSize) != 1 {
+		return nil, ErrInvalidDomainSize
+	}
+	crs := &CRS{
+		Basis: GenerateBasis(domainSize),
+		Q:     bandersnatch.GetEdwardsCurve().Base,
+	}
+
+	n := int(domainSize)
+	crs.invDomain = make([]fr.Element, n)
+	for k := 1; k < n; k++ {
+		crs.invDomain[k].SetUint64(uint64(k))
+	}
+	crs.invDomain = fr.BatchInvert(crs.invDomain)
+
+	// A'(i) = ∏_{j≠i} (i - j) = (-1)^{n-1-i} ⋅ i! ⋅ (n-1-i)!
+	factorials := make([]fr.Element, n)
+	factorials[0].SetOne()
+	for k := 1; k < n; k++ {
+		var tmp fr.Element
+		tmp.SetUint64(uint64(k))
+		factorials[k].Mul(&factorials[k-1], &tmp)
+	}
+	crs.weights = make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		crs.weights[i].Mul(&factorials[i], &factorials[n-1-i])
+		if (n-1-i)%2 == 1 {
+			crs.weights[i].Neg(&crs.weights[i])
+		}
+	}
+	crs.invWeights = fr.BatchInvert(crs.weights)
+
+	return crs, nil
+}
+
+// GenerateBasis returns n points of the Banderwagon group, derived by
+// hashing: the i-th candidate x coordinate is SHA-256(seed ∥ i) with i
+// encoded on 8 bytes in big endian, and candidates that are not the
+// coordinate of a point of the group are skipped.
+func GenerateBasis(n uint64) []bandersnatch.PointAffine {
+	points := make([]bandersnatch.PointAffine, 0, n)
+	var counter [8]byte
+	for i := uint64(0); uint64(len(points)) != n; i++ {
+		h := sha256.New()
+		h.Write([]byte(crsSeed))
+		binary.BigEndian.PutUint64(counter[:], i)
+		h.Write(counter[:])
+
+		var x fp.Element
+		x.SetBytes(h.Sum(nil))
+		xBytes := x.Bytes()
+
+		var p Digest
+		if _, err := p.SetBytes(xBytes[:]); err != nil {
+			continue
+		}
+		points = append(points, p.PointAffine)
+	}
+	return points
+}
+
+// DomainSize returns the size of the domain of the committed polynomials
+func (crs *CRS) DomainSize() int {
+	return len(crs.Basis)
+}
+
+// Commit returns the commitment ∑ evaluations[i]⋅Basis[i] to the polynomial
+// whose evaluations on the domain are given. Missing evaluations are zero.
+func Commit(evaluations []fr.Element, crs *CRS) (Digest, error) {
+	if len(evaluations) > crs.DomainSize() {
+		return Digest{}, ErrInvalidPolynomial
+	}
+	return multiExp(crs.Basis[:len(evaluations)], evaluations)
+}
+
+// barycentricCoefficients returns the Lagrange polynomials evaluated at
+// point: Lᵢ(z) = A(z) / (A'(i)⋅(z - i)) where A(z) = ∏ (z - i).
+func (crs *CRS) barycentricCoefficients(point fr.Element) []fr.Element {
+	n := crs.DomainSize()
+	res := make([]fr.Element, n)
+
+	// point in the domain
+	if point.IsUint64() && point.Uint64() < uint64(n) {
+		res[point.Uint64()].SetOne()
+		return res
+	}
+
+	var az, tmp fr.Element
+	az.SetOne()
+	for i := 0; i < n; i++ {
+		tmp.SetUint64(uint64(i))
+		tmp.Sub(&point, &tmp)
+		az.Mul(&az, &tmp)
+		res[i].Mul(&tmp, &crs.weights[i])
+	}
+	res = fr.BatchInvert(res)
+	for i := range res {
+		res[i].Mul(&res[i], &az)
+	}
+	return res
+}
+
+// evaluate returns the evaluation at point of the polynomial given by its
+// evaluations on the domain
+func (crs *CRS) evaluate(evaluations []fr.Element, point fr.Element) fr.Element {
+	return innerProduct(evaluations, crs.barycentricCoefficients(point))
+}
+
+// divideOnDomain returns the evaluations of (f(X) - f(index)) / (X - index)
+// on the domain, f being given by its evaluations on the domain
+func (crs *CRS) divideOnDomain(index uint64, f []fr.Element) []fr.Element {
+	n := crs.DomainSize()
+	res := make([]fr.Element, n)
+	y := f[index]
+
+	// q(i) = (f(i) - y) / (i - index) for i ≠ index, and
+	// q(index) = ∑_{i≠index} A'(index) / A'(i) ⋅ (f(i) - y) / (index - i)
+	//          = -∑_{i≠index} A'(index) / A'(i) ⋅ q(i)
+	aIndex := crs.weights[index]
+	var tmp fr.Element
+	for i := 0; i < n; i++ {
+		if i == int(index) {
+			continue
+		}
+		res[i].Sub(&f[i], &y)
+		if i > int(index) {
+			res[i].Mul(&res[i], &crs.invDomain[i-int(index)])
+		} else {
+			res[i].Mul(&res[i], &crs.invDomain[int(index)-i]).Neg(&res[i])
+		}
+		tmp.Mul(&aIndex, &crs.invWeights[i]).Mul(&tmp, &res[i])
+		res[index].Sub(&res[index], &tmp)
+	}
+	return res
+}
+
+// multiExp returns ∑ scalars[i]⋅points[i]
+func multiExp(points []bandersnatch.PointAffine, scalars []fr.Element) (Digest, error) {
+	// the scalars are smaller than the order, hence than the modulus of fp
+	_scalars := make([]fp.Element, len(scalars))
+	for i := range scalars {
+		b := scalars[i].Bytes()
+		_scalars[i].SetBytes(b[:])
+	}
+	var res bandersnatch.PointExtended
+	if _, err := res.MultiExp(points, _scalars, ecc.MultiExpConfig{}); err != nil {
+		return Digest{}, err
+	}
+	var d Digest
+	d.FromExtended(&res)
+	return d, nil
+}
+
+func innerProduct(a, b []fr.Element) fr.Element {
+	var res, tmp fr.Element
+	for i := range a {
+		tmp.Mul(&a[i], &b[i])
+		res.Add(&res, &tmp)
+	}
+	return res
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	fp "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// SizeDigest is the size in bytes of a serialized Digest
+const SizeDigest = fp.Bytes
+
+var (
+	errNotInGroup = errors.New("point is not in the Banderwagon group")
+	errNotOnCurve = errors.New("x is not the coordinate of a point on the curve")
+)
+
+// Digest is a commitment: an element of the Banderwagon group, represented
+// by a point of bandersnatch. P and P + (0, -1) represent the same element.
+type Digest struct {
+	bandersnatch.PointAffine
+}
+
+// Equal returns true if d and other represent the same element of the
+// Banderwagon group, that is if x₁⋅y₂ = x₂⋅y₁
+func (d *Digest) Equal(other *Digest) bool {
+	var lhs, rhs fp.Element
+	lhs.Mul(&d.X, &other.Y)
+	rhs.Mul(&other.X, &d.Y)
+	return lhs.Equal(&rhs)
+}
+
+// Bytes returns the serialization of d: the big endian encoding of x if y is
+// lexicographically largest, of -x otherwise.
+func (d *Digest) Bytes() (res [SizeDigest]byte) {
+	x := d.X
+	if !d.Y.LexicographicallyLargest() {
+		x.Neg(&x)
+	}
+	return x.Bytes()
+}
+
+// SetBytes sets d from its serialization. The y coordinate is the
+// lexicographically largest one, and the point is checked to be in the
+// Banderwagon group, i.e. 1 - a⋅x² must be a square.
+// It returns the number of bytes read.
+func (d *Digest) SetBytes(buf []byte) (int, error) {
+	if len(buf) < SizeDigest {
+		return 0, io.ErrShortBuffer
+	}
+	var x fp.Element
+	if err := x.SetBytesCanonical(buf[:SizeDigest]); err != nil {
+		return 0, err
+	}
+
+	// y² = (1 - a⋅x²) / (1 - d⋅x²)
+	curveParams := bandersnatch.GetEdwardsCurve()
+	var one, xx, num, den, y fp.Element
+	one.SetOne()
+	xx.Square(&x)
+	num.Mul(&xx, &curveParams.A).Sub(&one, &num)
+	if num.Legendre() != 1 {
+		return 0, errNotInGroup
+	}
+	den.Mul(&xx, &curveParams.D).Sub(&one, &den)
+	if den.IsZero() {
+		return 0, errNotOnCurve
+	}
+	den.Inverse(&den)
+	y.Mul(&num, &den)
+	if y.Sqrt(&y) == nil {
+		return 0, errNotOnCurve
+	}
+	if !y.LexicographicallyLargest() {
+		y.Neg(&y)
+	}
+
+	d.X = x
+	d.Y = y
+	return SizeDigest, nil
+}
+
+// MapToScalarField returns x/y as an element of the scalar field, as used by
+// Verkle trees to commit to commitments.
+func (d *Digest) MapToScalarField() fr.Element {
+	var t fp.Element
+	t.Inverse(&d.Y).Mul(&t, &d.X)
+	var buf [fp.Bytes]byte
+	fp.LittleEndian.PutElement(&buf, t)
+
+	var res fr.Element
+	setBytesLE(&res, buf[:])
+	return res
+}
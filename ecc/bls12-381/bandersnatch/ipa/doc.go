@@ -0,0 +1,35 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ipa implements the inner product argument (IPA) polynomial
+// commitment scheme over Bandersnatch, and its multiproof aggregation, as used
+// by Ethereum's Verkle trees.
+//
+// Polynomials are given by their evaluations on the domain {0, 1, …, n-1},
+// and committed to with a Pedersen vector commitment over a basis (the CRS)
+// derived by hashing to the curve, so that no trusted setup is needed.
+// Commitments are elements of the Banderwagon group, the quotient of the
+// points of the prime order subgroup plus the 2-torsion by the 2-torsion
+// point (0, -1).
+//
+// The CRS generation, the transcript, the serialization of the points and of
+// the proofs follow the go-ipa implementation
+// (https://github.com/crate-crypto/go-ipa), so that the proofs are
+// interoperable.
+//
+// Documentation:
+//   - Bulletproofs: https://eprint.iacr.org/2017/1066.pdf
+//   - Verkle multiproofs: https://dankradfeist.de/ethereum/2021/06/18/pcs-multiproofs.html
+//   - Banderwagon: https://hackmd.io/@6iQDuIePQjyYBqDChYw_jg/BJBNcv9fq
+package ipa
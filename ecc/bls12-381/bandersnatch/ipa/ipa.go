@@ -0,0 +1,278 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"io"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	fp "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// OpeningProof is an inner product argument proving the evaluation of a
+// committed polynomial at a point.
+type OpeningProof struct {
+	// L, R commitments to the cross terms of each round
+	L, R []Digest
+
+	// A the folded polynomial after the last round
+	A fr.Element
+}
+
+// Open computes an opening proof of the polynomial given by its evaluations on
+// the domain at point, and returns it along with the evaluation.
+// digest is the commitment to the polynomial, as returned by Commit.
+func Open(evaluations []fr.Element, point fr.Element, digest Digest, transcript *Transcript, crs *CRS) (OpeningProof, fr.Element, error) {
+	n := crs.DomainSize()
+	if len(evaluations) > n {
+		return OpeningProof{}, fr.Element{}, ErrInvalidPolynomial
+	}
+
+	a := make([]fr.Element, n)
+	copy(a, evaluations)
+	b := crs.barycentricCoefficients(point)
+	claimedValue := innerProduct(a, b)
+
+	q := bindChallenge(transcript, &digest, &point, &claimedValue, crs)
+
+	G := make([]bandersnatch.PointAffine, n)
+	copy(G, crs.Basis)
+
+	nbRounds := bits.TrailingZeros(uint(n))
+	proof := OpeningProof{
+		L: make([]Digest, nbRounds),
+		R: make([]Digest, nbRounds),
+	}
+
+	for round := 0; round < nbRounds; round++ {
+		m := len(a) / 2
+		aL, aR := a[:m], a[m:]
+		bL, bR := b[:m], b[m:]
+		GL, GR := G[:m], G[m:]
+
+		zL := innerProduct(aR, bL)
+		zR := innerProduct(aL, bR)
+
+		// L = <G_L, a_R> + z_L⋅q, R = <G_R, a_L> + z_R⋅q
+		var err error
+		if proof.L[round], err = multiExp(append(GL[:m:m], q), append(aR[:m:m], zL)); err != nil {
+			return OpeningProof{}, fr.Element{}, err
+		}
+		if proof.R[round], err = multiExp(append(GR[:m:m], q), append(aL[:m:m], zR)); err != nil {
+			return OpeningProof{}, fr.Element{}, err
+		}
+
+		transcript.AppendPoint(&proof.L[round], "L")
+		transcript.AppendPoint(&proof.R[round], "R")
+		x := transcript.ChallengeScalar("x")
+		var xInv fr.Element
+		xInv.Inverse(&x)
+
+		// a = a_L + x⋅a_R, b = b_L + x⁻¹⋅b_R, G = G_L + x⁻¹⋅G_R
+		var tmp fr.Element
+		for i := 0; i < m; i++ {
+			tmp.Mul(&x, &aR[i])
+			aL[i].Add(&aL[i], &tmp)
+			tmp.Mul(&xInv, &bR[i])
+			bL[i].Add(&bL[i], &tmp)
+		}
+		foldPoints(GL, GR, &xInv)
+
+		a, b, G = aL, bL, GL
+	}
+
+	proof.A = a[0]
+
+	return proof, claimedValue, nil
+}
+
+// Verify verifies that proof is a correct opening proof of the polynomial
+// committed to by digest at point, with value claimedValue.
+func Verify(digest Digest, proof *OpeningProof, point, claimedValue fr.Element, transcript *Transcript, crs *CRS) error {
+	n := crs.DomainSize()
+	nbRounds := bits.TrailingZeros(uint(n))
+	if len(proof.L) != nbRounds || len(proof.R) != nbRounds {
+		return ErrVerifyOpeningProof
+	}
+
+	b := crs.barycentricCoefficients(point)
+	q := bindChallenge(transcript, &digest, &point, &claimedValue, crs)
+
+	// C' = C + y⋅q + ∑ xᵢ⋅Lᵢ + xᵢ⁻¹⋅Rᵢ
+	x := make([]fr.Element, nbRounds)
+	for i := 0; i < nbRounds; i++ {
+		transcript.AppendPoint(&proof.L[i], "L")
+		transcript.AppendPoint(&proof.R[i], "R")
+		x[i] = transcript.ChallengeScalar("x")
+	}
+	xInv := fr.BatchInvert(x)
+
+	points := make([]bandersnatch.PointAffine, 0, 2+2*nbRounds)
+	scalars := make([]fr.Element, 0, 2+2*nbRounds)
+	var one fr.Element
+	one.SetOne()
+	points = append(points, digest.PointAffine, q)
+	scalars = append(scalars, one, claimedValue)
+	for i := 0; i < nbRounds; i++ {
+		points = append(points, proof.L[i].PointAffine, proof.R[i].PointAffine)
+		scalars = append(scalars, x[i], xInv[i])
+	}
+
+	// the folded b and G are <b, s> and <G, s> where
+	// sᵢ = ∏ x_j⁻¹ over the rounds j whose bit is set in i
+	// (bit 0 of the last round being the least significant bit)
+	s := make([]fr.Element, n)
+	s[0].SetOne()
+	for j := 0; j < nbRounds; j++ {
+		half := 1 << j
+		xj := &xInv[nbRounds-1-j]
+		for i := 0; i < half; i++ {
+			s[half+i].Mul(&s[i], xj)
+		}
+	}
+	b0 := innerProduct(b, s)
+
+	// C' = A⋅G₀ + A⋅b₀⋅q, that is ∑ A⋅sᵢ⋅Gᵢ + A⋅b₀⋅q - C' = 0
+	for i := range s {
+		s[i].Mul(&s[i], &proof.A)
+	}
+	var ab0 fr.Element
+	ab0.Mul(&proof.A, &b0)
+	for i := range scalars {
+		scalars[i].Neg(&scalars[i])
+	}
+	points = append(points, crs.Basis...)
+	scalars = append(scalars, s...)
+	points = append(points, q)
+	scalars = append(scalars, ab0)
+
+	res, err := multiExp(points, scalars)
+	if err != nil {
+		return err
+	}
+	var zero Digest
+	zero.Y.SetOne()
+	if !res.Equal(&zero) {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// bindChallenge binds the commitment, the point and the claimed value to the
+// transcript, and returns the point w⋅Q where w is derived from the
+// transcript.
+func bindChallenge(transcript *Transcript, digest *Digest, point, claimedValue *fr.Element, crs *CRS) bandersnatch.PointAffine {
+	transcript.DomainSep("ipa")
+	transcript.AppendPoint(digest, "C")
+	transcript.AppendScalar(point, "input point")
+	transcript.AppendScalar(claimedValue, "output point")
+	w := transcript.ChallengeScalar("w")
+
+	var q bandersnatch.PointAffine
+	var wBigInt big.Int
+	w.BigInt(&wBigInt)
+	q.ScalarMultiplication(&crs.Q, &wBigInt)
+	return q
+}
+
+// foldPoints sets left[i] to left[i] + x⋅right[i]
+func foldPoints(left, right []bandersnatch.PointAffine, x *fr.Element) {
+	var xBigInt big.Int
+	x.BigInt(&xBigInt)
+
+	res := make([]bandersnatch.PointExtended, len(left))
+	var l, r bandersnatch.PointExtended
+	for i := range left {
+		l.FromAffine(&left[i])
+		r.FromAffine(&right[i])
+		r.ScalarMultiplication(&r, &xBigInt)
+		res[i].Add(&l, &r)
+	}
+	batchFromExtended(left, res)
+}
+
+// batchFromExtended sets res[i] to the affine representation of p[i], with a
+// single inversion
+func batchFromExtended(res []bandersnatch.PointAffine, p []bandersnatch.PointExtended) {
+	zs := make([]fp.Element, len(p))
+	for i := range p {
+		zs[i] = p[i].Z
+	}
+	zs = fp.BatchInvert(zs)
+	for i := range p {
+		res[i].X.Mul(&p[i].X, &zs[i])
+		res[i].Y.Mul(&p[i].Y, &zs[i])
+	}
+}
+
+// WriteTo writes the serialization of the proof to w: the points L, then the
+// points R, then A in little endian, as in go-ipa.
+// It returns the number of bytes written.
+func (proof *OpeningProof) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	for _, points := range [][]Digest{proof.L, proof.R} {
+		for i := range points {
+			buf := points[i].Bytes()
+			m, err := w.Write(buf[:])
+			n += int64(m)
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+	var buf [fr.Bytes]byte
+	fr.LittleEndian.PutElement(&buf, proof.A)
+	m, err := w.Write(buf[:])
+	n += int64(m)
+	return n, err
+}
+
+// ReadFrom reads a proof serialized by WriteTo from r. The number of rounds
+// is len(proof.L) if it is set, and that of a Verkle tree domain otherwise.
+// It returns the number of bytes read.
+func (proof *OpeningProof) ReadFrom(r io.Reader) (int64, error) {
+	nbRounds := len(proof.L)
+	if nbRounds == 0 {
+		nbRounds = bits.TrailingZeros(VerkleDomainSize)
+	}
+	proof.L = make([]Digest, nbRounds)
+	proof.R = make([]Digest, nbRounds)
+
+	var n int64
+	var buf [SizeDigest]byte
+	for _, points := range [][]Digest{proof.L, proof.R} {
+		for i := range points {
+			m, err := io.ReadFull(r, buf[:])
+			n += int64(m)
+			if err != nil {
+				return n, err
+			}
+			if _, err = points[i].SetBytes(buf[:]); err != nil {
+				return n, err
+			}
+		}
+	}
+	var bufA [fr.Bytes]byte
+	m, err := io.ReadFull(r, bufA[:])
+	n += int64(m)
+	if err != nil {
+		return n, err
+	}
+	proof.A, err = fr.LittleEndian.Element(&bufA)
+	return n, err
+}
@@ -0,0 +1,321 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	fp "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCRSVectors(t *testing.T) {
+	assert := require.New(t)
+
+	crs, err := NewCRS(VerkleDomainSize)
+	assert.NoError(err)
+
+	first := crs.Basis[0]
+	last := crs.Basis[VerkleDomainSize-1]
+	b0 := (&Digest{first}).Bytes()
+	b255 := (&Digest{last}).Bytes()
+	assert.Equal("01587ad1336675eb912550ec2a28eb8923b824b490dd2ba82e48f14590a298a0", hex.EncodeToString(b0[:]))
+	assert.Equal("3de2be346b539395b0c0de56a5ccca54a317f1b5c80107b0802af9a62276a4d8", hex.EncodeToString(b255[:]))
+
+	h := sha256.New()
+	for i := range crs.Basis {
+		b := (&Digest{crs.Basis[i]}).Bytes()
+		h.Write(b[:])
+	}
+	assert.Equal("1fcaea10bf24f750200e06fa473c76ff0468007291fa548e2d99f09ba9256fdb", hex.EncodeToString(h.Sum(nil)))
+}
+
+func TestIPAVector(t *testing.T) {
+	assert := require.New(t)
+
+	crs, err := NewCRS(VerkleDomainSize)
+	assert.NoError(err)
+
+	poly := make([]fr.Element, VerkleDomainSize)
+	for i := range poly {
+		poly[i].SetUint64(uint64(i%32 + 1))
+	}
+	digest, err := Commit(poly, crs)
+	assert.NoError(err)
+	b := digest.Bytes()
+	assert.Equal("1b9dff8f5ebbac250d291dfe90e36283a227c64b113c37f1bfb9e7a743cdb128", hex.EncodeToString(b[:]))
+
+	var point fr.Element
+	point.SetUint64(2101)
+	proof, value, err := Open(poly, point, digest, NewTranscript("test"), crs)
+	assert.NoError(err)
+	var vb [fr.Bytes]byte
+	fr.LittleEndian.PutElement(&vb, value)
+	assert.Equal("4a353e70b03c89f161de002e8713beec0d740a5e20722fd5bd68b30540a33208", hex.EncodeToString(vb[:]))
+
+	assert.NoError(Verify(digest, &proof, point, value, NewTranscript("test"), crs))
+}
+
+func randomPolynomial(size int) []fr.Element {
+	res := make([]fr.Element, size)
+	for i := range res {
+		res[i].SetRandom()
+	}
+	return res
+}
+
+func TestOpenVerify(t *testing.T) {
+	assert := require.New(t)
+
+	crs, err := NewCRS(32)
+	assert.NoError(err)
+
+	poly := randomPolynomial(32)
+	digest, err := Commit(poly, crs)
+	assert.NoError(err)
+
+	// point outside and inside the domain
+	var points [2]fr.Element
+	points[0].SetRandom()
+	points[1].SetUint64(7)
+
+	for _, point := range points {
+		proof, value, err := Open(poly, point, digest, NewTranscript("test"), crs)
+		assert.NoError(err)
+		assert.Equal(crs.evaluate(poly, point), value)
+		assert.NoError(Verify(digest, &proof, point, value, NewTranscript("test"), crs))
+
+		// wrong value
+		var wrongValue fr.Element
+		wrongValue.SetOne().Add(&wrongValue, &value)
+		assert.ErrorIs(Verify(digest, &proof, point, wrongValue, NewTranscript("test"), crs), ErrVerifyOpeningProof)
+
+		// wrong transcript
+		assert.ErrorIs(Verify(digest, &proof, point, value, NewTranscript("other"), crs), ErrVerifyOpeningProof)
+
+		// tampered proof
+		proof.A.Double(&proof.A)
+		assert.ErrorIs(Verify(digest, &proof, point, value, NewTranscript("test"), crs), ErrVerifyOpeningProof)
+	}
+	assert.Equal(poly[7], crs.evaluate(poly, points[1]))
+
+	_, err = NewCRS(12)
+	assert.ErrorIs(err, ErrInvalidDomainSize)
+	_, err = Commit(randomPolynomial(33), crs)
+	assert.ErrorIs(err, ErrInvalidPolynomial)
+}
+
+func TestDivideOnDomain(t *testing.T) {
+	assert := require.New(t)
+
+	crs, err := NewCRS(16)
+	assert.NoError(err)
+
+	f := randomPolynomial(16)
+	var z, tmp, lhs, rhs fr.Element
+	z.SetRandom()
+	for _, index := range []uint64{0, 5, 15} {
+		q := crs.divideOnDomain(index, f)
+
+		// f(z) - f(index) = q(z)⋅(z - index)
+		lhs = crs.evaluate(f, z)
+		lhs.Sub(&lhs, &f[index])
+		rhs = crs.evaluate(q, z)
+		tmp.SetUint64(index)
+		tmp.Sub(&z, &tmp)
+		rhs.Mul(&rhs, &tmp)
+		assert.Equal(lhs, rhs)
+	}
+}
+
+func TestMultiProof(t *testing.T) {
+	assert := require.New(t)
+
+	crs, err := NewCRS(VerkleDomainSize)
+	assert.NoError(err)
+
+	// several polynomials opened at the same point
+	points := []uint64{0, 7, 7, 255, 42, 0}
+	digests := make([]Digest, len(points))
+	evaluations := make([][]fr.Element, len(points))
+	values := make([]fr.Element, len(points))
+	for i := range points {
+		evaluations[i] = randomPolynomial(VerkleDomainSize)
+		digests[i], err = Commit(evaluations[i], crs)
+		assert.NoError(err)
+		values[i] = evaluations[i][points[i]]
+	}
+
+	proof, err := MultiOpen(digests, evaluations, points, NewTranscript("multiproof"), crs)
+	assert.NoError(err)
+	assert.NoError(MultiVerify(&proof, digests, values, points, NewTranscript("multiproof"), crs))
+
+	// serialization
+	var buf bytes.Buffer
+	written, err := proof.WriteTo(&buf)
+	assert.NoError(err)
+	assert.Equal(int64((1+2*8)*SizeDigest+fr.Bytes), written)
+	var proof2 MultiProof
+	read, err := proof2.ReadFrom(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Equal(written, read)
+	assert.NoError(MultiVerify(&proof2, digests, values, points, NewTranscript("multiproof"), crs))
+
+	// wrong value
+	values[2].SetOne()
+	assert.ErrorIs(MultiVerify(&proof, digests, values, points, NewTranscript("multiproof"), crs), ErrVerifyOpeningProof)
+	values[2] = evaluations[2][points[2]]
+
+	// wrong point
+	points[1] = 8
+	assert.ErrorIs(MultiVerify(&proof, digests, values, points, NewTranscript("multiproof"), crs), ErrVerifyOpeningProof)
+
+	_, err = MultiOpen(digests, evaluations[:1], points, NewTranscript("multiproof"), crs)
+	assert.ErrorIs(err, ErrLengthMismatch)
+	points[1] = VerkleDomainSize
+	_, err = MultiOpen(digests, evaluations, points, NewTranscript("multiproof"), crs)
+	assert.ErrorIs(err, ErrInvalidPoint)
+}
+
+func TestOpeningProofSerialization(t *testing.T) {
+	assert := require.New(t)
+
+	crs, err := NewCRS(16)
+	assert.NoError(err)
+
+	poly := randomPolynomial(16)
+	digest, err := Commit(poly, crs)
+	assert.NoError(err)
+	var point fr.Element
+	point.SetRandom()
+	proof, value, err := Open(poly, point, digest, NewTranscript("test"), crs)
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	_, err = proof.WriteTo(&buf)
+	assert.NoError(err)
+
+	// the number of rounds is given by the preallocated L
+	proof2 := OpeningProof{L: make([]Digest, 4)}
+	_, err = proof2.ReadFrom(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Equal(proof.A, proof2.A)
+	assert.NoError(Verify(digest, &proof2, point, value, NewTranscript("test"), crs))
+
+	var buf2 bytes.Buffer
+	_, err = proof2.WriteTo(&buf2)
+	assert.NoError(err)
+	assert.Equal(buf.Bytes(), buf2.Bytes())
+
+	// truncated input
+	_, err = proof2.ReadFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	assert.Error(err)
+}
+
+func TestDigest(t *testing.T) {
+	assert := require.New(t)
+
+	crs, err := NewCRS(4)
+	assert.NoError(err)
+
+	// P and P + (0, -1) = (-x, -y) represent the same element
+	var d1, d2, d3 Digest
+	d1.PointAffine = crs.Basis[1]
+	d2.X.Neg(&d1.X)
+	d2.Y.Neg(&d1.Y)
+	assert.True(d1.Equal(&d2))
+	assert.Equal(d1.Bytes(), d2.Bytes())
+	assert.Equal(d1.MapToScalarField(), d2.MapToScalarField())
+
+	b := d2.Bytes()
+	_, err = d3.SetBytes(b[:])
+	assert.NoError(err)
+	assert.True(d3.Equal(&d1))
+	assert.True(d3.IsOnCurve())
+
+	d3.PointAffine = crs.Basis[2]
+	assert.False(d3.Equal(&d1))
+
+	// x such that 1 - a⋅x² is not a square is rejected
+	curveParams := bandersnatch.GetEdwardsCurve()
+	var x, num fp.Element
+	for {
+		x.SetRandom()
+		num.Square(&x).Mul(&num, &curveParams.A).Neg(&num)
+		num.Add(&num, new(fp.Element).SetOne())
+		if num.Legendre() == -1 {
+			break
+		}
+	}
+	xBytes := x.Bytes()
+	_, err = d3.SetBytes(xBytes[:])
+	assert.ErrorIs(err, errNotInGroup)
+}
+
+func BenchmarkCommit(b *testing.B) {
+	crs, _ := NewCRS(VerkleDomainSize)
+	poly := randomPolynomial(VerkleDomainSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Commit(poly, crs)
+	}
+}
+
+func BenchmarkOpen(b *testing.B) {
+	crs, _ := NewCRS(VerkleDomainSize)
+	poly := randomPolynomial(VerkleDomainSize)
+	digest, _ := Commit(poly, crs)
+	var point fr.Element
+	point.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = Open(poly, point, digest, NewTranscript("bench"), crs)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	crs, _ := NewCRS(VerkleDomainSize)
+	poly := randomPolynomial(VerkleDomainSize)
+	digest, _ := Commit(poly, crs)
+	var point fr.Element
+	point.SetRandom()
+	proof, value, _ := Open(poly, point, digest, NewTranscript("bench"), crs)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Verify(digest, &proof, point, value, NewTranscript("bench"), crs)
+	}
+}
+
+func BenchmarkMultiOpen(b *testing.B) {
+	const nbPolynomials = 16
+	crs, _ := NewCRS(VerkleDomainSize)
+	digests := make([]Digest, nbPolynomials)
+	evaluations := make([][]fr.Element, nbPolynomials)
+	points := make([]uint64, nbPolynomials)
+	for i := range evaluations {
+		evaluations[i] = randomPolynomial(VerkleDomainSize)
+		digests[i], _ = Commit(evaluations[i], crs)
+		points[i] = uint64(i * 13 % VerkleDomainSize)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = MultiOpen(digests, evaluations, points, NewTranscript("bench"), crs)
+	}
+}
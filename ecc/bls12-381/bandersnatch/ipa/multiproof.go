@@ -0,0 +1,232 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+)
+
+// MultiProof proves the evaluations of several committed polynomials at points
+// of the domain, as used by Verkle trees.
+type MultiProof struct {
+	// IPA opening proof of h - g at the challenge t
+	IPA OpeningProof
+
+	// D commitment to g(X) = ∑ rⁱ⋅(fᵢ(X) - yᵢ) / (X - zᵢ)
+	D Digest
+}
+
+// MultiOpen computes a proof of the evaluations of the polynomials, given by
+// their evaluations on the domain, at the points of the domain.
+// digests are the commitments to the polynomials.
+func MultiOpen(digests []Digest, evaluations [][]fr.Element, points []uint64, transcript *Transcript, crs *CRS) (MultiProof, error) {
+	if len(digests) != len(evaluations) || len(digests) != len(points) {
+		return MultiProof{}, ErrLengthMismatch
+	}
+	n := crs.DomainSize()
+	for i := range evaluations {
+		if len(evaluations[i]) != n {
+			return MultiProof{}, ErrInvalidPolynomial
+		}
+		if points[i] >= uint64(n) {
+			return MultiProof{}, ErrInvalidPoint
+		}
+	}
+
+	transcript.DomainSep("multiproof")
+	for i := range digests {
+		var z fr.Element
+		z.SetUint64(points[i])
+		transcript.AppendPoint(&digests[i], "C")
+		transcript.AppendScalar(&z, "z")
+		transcript.AppendScalar(&evaluations[i][points[i]], "y")
+	}
+	r := transcript.ChallengeScalar("r")
+
+	// polynomials opened at the same point are aggregated first:
+	// grouped[z] = ∑_{zᵢ = z} rⁱ⋅fᵢ
+	grouped := make(map[uint64][]fr.Element)
+	order := make([]uint64, 0)
+	var ri, tmp fr.Element
+	ri.SetOne()
+	for i := range evaluations {
+		f, ok := grouped[points[i]]
+		if !ok {
+			f = make([]fr.Element, n)
+			grouped[points[i]] = f
+			order = append(order, points[i])
+		}
+		for j := range f {
+			tmp.Mul(&ri, &evaluations[i][j])
+			f[j].Add(&f[j], &tmp)
+		}
+		ri.Mul(&ri, &r)
+	}
+
+	// g = ∑ (grouped[z](X) - grouped[z](z)) / (X - z)
+	g := make([]fr.Element, n)
+	for _, z := range order {
+		q := crs.divideOnDomain(z, grouped[z])
+		for j := range g {
+			g[j].Add(&g[j], &q[j])
+		}
+	}
+
+	D, err := Commit(g, crs)
+	if err != nil {
+		return MultiProof{}, err
+	}
+	transcript.AppendPoint(&D, "D")
+	t := transcript.ChallengeScalar("t")
+
+	// h = ∑ grouped[z](X) / (t - z)
+	den := make([]fr.Element, len(order))
+	for i, z := range order {
+		den[i].SetUint64(z)
+		den[i].Sub(&t, &den[i])
+	}
+	den = fr.BatchInvert(den)
+	h := make([]fr.Element, n)
+	for i, z := range order {
+		f := grouped[z]
+		for j := range h {
+			tmp.Mul(&den[i], &f[j])
+			h[j].Add(&h[j], &tmp)
+		}
+	}
+
+	E, err := Commit(h, crs)
+	if err != nil {
+		return MultiProof{}, err
+	}
+	transcript.AppendPoint(&E, "E")
+
+	// h - g is committed to by E - D, and evaluates to g₂(t) at t
+	hMinusG := make([]fr.Element, n)
+	for j := range hMinusG {
+		hMinusG[j].Sub(&h[j], &g[j])
+	}
+	EMinusD := sub(&E, &D)
+
+	ipaProof, _, err := Open(hMinusG, t, EMinusD, transcript, crs)
+	if err != nil {
+		return MultiProof{}, err
+	}
+
+	return MultiProof{IPA: ipaProof, D: D}, nil
+}
+
+// MultiVerify verifies that proof proves that the polynomials committed to by
+// digests evaluate to claimedValues at points.
+func MultiVerify(proof *MultiProof, digests []Digest, claimedValues []fr.Element, points []uint64, transcript *Transcript, crs *CRS) error {
+	if len(digests) != len(claimedValues) || len(digests) != len(points) {
+		return ErrLengthMismatch
+	}
+	n := crs.DomainSize()
+	for i := range points {
+		if points[i] >= uint64(n) {
+			return ErrInvalidPoint
+		}
+	}
+
+	transcript.DomainSep("multiproof")
+	for i := range digests {
+		var z fr.Element
+		z.SetUint64(points[i])
+		transcript.AppendPoint(&digests[i], "C")
+		transcript.AppendScalar(&z, "z")
+		transcript.AppendScalar(&claimedValues[i], "y")
+	}
+	r := transcript.ChallengeScalar("r")
+
+	transcript.AppendPoint(&proof.D, "D")
+	t := transcript.ChallengeScalar("t")
+
+	// coefficients rⁱ / (t - zᵢ)
+	coeffs := make([]fr.Element, len(points))
+	for i := range points {
+		coeffs[i].SetUint64(points[i])
+		coeffs[i].Sub(&t, &coeffs[i])
+	}
+	coeffs = fr.BatchInvert(coeffs)
+	var ri fr.Element
+	ri.SetOne()
+	for i := range coeffs {
+		coeffs[i].Mul(&coeffs[i], &ri)
+		ri.Mul(&ri, &r)
+	}
+
+	// g₂(t) = ∑ rⁱ⋅yᵢ / (t - zᵢ), E = ∑ rⁱ / (t - zᵢ)⋅Cᵢ
+	g2t := innerProduct(coeffs, claimedValues)
+	_digests := make([]bandersnatch.PointAffine, len(digests))
+	for i := range digests {
+		_digests[i] = digests[i].PointAffine
+	}
+	E, err := multiExp(_digests, coeffs)
+	if err != nil {
+		return err
+	}
+	transcript.AppendPoint(&E, "E")
+
+	EMinusD := sub(&E, &proof.D)
+
+	return Verify(EMinusD, &proof.IPA, t, g2t, transcript, crs)
+}
+
+// sub returns a - b
+func sub(a, b *Digest) Digest {
+	var _a, _b bandersnatch.PointExtended
+	_a.FromAffine(&a.PointAffine)
+	_b.FromAffine(&b.PointAffine)
+	_b.Neg(&_b)
+	_a.Add(&_a, &_b)
+
+	var res Digest
+	res.FromExtended(&_a)
+	return res
+}
+
+// WriteTo writes the serialization of the proof to w: D followed by the IPA
+// opening proof, as in go-ipa.
+// It returns the number of bytes written.
+func (proof *MultiProof) WriteTo(w io.Writer) (int64, error) {
+	buf := proof.D.Bytes()
+	m, err := w.Write(buf[:])
+	n := int64(m)
+	if err != nil {
+		return n, err
+	}
+	m2, err := proof.IPA.WriteTo(w)
+	return n + m2, err
+}
+
+// ReadFrom reads a proof serialized by WriteTo from r.
+// It returns the number of bytes read.
+func (proof *MultiProof) ReadFrom(r io.Reader) (int64, error) {
+	var buf [SizeDigest]byte
+	m, err := io.ReadFull(r, buf[:])
+	n := int64(m)
+	if err != nil {
+		return n, err
+	}
+	if _, err = proof.D.SetBytes(buf[:]); err != nil {
+		return n, err
+	}
+	m2, err := proof.IPA.ReadFrom(r)
+	return n + m2, err
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"crypto/sha256"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+)
+
+// Transcript is the Fiat-Shamir transcript of the IPA and of the multiproofs.
+// Messages are absorbed in a SHA-256 state together with their label, and
+// challenges are squeezed by hashing the state, which is then reset to the
+// challenge itself.
+type Transcript struct {
+	state hash.Hash
+}
+
+// NewTranscript returns a new transcript, domain separated by label
+func NewTranscript(label string) *Transcript {
+	t := &Transcript{state: sha256.New()}
+	t.state.Write([]byte(label))
+	return t
+}
+
+// DomainSep adds a domain separator to the transcript
+func (t *Transcript) DomainSep(label string) {
+	t.state.Write([]byte(label))
+}
+
+func (t *Transcript) appendMessage(message []byte, label string) {
+	t.state.Write([]byte(label))
+	t.state.Write(message)
+}
+
+// AppendScalar appends a scalar, in little endian, to the transcript
+func (t *Transcript) AppendScalar(scalar *fr.Element, label string) {
+	var buf [fr.Bytes]byte
+	fr.LittleEndian.PutElement(&buf, *scalar)
+	t.appendMessage(buf[:], label)
+}
+
+// AppendPoint appends the serialization of a point to the transcript
+func (t *Transcript) AppendPoint(point *Digest, label string) {
+	buf := point.Bytes()
+	t.appendMessage(buf[:], label)
+}
+
+// ChallengeScalar returns a challenge derived from the transcript
+func (t *Transcript) ChallengeScalar(label string) fr.Element {
+	t.DomainSep(label)
+	digest := t.state.Sum(nil)
+
+	var res fr.Element
+	setBytesLE(&res, digest)
+
+	// the state is summarized by the challenge
+	t.state.Reset()
+	t.AppendScalar(&res, label)
+
+	return res
+}
+
+// setBytesLE sets z from the little endian integer b, reduced modulo the
+// order
+func setBytesLE(z *fr.Element, b []byte) {
+	buf := make([]byte, len(b))
+	for i := range b {
+		buf[len(b)-1-i] = b[i]
+	}
+	z.SetBytes(buf)
+}
@@ -0,0 +1,450 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/signature"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	sizeFr = fr.Bytes
+	sizeG1 = bls12381.SizeOfG1AffineCompressed
+	sizeG2 = bls12381.SizeOfG2AffineCompressed
+)
+
+var (
+	errShortIKM          = errors.New("input keying material must be at least 32 bytes")
+	errInvalidPublicKey  = errors.New("invalid public key")
+	errSuiteMismatch     = errors.New("public keys belong to different suites")
+	errNotPop            = errors.New("operation requires a proof of possession suite")
+	errDuplicateMessages = errors.New("messages must be distinct in the basic scheme")
+	errLengthMismatch    = errors.New("number of public keys and messages differ")
+	errEmptyInput        = errors.New("nothing to aggregate")
+)
+
+// Suite identifies a BLS ciphersuite, that is a variant (which group the
+// public keys live in) together with a scheme (how rogue-key attacks are
+// prevented when aggregating).
+type Suite uint8
+
+const (
+	// MinPkBasic is the min-pk variant with the basic scheme.
+	MinPkBasic Suite = iota
+	// MinPkAug is the min-pk variant with the message augmentation scheme.
+	MinPkAug
+	// MinPkPop is the min-pk variant with the proof of possession scheme.
+	MinPkPop
+	// MinSigBasic is the min-sig variant with the basic scheme.
+	MinSigBasic
+	// MinSigAug is the min-sig variant with the message augmentation scheme.
+	MinSigAug
+	// MinSigPop is the min-sig variant with the proof of possession scheme.
+	MinSigPop
+)
+
+const (
+	schemeBasic = iota
+	schemeAug
+	schemePop
+)
+
+var schemeTags = [...]string{schemeBasic: "NUL_", schemeAug: "AUG_", schemePop: "POP_"}
+
+const (
+	hashSuiteG1 = "BLS12381G1_XMD:SHA-256_SSWU_RO_"
+	hashSuiteG2 = "BLS12381G2_XMD:SHA-256_SSWU_RO_"
+
+	keyGenSalt = "BLS-SIG-KEYGEN-SALT-"
+)
+
+// isMinPk returns true if public keys are in G1 and signatures in G2.
+func (s Suite) isMinPk() bool {
+	return s <= MinPkPop
+}
+
+func (s Suite) scheme() int {
+	return int(s % 3)
+}
+
+func (s Suite) hashSuite() string {
+	if s.isMinPk() {
+		return hashSuiteG2
+	}
+	return hashSuiteG1
+}
+
+// DST returns the domain separation tag used to hash messages to the
+// signature group, i.e. the ciphersuite ID of the IETF draft.
+func (s Suite) DST() []byte {
+	return []byte("BLS_SIG_" + s.hashSuite() + schemeTags[s.scheme()])
+}
+
+// PopDST returns the domain separation tag used to hash public keys when
+// proving possession of the secret key.
+func (s Suite) PopDST() []byte {
+	return []byte("BLS_POP_" + s.hashSuite() + schemeTags[schemePop])
+}
+
+// PublicKey represents a BLS public key
+type PublicKey struct {
+	Suite Suite
+	A     bls12381.G1Affine // public key of the min-pk variant
+	B     bls12381.G2Affine // public key of the min-sig variant
+}
+
+// PrivateKey represents a BLS private key
+type PrivateKey struct {
+	PublicKey PublicKey
+	scalar    [sizeFr]byte // secret scalar, in big Endian
+}
+
+// KeyGen derives a private key from the input keying material ikm (at least
+// 32 bytes) and the optional keyInfo, following the KeyGen procedure of the
+// IETF draft:
+//
+//	salt = H(salt)
+//	PRK = HKDF-Extract(salt, ikm ∥ I2OSP(0, 1))
+//	OKM = HKDF-Expand(PRK, keyInfo ∥ I2OSP(L, 2), L)
+//	sk = OS2IP(OKM) mod r
+//
+// repeated until sk ≠ 0, with L = ⌈3⋅⌈log₂(r)⌉/16⌉.
+func (s Suite) KeyGen(ikm, keyInfo []byte) (*PrivateKey, error) {
+	if len(ikm) < 32 {
+		return nil, errShortIKM
+	}
+	const L = (3*fr.Bits + 15) / 16
+
+	secret := make([]byte, len(ikm)+1)
+	copy(secret, ikm)
+	info := make([]byte, len(keyInfo)+2)
+	copy(info, keyInfo)
+	info[len(keyInfo)] = byte(L >> 8)
+	info[len(keyInfo)+1] = byte(L)
+
+	salt := []byte(keyGenSalt)
+	okm := make([]byte, L)
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), okm); err != nil {
+			return nil, err
+		}
+		sk.SetBytes(okm).Mod(sk, fr.Modulus())
+	}
+
+	return s.newPrivateKey(sk), nil
+}
+
+// GenerateKey generates a public and private key pair, drawing the input
+// keying material of KeyGen from rand.
+func (s Suite) GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	ikm := make([]byte, 32)
+	if _, err := io.ReadFull(rand, ikm); err != nil {
+		return nil, err
+	}
+	return s.KeyGen(ikm, nil)
+}
+
+func (s Suite) newPrivateKey(sk *big.Int) *PrivateKey {
+	privateKey := new(PrivateKey)
+	sk.FillBytes(privateKey.scalar[:])
+	privateKey.PublicKey.Suite = s
+	if s.isMinPk() {
+		privateKey.PublicKey.A.ScalarMultiplicationBase(sk)
+	} else {
+		privateKey.PublicKey.B.ScalarMultiplicationBase(sk)
+	}
+	return privateKey
+}
+
+// Equal compares 2 public keys
+func (pub *PublicKey) Equal(x signature.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	bpk := pub.Bytes()
+	bxx := xx.Bytes()
+	return pub.Suite == xx.Suite && subtle.ConstantTimeCompare(bpk, bxx) == 1
+}
+
+// Public returns the public key associated to the private key.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	var pub PublicKey
+	pub.Suite = privKey.PublicKey.Suite
+	pub.A.Set(&privKey.PublicKey.A)
+	pub.B.Set(&privKey.PublicKey.B)
+	return &pub
+}
+
+// isValid implements KeyValidate of the IETF draft: the public key must be a
+// non-identity point of the prime order subgroup.
+func (pub *PublicKey) isValid() bool {
+	if pub.Suite.isMinPk() {
+		return !pub.A.IsInfinity() && pub.A.IsInSubGroup()
+	}
+	return !pub.B.IsInfinity() && pub.B.IsInSubGroup()
+}
+
+// Sign performs the BLS signature
+//
+// sig = sk ⋅ H(m)
+//
+// where H hashes to the signature group with the suite DST. In the message
+// augmentation scheme, the message is prefixed with the public key. If hFunc
+// is provided, the message is first hashed with hFunc.
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	msg, err := prehash(message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+	suite := privKey.PublicKey.Suite
+	if suite.scheme() == schemeAug {
+		msg = append(privKey.PublicKey.Bytes(), msg...)
+	}
+	return privKey.sign(msg, suite.DST())
+}
+
+// ProvePossession returns a proof of possession of the private key, that is
+// a signature of the public key with the PopDST of the suite.
+func (privKey *PrivateKey) ProvePossession() ([]byte, error) {
+	return privKey.sign(privKey.PublicKey.Bytes(), privKey.PublicKey.Suite.PopDST())
+}
+
+func (privKey *PrivateKey) sign(msg, dst []byte) ([]byte, error) {
+	var s big.Int
+	s.SetBytes(privKey.scalar[:])
+	if privKey.PublicKey.Suite.isMinPk() {
+		h, err := bls12381.HashToG2(msg, dst)
+		if err != nil {
+			return nil, err
+		}
+		var sig bls12381.G2Affine
+		sig.ScalarMultiplication(&h, &s)
+		res := sig.Bytes()
+		return res[:], nil
+	}
+	h, err := bls12381.HashToG1(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	var sig bls12381.G1Affine
+	sig.ScalarMultiplication(&h, &s)
+	res := sig.Bytes()
+	return res[:], nil
+}
+
+// Verify validates the BLS signature
+//
+// e(pk, H(m)) ?= e(g1, sig) (min-pk) or e(H(m), pk) ?= e(sig, g2) (min-sig)
+func (pub *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+	msg, err := prehash(message, hFunc)
+	if err != nil {
+		return false, err
+	}
+	if pub.Suite.scheme() == schemeAug {
+		msg = append(pub.Bytes(), msg...)
+	}
+	return verify([]*PublicKey{pub}, [][]byte{msg}, sigBin, pub.Suite.DST())
+}
+
+// VerifyPossession checks a proof of possession of the private key associated
+// to pub, as returned by ProvePossession.
+func (pub *PublicKey) VerifyPossession(proof []byte) (bool, error) {
+	return verify([]*PublicKey{pub}, [][]byte{pub.Bytes()}, proof, pub.Suite.PopDST())
+}
+
+// Aggregate aggregates signatures of the suite into a single signature
+func (s Suite) Aggregate(signatures [][]byte) ([]byte, error) {
+	if len(signatures) == 0 {
+		return nil, errEmptyInput
+	}
+	if s.isMinPk() {
+		var acc, p bls12381.G2Jac
+		var sig bls12381.G2Affine
+		for i := range signatures {
+			if _, err := sig.SetBytes(signatures[i]); err != nil {
+				return nil, err
+			}
+			acc.AddAssign(p.FromAffine(&sig))
+		}
+		sig.FromJacobian(&acc)
+		res := sig.Bytes()
+		return res[:], nil
+	}
+	var acc, p bls12381.G1Jac
+	var sig bls12381.G1Affine
+	for i := range signatures {
+		if _, err := sig.SetBytes(signatures[i]); err != nil {
+			return nil, err
+		}
+		acc.AddAssign(p.FromAffine(&sig))
+	}
+	sig.FromJacobian(&acc)
+	res := sig.Bytes()
+	return res[:], nil
+}
+
+// AggregatePublicKeys returns the sum of the public keys. All keys must
+// belong to the same suite.
+func AggregatePublicKeys(publicKeys []*PublicKey) (*PublicKey, error) {
+	if len(publicKeys) == 0 {
+		return nil, errEmptyInput
+	}
+	suite := publicKeys[0].Suite
+	var a, p1 bls12381.G1Jac
+	var b, p2 bls12381.G2Jac
+	for _, pk := range publicKeys {
+		if pk.Suite != suite {
+			return nil, errSuiteMismatch
+		}
+		if !pk.isValid() {
+			return nil, errInvalidPublicKey
+		}
+		if suite.isMinPk() {
+			a.AddAssign(p1.FromAffine(&pk.A))
+		} else {
+			b.AddAssign(p2.FromAffine(&pk.B))
+		}
+	}
+	res := &PublicKey{Suite: suite}
+	if suite.isMinPk() {
+		res.A.FromJacobian(&a)
+	} else {
+		res.B.FromJacobian(&b)
+	}
+	return res, nil
+}
+
+// FastAggregateVerify verifies an aggregate signature of the same message by
+// all the public keys. It is only available in the proof of possession
+// scheme, where rogue keys are ruled out by VerifyPossession.
+func FastAggregateVerify(publicKeys []*PublicKey, message, sig []byte) (bool, error) {
+	aggPk, err := AggregatePublicKeys(publicKeys)
+	if err != nil {
+		return false, err
+	}
+	if aggPk.Suite.scheme() != schemePop {
+		return false, errNotPop
+	}
+	return verify([]*PublicKey{aggPk}, [][]byte{message}, sig, aggPk.Suite.DST())
+}
+
+// AggregateVerify verifies an aggregate signature of messages[i] by
+// publicKeys[i], for all i, with a single multi-pairing. In the basic scheme
+// the messages must be distinct; in the message augmentation scheme they are
+// prefixed with the corresponding public key.
+func AggregateVerify(publicKeys []*PublicKey, messages [][]byte, sig []byte) (bool, error) {
+	if len(publicKeys) != len(messages) {
+		return false, errLengthMismatch
+	}
+	if len(publicKeys) == 0 {
+		return false, errEmptyInput
+	}
+	suite := publicKeys[0].Suite
+	msgs := messages
+	switch suite.scheme() {
+	case schemeBasic:
+		seen := make(map[string]struct{}, len(messages))
+		for _, m := range messages {
+			if _, ok := seen[string(m)]; ok {
+				return false, errDuplicateMessages
+			}
+			seen[string(m)] = struct{}{}
+		}
+	case schemeAug:
+		msgs = make([][]byte, len(messages))
+		for i := range messages {
+			msgs[i] = append(publicKeys[i].Bytes(), messages[i]...)
+		}
+	}
+	return verify(publicKeys, msgs, sig, suite.DST())
+}
+
+// verify checks that sig is an aggregate signature of msgs[i] by
+// publicKeys[i] under dst, that is
+//
+// ∏ e(pkᵢ, H(mᵢ)) ?= e(g1, sig) (min-pk) or ∏ e(H(mᵢ), pkᵢ) ?= e(sig, g2) (min-sig)
+func verify(publicKeys []*PublicKey, msgs [][]byte, sigBin, dst []byte) (bool, error) {
+	suite := publicKeys[0].Suite
+	for _, pk := range publicKeys {
+		if pk.Suite != suite {
+			return false, errSuiteMismatch
+		}
+		if !pk.isValid() {
+			return false, errInvalidPublicKey
+		}
+	}
+	n := len(publicKeys)
+	P := make([]bls12381.G1Affine, n+1)
+	Q := make([]bls12381.G2Affine, n+1)
+	_, _, g1, g2 := bls12381.Generators()
+
+	if suite.isMinPk() {
+		if _, err := Q[n].SetBytes(sigBin); err != nil {
+			return false, err
+		}
+		P[n].Neg(&g1)
+		for i := 0; i < n; i++ {
+			h, err := bls12381.HashToG2(msgs[i], dst)
+			if err != nil {
+				return false, err
+			}
+			P[i].Set(&publicKeys[i].A)
+			Q[i].Set(&h)
+		}
+	} else {
+		if _, err := P[n].SetBytes(sigBin); err != nil {
+			return false, err
+		}
+		Q[n].Set(&g2)
+		for i := 0; i < n; i++ {
+			h, err := bls12381.HashToG1(msgs[i], dst)
+			if err != nil {
+				return false, err
+			}
+			P[i].Neg(&h)
+			Q[i].Set(&publicKeys[i].B)
+		}
+	}
+
+	return bls12381.PairingCheck(P, Q)
+}
+
+// prehash hashes the message with hFunc if provided, else returns the message
+// unchanged.
+func prehash(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	hFunc.Reset()
+	if _, err := hFunc.Write(message); err != nil {
+		return nil, err
+	}
+	return hFunc.Sum(nil), nil
+}
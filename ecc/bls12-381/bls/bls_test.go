@@ -0,0 +1,272 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+var suites = []Suite{MinPkBasic, MinPkAug, MinPkPop, MinSigBasic, MinSigAug, MinSigPop}
+
+func genSuite() gopter.Gen {
+	return gen.UInt8Range(uint8(MinPkBasic), uint8(MinSigPop)).Map(func(s uint8) Suite {
+		return Suite(s)
+	})
+}
+
+func TestBLS(t *testing.T) {
+
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[BLS12-381] test the signing and verification", prop.ForAll(
+		func(suite Suite) bool {
+
+			privKey, _ := suite.GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing BLS")
+			hFunc := sha256.New()
+			sig, _ := privKey.Sign(msg, hFunc)
+			flag, _ := publicKey.Verify(sig, msg, hFunc)
+
+			return flag
+		},
+		genSuite(),
+	))
+
+	properties.Property("[BLS12-381] test the signing and verification (pre-hashed)", prop.ForAll(
+		func(suite Suite) bool {
+
+			privKey, _ := suite.GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing BLS")
+			sig, _ := privKey.Sign(msg, nil)
+			flag, _ := publicKey.Verify(sig, msg, nil)
+
+			return flag
+		},
+		genSuite(),
+	))
+
+	properties.Property("[BLS12-381] a signature should not verify for another message or key", prop.ForAll(
+		func(suite Suite) bool {
+
+			privKey, _ := suite.GenerateKey(rand.Reader)
+			otherKey, _ := suite.GenerateKey(rand.Reader)
+
+			sig, _ := privKey.Sign([]byte("testing BLS"), nil)
+			flag1, _ := privKey.PublicKey.Verify(sig, []byte("testing BLS!"), nil)
+			flag2, _ := otherKey.PublicKey.Verify(sig, []byte("testing BLS"), nil)
+
+			return !flag1 && !flag2
+		},
+		genSuite(),
+	))
+
+	properties.Property("[BLS12-381] test the proof of possession", prop.ForAll(
+		func(suite Suite) bool {
+
+			privKey, _ := suite.GenerateKey(rand.Reader)
+			otherKey, _ := suite.GenerateKey(rand.Reader)
+
+			proof, _ := privKey.ProvePossession()
+			flag1, _ := privKey.PublicKey.VerifyPossession(proof)
+			flag2, _ := otherKey.PublicKey.VerifyPossession(proof)
+
+			return flag1 && !flag2
+		},
+		genSuite(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestAggregation(t *testing.T) {
+	t.Parallel()
+	const nbSigners = 5
+
+	for _, suite := range suites {
+		keys := make([]*PublicKey, nbSigners)
+		sigs := make([][]byte, nbSigners)
+		sameMsgSigs := make([][]byte, nbSigners)
+		msgs := make([][]byte, nbSigners)
+		sameMsg := []byte("same message")
+		for i := range keys {
+			privKey, err := suite.GenerateKey(rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			keys[i] = &privKey.PublicKey
+			msgs[i] = []byte{byte(i)}
+			if sigs[i], err = privKey.Sign(msgs[i], nil); err != nil {
+				t.Fatal(err)
+			}
+			if sameMsgSigs[i], err = privKey.Sign(sameMsg, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		aggSig, err := suite.Aggregate(sigs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ok, err := AggregateVerify(keys, msgs, aggSig)
+		if err != nil || !ok {
+			t.Fatal("aggregate signature should verify", suite)
+		}
+		msgs[0] = []byte("tampered")
+		if ok, _ = AggregateVerify(keys, msgs, aggSig); ok {
+			t.Fatal("aggregate signature should not verify for a tampered message", suite)
+		}
+
+		aggSig, err = suite.Aggregate(sameMsgSigs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sameMsgs := make([][]byte, nbSigners)
+		for i := range sameMsgs {
+			sameMsgs[i] = sameMsg
+		}
+		ok, err = AggregateVerify(keys, sameMsgs, aggSig)
+		if suite.scheme() == schemeBasic {
+			if err != errDuplicateMessages {
+				t.Fatal("basic scheme should reject duplicate messages", suite)
+			}
+		} else if err != nil || !ok {
+			t.Fatal("aggregate signature of the same message should verify", suite)
+		}
+
+		ok, err = FastAggregateVerify(keys, sameMsg, aggSig)
+		if suite.scheme() != schemePop {
+			if err != errNotPop {
+				t.Fatal("fast aggregate verification requires the proof of possession scheme", suite)
+			}
+			continue
+		}
+		if err != nil || !ok {
+			t.Fatal("fast aggregate verification should succeed", suite)
+		}
+		if ok, _ = FastAggregateVerify(keys[1:], sameMsg, aggSig); ok {
+			t.Fatal("fast aggregate verification should fail with a missing signer", suite)
+		}
+	}
+}
+
+func TestSuiteMismatch(t *testing.T) {
+	privKey1, _ := MinPkPop.GenerateKey(rand.Reader)
+	privKey2, _ := MinPkAug.GenerateKey(rand.Reader)
+	if _, err := AggregatePublicKeys([]*PublicKey{&privKey1.PublicKey, &privKey2.PublicKey}); err != errSuiteMismatch {
+		t.Fatal("public keys of different suites should not aggregate")
+	}
+}
+
+func TestDST(t *testing.T) {
+	expected := map[Suite]string{
+		MinPkBasic:  "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_",
+		MinPkAug:    "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_AUG_",
+		MinPkPop:    "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_",
+		MinSigBasic: "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_",
+		MinSigAug:   "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_AUG_",
+		MinSigPop:   "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_",
+	}
+	for suite, dst := range expected {
+		if string(suite.DST()) != dst {
+			t.Fatalf("wrong DST: got %s, expected %s", suite.DST(), dst)
+		}
+	}
+	if string(MinPkPop.PopDST()) != "BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_" {
+		t.Fatal("wrong PoP DST")
+	}
+}
+
+func TestKeyGenVector(t *testing.T) {
+	// test case 0 of EIP-2333, whose derive_master_SK is the KeyGen of the
+	// IETF draft with an empty key info.
+	seed, _ := hex.DecodeString("c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04")
+	expected, _ := new(big.Int).SetString("6083874454709270928345386274498605044986640685124978867557563392430687146096", 10)
+
+	privKey, err := MinPkPop.KeyGen(seed, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if new(big.Int).SetBytes(privKey.scalar[:]).Cmp(expected) != 0 {
+		t.Fatal("wrong master secret key")
+	}
+}
+
+func TestSignVector(t *testing.T) {
+	// Ethereum consensus-spec test vector (bls/sign), which uses the min-pk
+	// variant with the proof of possession scheme.
+	sk, _ := hex.DecodeString("263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3")
+	msg := make([]byte, 32)
+	expected := "b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55"
+
+	privKey := MinPkPop.newPrivateKey(new(big.Int).SetBytes(sk))
+	sig, err := privKey.Sign(msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(sig) != expected {
+		t.Fatal("wrong signature")
+	}
+	ok, err := privKey.PublicKey.Verify(sig, msg, nil)
+	if err != nil || !ok {
+		t.Fatal("signature should verify")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkSignBLS(b *testing.B) {
+
+	privKey, _ := MinPkPop.GenerateKey(rand.Reader)
+
+	msg := []byte("benchmarking BLS sign()")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.Sign(msg, nil)
+	}
+}
+
+func BenchmarkVerifyBLS(b *testing.B) {
+
+	privKey, _ := MinPkPop.GenerateKey(rand.Reader)
+	msg := []byte("benchmarking BLS sign()")
+	sig, _ := privKey.Sign(msg, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.PublicKey.Verify(sig, msg, nil)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package bulletproofs implements Bulletproofs range proofs on bls12-381.
+//
+// A range proof shows that the values committed to in Pedersen commitments
+// V = v⋅G + γ⋅H lie in [0, 2ⁿ), without revealing them. Several commitments
+// can be proven at once with an aggregated proof, whose size grows
+// logarithmically with the total number of bits, thanks to the inner product
+// argument.
+//
+// The generators are derived by hashing to the curve, so that no trusted setup
+// is needed. The challenges are derived with Fiat-Shamir.
+//
+// See https://eprint.iacr.org/2017/1066.pdf, sections 3 and 4.
+package bulletproofs
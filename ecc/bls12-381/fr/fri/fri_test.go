@@ -17,11 +17,15 @@
 package fri
 
 import (
+	"bytes"
 	"crypto/sha256"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
@@ -212,6 +216,347 @@ func TestFRI(t *testing.T) {
 
 }
 
+func TestScheme(t *testing.T) {
+
+	size := uint64(1024)
+	p := randomPolynomial(size, 42)
+
+	for _, k := range []int{2, 4, 8, 16} {
+		// 1024 is a power of 2 and 4, not of 8 and 16
+		for _, d := range []int{k - 1, 7} {
+			s, err := New(size, sha256.New(), WithFoldingFactor(k), WithBlowup(4), WithQueries(20), WithGrinding(4), WithFinalPolyDegree(d))
+			if err != nil {
+				t.Fatal(err)
+			}
+			proof, err := s.BuildProofOfProximity(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.VerifyProofOfProximity(proof); err != nil {
+				t.Fatalf("k=%d, d=%d: %v", k, d, err)
+			}
+
+			// tamper with the final polynomial
+			proof.FinalPoly[0].SetOne()
+			if err := s.VerifyProofOfProximity(proof); err == nil {
+				t.Fatalf("k=%d, d=%d: tampered final polynomial accepted", k, d)
+			}
+		}
+	}
+
+	// a polynomial of too high degree should be rejected
+	s, err := New(size, sha256.New(), WithFoldingFactor(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := make([]fr.Element, 2*size)
+	copy(q, p)
+	q[2*size-1].SetOne()
+	if _, err := s.BuildProofOfProximity(q); !errors.Is(err, ErrPolySize) {
+		t.Fatal("expected ErrPolySize")
+	}
+	s2, err := New(4*size, sha256.New(), WithFoldingFactor(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := s2.BuildProofOfProximity(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s2.VerifyProofOfProximity(proof); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.VerifyProofOfProximity(proof); err == nil {
+		t.Fatal("proof for a larger polynomial accepted")
+	}
+}
+
+func TestSchemeParameters(t *testing.T) {
+
+	invalid := [][]Option{
+		{WithBlowup(1)},
+		{WithBlowup(6)},
+		{WithFoldingFactor(3)},
+		{WithQueries(0)},
+		{WithGrinding(maxGrindingBits + 1)},
+		{WithFinalPolyDegree(-1)},
+		{WithFinalPolyDegree(1024)},
+	}
+	for i, opts := range invalid {
+		if _, err := New(1024, sha256.New(), opts...); !errors.Is(err, ErrInvalidParameters) {
+			t.Fatalf("options %d: expected ErrInvalidParameters, got %v", i, err)
+		}
+	}
+
+	// the polynomials can not be folded by 4 from 8 down to a constant, nor by
+	// 8 or 16 from 1024
+	if _, err := New(8, sha256.New(), WithFoldingFactor(4)); !errors.Is(err, ErrInvalidParameters) {
+		t.Fatalf("expected ErrInvalidParameters, got %v", err)
+	}
+	for _, k := range []int{8, 16} {
+		if _, err := New(1024, sha256.New(), WithFoldingFactor(k), WithFinalPolyDegree(0)); !errors.Is(err, ErrInvalidParameters) {
+			t.Fatalf("k=%d: expected ErrInvalidParameters, got %v", k, err)
+		}
+	}
+	s, err := New(8, sha256.New(), WithFoldingFactor(4), WithFinalPolyDegree(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.NbFoldings() != 1 {
+		t.Fatalf("expected 1 folding, got %d", s.NbFoldings())
+	}
+
+	// the default number of queries reaches 128 bits of conjectured security
+	s, err = New(1024, sha256.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.NbQueries() != 43 || s.NbFoldings() != 10 {
+		t.Fatalf("unexpected default parameters: %d queries, %d foldings", s.NbQueries(), s.NbFoldings())
+	}
+
+	// grinding and queries increase the security, the proven security is
+	// smaller than the conjectured one
+	c0, p0 := s.SoundnessBits()
+	s, err = New(1024, sha256.New(), WithQueries(s.NbQueries()+10), WithGrinding(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c1, p1 := s.SoundnessBits()
+	if p0 > c0 || p1 > c1 || c1 < c0 || p1 < p0 {
+		t.Fatalf("unexpected soundness: (%d, %d) then (%d, %d)", c0, p0, c1, p1)
+	}
+
+	// a larger folding factor reduces the number of foldings
+	s, err = New(1024, sha256.New(), WithFoldingFactor(16), WithFinalPolyDegree(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.NbFoldings() != 2 {
+		t.Fatalf("expected 2 foldings, got %d", s.NbFoldings())
+	}
+}
+
+func TestBatch(t *testing.T) {
+
+	size := uint64(256)
+	columns := make([][]fr.Element, 5)
+	for c := range columns {
+		columns[c] = randomPolynomial(size-uint64(c), int32(c+2))
+	}
+
+	s, err := New(size, sha256.New(), WithFoldingFactor(4), WithQueries(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc, err := s.CommitColumns(columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var z1, z2 fr.Element
+	z1.SetUint64(7)
+	z2.SetUint64(11)
+	for _, points := range [][]fr.Element{nil, {z1}, {z1, z2}} {
+		proof, err := s.BuildBatchProof(cc, points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.VerifyBatchProof(cc.Root(), points, proof); err != nil {
+			t.Fatalf("%d points: %v", len(points), err)
+		}
+
+		if len(points) > 0 {
+			// the claimed evaluations are correct
+			var y fr.Element
+			for k := len(columns[3]) - 1; k >= 0; k-- {
+				y.Mul(&y, &points[0]).Add(&y, &columns[3][k])
+			}
+			if !y.Equal(&proof.Evaluations[0][3]) {
+				t.Fatal("wrong claimed evaluation")
+			}
+
+			// a wrong claim should be rejected
+			proof.Evaluations[0][3].Add(&proof.Evaluations[0][3], &z1)
+			if err := s.VerifyBatchProof(cc.Root(), points, proof); err == nil {
+				t.Fatal("wrong claimed evaluation accepted")
+			}
+		}
+	}
+
+	// the proof should not verify against another commitment
+	columns[0][0].SetOne()
+	other, err := s.CommitColumns(columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := s.BuildBatchProof(cc, []fr.Element{z1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.VerifyBatchProof(other.Root(), []fr.Element{z1}, proof); err == nil {
+		t.Fatal("proof accepted for another commitment")
+	}
+
+	// points of the domain are forbidden
+	if _, err := s.BuildBatchProof(cc, []fr.Element{s.domain.Generator}); !errors.Is(err, ErrPointInDomain) {
+		t.Fatal("expected ErrPointInDomain")
+	}
+}
+
+func TestBatchForgery(t *testing.T) {
+
+	size := uint64(256)
+	p := randomPolynomial(size, 5)
+	s, err := New(size, sha256.New(), WithQueries(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc, err := s.CommitColumns([][]fr.Element{p})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the honest DEEP quotient g = (p - p(z))/(X - z)
+	var z, y fr.Element
+	z.SetUint64(7)
+	for k := len(p) - 1; k >= 0; k-- {
+		y.Mul(&y, &z).Add(&y, &p[k])
+	}
+	n := s.domain.Cardinality
+	g := make([]fr.Element, n)
+	var x, d fr.Element
+	x.SetOne()
+	for i := range g {
+		d.Sub(&x, &z).Inverse(&d)
+		g[i].Sub(&cc.evals[0][i], &y).Mul(&g[i], &d)
+		x.Mul(&x, &s.domain.Generator)
+	}
+
+	// a proof of proximity of g that is not bound to the commitment gives away
+	// the queries: the column is then patched at the queried positions to
+	// match the false claim p(z)+1 there
+	friProof, queries, err := s.proveEvaluations(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var one fr.Element
+	one.SetOne()
+	patched := make([]fr.Element, n)
+	copy(patched, cc.evals[0])
+	positions := s.fiberPositions(queries)
+	for _, i := range positions {
+		patched[i].Add(&patched[i], &one)
+	}
+	rows := make([][]byte, n)
+	for i := range rows {
+		b := patched[i].Bytes()
+		rows[i] = b[:]
+	}
+	tree := merkletree.NewRetainedTree(s.h, rows)
+
+	var proof BatchProof
+	proof.Evaluations = [][]fr.Element{{*new(fr.Element).Add(&y, &one)}}
+	proof.Proof = friProof
+	proof.Rows, err = tree.ProveMulti(positions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.VerifyBatchProof(tree.Root(), []fr.Element{z}, proof); err == nil {
+		t.Fatal("forged evaluation accepted")
+	}
+}
+
+func TestSerialization(t *testing.T) {
+
+	size := uint64(64)
+	p := randomPolynomial(size, 3)
+
+	// roundTrip encodes src, decodes it in dst and checks the byte counts
+	roundTrip := func(src io.WriterTo, dst io.ReaderFrom) {
+		var buf bytes.Buffer
+		written, err := src.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if written != int64(buf.Len()) {
+			t.Fatal("wrong number of bytes written")
+		}
+		b := append([]byte(nil), buf.Bytes()...)
+		read, err := dst.ReadFrom(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read != written {
+			t.Fatal("wrong number of bytes read")
+		}
+
+		// the encoding is versioned
+		b[0]++
+		if _, err := dst.ReadFrom(bytes.NewReader(b)); err != ErrSerializationVersion {
+			t.Fatal("expected ErrSerializationVersion")
+		}
+		b[0]--
+		if _, err := dst.ReadFrom(bytes.NewReader(b[:len(b)-1])); err == nil {
+			t.Fatal("truncated encoding decoded")
+		}
+		if _, err := dst.ReadFrom(bytes.NewReader(b)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	iop := RADIX_2_FRI.New(size, sha256.New())
+	pp, err := iop.BuildProofOfProximity(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ppDecoded ProofOfProximity
+	roundTrip(&pp, &ppDecoded)
+	if err := iop.VerifyProofOfProximity(ppDecoded); err != nil {
+		t.Fatal(err)
+	}
+
+	openingProof, err := iop.Open(p, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var openingProofDecoded OpeningProof
+	roundTrip(&openingProof, &openingProofDecoded)
+	if err := iop.VerifyOpening(5, openingProofDecoded, ppDecoded); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := New(size, sha256.New(), WithFoldingFactor(4), WithQueries(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := s.BuildProofOfProximity(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var proofDecoded Proof
+	roundTrip(&proof, &proofDecoded)
+	if err := s.VerifyProofOfProximity(proofDecoded); err != nil {
+		t.Fatal(err)
+	}
+
+	cc, err := s.CommitColumns([][]fr.Element{p, p[:10]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var z fr.Element
+	z.SetUint64(5)
+	batchProof, err := s.BuildBatchProof(cc, []fr.Element{z})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var batchProofDecoded BatchProof
+	roundTrip(&batchProof, &batchProofDecoded)
+	if err := s.VerifyBatchProof(cc.Root(), []fr.Element{z}, batchProofDecoded); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // Benchmarks
 
 func BenchmarkProximityVerification(b *testing.B) {
@@ -0,0 +1,329 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fri
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// serializationVersion is the first byte of the binary encoding of the proofs
+const serializationVersion byte = 1
+
+var ErrSerializationVersion = errors.New("unknown serialization version")
+
+// WriteTo writes the binary encoding of the proof of proximity to w.
+// Slices are prefixed by their length on 4 bytes, integers and field elements
+// are encoded in big endian.
+// It returns the number of bytes written.
+func (proof *ProofOfProximity) WriteTo(w io.Writer) (int64, error) {
+	enc := encoder{w: w}
+	enc.write([]byte{serializationVersion})
+	enc.writeBytes(proof.ID)
+	enc.writeUint32(uint32(len(proof.Rounds)))
+	for i := range proof.Rounds {
+		enc.writeUint32(uint32(len(proof.Rounds[i].Interactions)))
+		for j := range proof.Rounds[i].Interactions {
+			for k := range proof.Rounds[i].Interactions[j] {
+				mp := &proof.Rounds[i].Interactions[j][k]
+				enc.writeBytes(mp.MerkleRoot)
+				enc.writeProofSet(mp.ProofSet)
+				enc.writeUint64(mp.numLeaves)
+			}
+		}
+		enc.writeElement(&proof.Rounds[i].Evaluation)
+	}
+	return enc.n, enc.err
+}
+
+// ReadFrom decodes a proof of proximity encoded by WriteTo from r.
+// It returns the number of bytes read.
+func (proof *ProofOfProximity) ReadFrom(r io.Reader) (int64, error) {
+	dec := decoder{r: r}
+	dec.readVersion()
+	proof.ID = dec.readBytes()
+	nbRounds := dec.readUint32()
+	proof.Rounds = nil
+	for i := uint32(0); i < nbRounds && dec.err == nil; i++ {
+		var round Round
+		nbInteractions := dec.readUint32()
+		for j := uint32(0); j < nbInteractions && dec.err == nil; j++ {
+			var interaction [2]MerkleProof
+			for k := range interaction {
+				interaction[k].MerkleRoot = dec.readBytes()
+				interaction[k].ProofSet = dec.readProofSet()
+				interaction[k].numLeaves = dec.readUint64()
+			}
+			round.Interactions = append(round.Interactions, interaction)
+		}
+		round.Evaluation = dec.readElement()
+		proof.Rounds = append(proof.Rounds, round)
+	}
+	return dec.n, dec.err
+}
+
+// WriteTo writes the binary encoding of the opening proof to w.
+// It returns the number of bytes written.
+func (proof *OpeningProof) WriteTo(w io.Writer) (int64, error) {
+	enc := encoder{w: w}
+	enc.write([]byte{serializationVersion})
+	enc.writeBytes(proof.merkleRoot)
+	enc.writeProofSet(proof.ProofSet)
+	enc.writeUint64(proof.numLeaves)
+	enc.writeUint64(proof.index)
+	enc.writeElement(&proof.ClaimedValue)
+	return enc.n, enc.err
+}
+
+// ReadFrom decodes an opening proof encoded by WriteTo from r.
+// It returns the number of bytes read.
+func (proof *OpeningProof) ReadFrom(r io.Reader) (int64, error) {
+	dec := decoder{r: r}
+	dec.readVersion()
+	proof.merkleRoot = dec.readBytes()
+	proof.ProofSet = dec.readProofSet()
+	proof.numLeaves = dec.readUint64()
+	proof.index = dec.readUint64()
+	proof.ClaimedValue = dec.readElement()
+	return dec.n, dec.err
+}
+
+// WriteTo writes the binary encoding of the proof to w.
+// It returns the number of bytes written.
+func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
+	enc := encoder{w: w}
+	enc.write([]byte{serializationVersion})
+	enc.writeProofSet(proof.Commitments)
+	enc.writeUint32(uint32(len(proof.Openings)))
+	for i := range proof.Openings {
+		enc.writeMultiProof(&proof.Openings[i])
+	}
+	enc.writeVector(proof.FinalPoly)
+	enc.writeUint64(proof.Nonce)
+	return enc.n, enc.err
+}
+
+// ReadFrom decodes a proof encoded by WriteTo from r.
+// It returns the number of bytes read.
+func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
+	dec := decoder{r: r}
+	dec.readVersion()
+	proof.Commitments = dec.readProofSet()
+	nbOpenings := dec.readUint32()
+	proof.Openings = nil
+	for i := uint32(0); i < nbOpenings && dec.err == nil; i++ {
+		proof.Openings = append(proof.Openings, dec.readMultiProof())
+	}
+	proof.FinalPoly = dec.readVector()
+	proof.Nonce = dec.readUint64()
+	return dec.n, dec.err
+}
+
+// WriteTo writes the binary encoding of the batch proof to w.
+// It returns the number of bytes written.
+func (proof *BatchProof) WriteTo(w io.Writer) (int64, error) {
+	enc := encoder{w: w}
+	enc.write([]byte{serializationVersion})
+	enc.writeUint32(uint32(len(proof.Evaluations)))
+	for l := range proof.Evaluations {
+		enc.writeVector(proof.Evaluations[l])
+	}
+	enc.writeMultiProof(&proof.Rows)
+	if enc.err != nil {
+		return enc.n, enc.err
+	}
+	m, err := proof.Proof.WriteTo(w)
+	return enc.n + m, err
+}
+
+// ReadFrom decodes a batch proof encoded by WriteTo from r.
+// It returns the number of bytes read.
+func (proof *BatchProof) ReadFrom(r io.Reader) (int64, error) {
+	dec := decoder{r: r}
+	dec.readVersion()
+	nbPoints := dec.readUint32()
+	proof.Evaluations = nil
+	for l := uint32(0); l < nbPoints && dec.err == nil; l++ {
+		proof.Evaluations = append(proof.Evaluations, dec.readVector())
+	}
+	proof.Rows = dec.readMultiProof()
+	if dec.err != nil {
+		return dec.n, dec.err
+	}
+	m, err := proof.Proof.ReadFrom(r)
+	return dec.n + m, err
+}
+
+// encoder writes to w and keeps track of the number of bytes written. After an
+// error, the writes are no-ops.
+type encoder struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (enc *encoder) write(b []byte) {
+	if enc.err != nil {
+		return
+	}
+	m, err := enc.w.Write(b)
+	enc.n += int64(m)
+	enc.err = err
+}
+
+func (enc *encoder) writeUint32(v uint32) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	enc.write(buf[:])
+}
+
+func (enc *encoder) writeUint64(v uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	enc.write(buf[:])
+}
+
+func (enc *encoder) writeBytes(b []byte) {
+	enc.writeUint32(uint32(len(b)))
+	enc.write(b)
+}
+
+func (enc *encoder) writeProofSet(s [][]byte) {
+	enc.writeUint32(uint32(len(s)))
+	for i := range s {
+		enc.writeBytes(s[i])
+	}
+}
+
+func (enc *encoder) writeElement(e *fr.Element) {
+	b := e.Bytes()
+	enc.write(b[:])
+}
+
+func (enc *encoder) writeVector(v []fr.Element) {
+	enc.writeUint32(uint32(len(v)))
+	for i := range v {
+		enc.writeElement(&v[i])
+	}
+}
+
+func (enc *encoder) writeMultiProof(proof *merkletree.MultiProof) {
+	if enc.err != nil {
+		return
+	}
+	m, err := proof.WriteTo(enc.w)
+	enc.n += m
+	enc.err = err
+}
+
+// decoder reads from r and keeps track of the number of bytes read. After an
+// error, the reads are no-ops returning zero values. The lengths are not
+// trusted: the slices grow as the data is read.
+type decoder struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func (dec *decoder) read(b []byte) {
+	if dec.err != nil {
+		return
+	}
+	m, err := io.ReadFull(dec.r, b)
+	dec.n += int64(m)
+	dec.err = err
+}
+
+func (dec *decoder) readVersion() {
+	var buf [1]byte
+	dec.read(buf[:])
+	if dec.err == nil && buf[0] != serializationVersion {
+		dec.err = ErrSerializationVersion
+	}
+}
+
+func (dec *decoder) readUint32() uint32 {
+	var buf [4]byte
+	dec.read(buf[:])
+	return binary.BigEndian.Uint32(buf[:])
+}
+
+func (dec *decoder) readUint64() uint64 {
+	var buf [8]byte
+	dec.read(buf[:])
+	return binary.BigEndian.Uint64(buf[:])
+}
+
+func (dec *decoder) readBytes() []byte {
+	length := dec.readUint32()
+	if dec.err != nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	m, err := io.CopyN(&buf, dec.r, int64(length))
+	dec.n += m
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	dec.err = err
+	return buf.Bytes()
+}
+
+func (dec *decoder) readProofSet() [][]byte {
+	count := dec.readUint32()
+	var res [][]byte
+	for i := uint32(0); i < count && dec.err == nil; i++ {
+		res = append(res, dec.readBytes())
+	}
+	return res
+}
+
+func (dec *decoder) readElement() fr.Element {
+	var buf [fr.Bytes]byte
+	dec.read(buf[:])
+	if dec.err != nil {
+		return fr.Element{}
+	}
+	e, err := fr.BigEndian.Element(&buf)
+	dec.err = err
+	return e
+}
+
+func (dec *decoder) readVector() []fr.Element {
+	length := dec.readUint32()
+	var res []fr.Element
+	for i := uint32(0); i < length && dec.err == nil; i++ {
+		res = append(res, dec.readElement())
+	}
+	return res
+}
+
+func (dec *decoder) readMultiProof() merkletree.MultiProof {
+	var res merkletree.MultiProof
+	if dec.err != nil {
+		return res
+	}
+	m, err := res.ReadFrom(dec.r)
+	dec.n += m
+	dec.err = err
+	return res
+}
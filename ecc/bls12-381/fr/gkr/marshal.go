@@ -0,0 +1,80 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package gkr
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/sumcheck"
+)
+
+// serializationVersion is the first byte of the binary encoding of a proof
+const serializationVersion byte = 1
+
+var ErrSerializationVersion = errors.New("unknown serialization version")
+
+// WriteTo writes the binary encoding of the proof to w: the version byte, the
+// number of sumcheck proofs on 4 bytes, then the sumcheck proofs.
+// It returns the number of bytes written.
+func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
+	var buf [5]byte
+	buf[0] = serializationVersion
+	binary.BigEndian.PutUint32(buf[1:], uint32(len(*proof)))
+	m, err := w.Write(buf[:])
+	n := int64(m)
+	if err != nil {
+		return n, err
+	}
+	for i := range *proof {
+		m, err := (*proof)[i].WriteTo(w)
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// ReadFrom decodes a proof encoded by WriteTo from r.
+// It returns the number of bytes read.
+func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
+	var buf [5]byte
+	m, err := io.ReadFull(r, buf[:])
+	n := int64(m)
+	if err != nil {
+		return n, err
+	}
+	if buf[0] != serializationVersion {
+		return n, ErrSerializationVersion
+	}
+
+	// the length is not trusted: the proof grows as the data is read
+	nbProofs := binary.BigEndian.Uint32(buf[1:])
+	*proof = nil
+	for i := uint32(0); i < nbProofs; i++ {
+		var p sumcheck.Proof
+		m, err := p.ReadFrom(r)
+		n += m
+		if err != nil {
+			return n, err
+		}
+		*proof = append(*proof, p)
+	}
+	return n, nil
+}
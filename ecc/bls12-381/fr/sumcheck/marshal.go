@@ -0,0 +1,175 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package sumcheck
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/polynomial"
+)
+
+// serializationVersion is the first byte of the binary encoding of a proof
+const serializationVersion byte = 1
+
+// tags of the supported types of FinalEvalProof
+const (
+	finalEvalProofNil byte = iota
+	finalEvalProofElements
+)
+
+var ErrSerializationVersion = errors.New("unknown serialization version")
+
+// WriteTo writes the binary encoding of the proof to w: the version byte, the
+// partial sum polynomials, then a tag for the type of FinalEvalProof followed by
+// its value. Slices are prefixed by their length on 4 bytes, field elements are
+// encoded in big endian. FinalEvalProof must be nil or a []fr.Element.
+// It returns the number of bytes written.
+func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	write := func(b []byte) error {
+		m, err := w.Write(b)
+		n += int64(m)
+		return err
+	}
+	writeVector := func(v []fr.Element) error {
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], uint32(len(v)))
+		if err := write(buf[:]); err != nil {
+			return err
+		}
+		for i := range v {
+			b := v[i].Bytes()
+			if err := write(b[:]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var tag byte
+	switch proof.FinalEvalProof.(type) {
+	case nil:
+		tag = finalEvalProofNil
+	case []fr.Element:
+		tag = finalEvalProofElements
+	default:
+		return 0, fmt.Errorf("unsupported final evaluation proof type %T", proof.FinalEvalProof)
+	}
+
+	if err := write([]byte{serializationVersion}); err != nil {
+		return n, err
+	}
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(len(proof.PartialSumPolys)))
+	if err := write(buf[:]); err != nil {
+		return n, err
+	}
+	for _, p := range proof.PartialSumPolys {
+		if err := writeVector(p); err != nil {
+			return n, err
+		}
+	}
+	if err := write([]byte{tag}); err != nil {
+		return n, err
+	}
+	if tag == finalEvalProofElements {
+		if err := writeVector(proof.FinalEvalProof.([]fr.Element)); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// ReadFrom decodes a proof encoded by WriteTo from r.
+// It returns the number of bytes read.
+func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	read := func(b []byte) error {
+		m, err := io.ReadFull(r, b)
+		n += int64(m)
+		return err
+	}
+	readUint32 := func() (uint32, error) {
+		var buf [4]byte
+		err := read(buf[:])
+		return binary.BigEndian.Uint32(buf[:]), err
+	}
+	// the lengths are not trusted: the slices grow as the data is read
+	readVector := func() ([]fr.Element, error) {
+		length, err := readUint32()
+		if err != nil {
+			return nil, err
+		}
+		res := make([]fr.Element, 0)
+		var buf [fr.Bytes]byte
+		for i := uint32(0); i < length; i++ {
+			if err := read(buf[:]); err != nil {
+				return nil, err
+			}
+			e, err := fr.BigEndian.Element(&buf)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, e)
+		}
+		return res, nil
+	}
+
+	var version [1]byte
+	if err := read(version[:]); err != nil {
+		return n, err
+	}
+	if version[0] != serializationVersion {
+		return n, ErrSerializationVersion
+	}
+	nbPolys, err := readUint32()
+	if err != nil {
+		return n, err
+	}
+	proof.PartialSumPolys = nil
+	for i := uint32(0); i < nbPolys; i++ {
+		p, err := readVector()
+		if err != nil {
+			return n, err
+		}
+		proof.PartialSumPolys = append(proof.PartialSumPolys, polynomial.Polynomial(p))
+	}
+
+	var tag [1]byte
+	if err := read(tag[:]); err != nil {
+		return n, err
+	}
+	switch tag[0] {
+	case finalEvalProofNil:
+		proof.FinalEvalProof = nil
+	case finalEvalProofElements:
+		v, err := readVector()
+		if err != nil {
+			return n, err
+		}
+		proof.FinalEvalProof = v
+	default:
+		return n, fmt.Errorf("unknown final evaluation proof tag %d", tag[0])
+	}
+
+	return n, nil
+}
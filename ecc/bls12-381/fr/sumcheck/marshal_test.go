@@ -0,0 +1,98 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package sumcheck
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/polynomial"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+func TestProofSerialization(t *testing.T) {
+
+	poly := make(polynomial.MultiLin, 16)
+	for i := range poly {
+		poly[i].SetUint64(uint64(i*i + 1))
+	}
+	claim := singleMultilinClaim{g: poly.Clone()}
+	proof, err := Prove(&claim, fiatshamir.WithHash(sha256.New()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roundTrip := func(proof Proof) Proof {
+		var buf bytes.Buffer
+		written, err := proof.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if written != int64(buf.Len()) {
+			t.Fatal("wrong number of bytes written")
+		}
+		var decoded Proof
+		read, err := decoded.ReadFrom(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read != written {
+			t.Fatal("wrong number of bytes read")
+		}
+		return decoded
+	}
+
+	decoded := roundTrip(proof)
+	if !reflect.DeepEqual(proof, decoded) {
+		t.Fatal("decoded proof does not match")
+	}
+	lazyClaim := singleMultilinLazyClaim{g: poly, claimedSum: poly.Sum()}
+	if err := Verify(lazyClaim, decoded, fiatshamir.WithHash(sha256.New())); err != nil {
+		t.Fatal(err)
+	}
+
+	proof.FinalEvalProof = []fr.Element{poly[3], poly[5]}
+	if decoded = roundTrip(proof); !reflect.DeepEqual(proof, decoded) {
+		t.Fatal("decoded proof does not match")
+	}
+
+	// unsupported final evaluation proof and unknown version
+	proof.FinalEvalProof = 42
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err == nil {
+		t.Fatal("unsupported final evaluation proof encoded")
+	}
+	buf.Reset()
+	proof.FinalEvalProof = nil
+	if _, err := proof.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	b[0]++
+	if _, err := decoded.ReadFrom(bytes.NewReader(b)); err != ErrSerializationVersion {
+		t.Fatal("expected ErrSerializationVersion")
+	}
+
+	// truncated encoding
+	b[0]--
+	if _, err := decoded.ReadFrom(bytes.NewReader(b[:len(b)-1])); err == nil {
+		t.Fatal("truncated proof decoded")
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package sumcheck
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/polynomial"
+	"github.com/consensys/gnark-crypto/utils"
+)
+
+var (
+	errVirtualFinalEval = errors.New("the final evaluations do not match the virtual polynomial")
+	errVirtualShape     = errors.New("malformed final evaluation proof")
+)
+
+// Term is the product Coeff × ∏ⱼ Polys[Factors[j]] of a virtual polynomial
+type Term struct {
+	Coeff   fr.Element
+	Factors []int // indices of the factors in VirtualPolynomial.Polys, with repetitions for powers
+}
+
+// VirtualPolynomial is a sum of products of multilinear polynomials
+// g = ∑ᵢ cᵢ ∏ⱼ Pᵢⱼ, as used in HyperPlonk or Spartan. The multilinear polynomials
+// are stored once and referenced by the terms, so that a polynomial appearing in
+// several products is folded once per round.
+type VirtualPolynomial struct {
+	Polys []polynomial.MultiLin // hypercube evaluations, all of the same length 2ⁿ
+	Terms []Term
+}
+
+// Degree returns the degree of g in each variable, that is the largest number
+// of factors of a term
+func (v *VirtualPolynomial) Degree() int {
+	return termsDegree(v.Terms)
+}
+
+// Sum returns ∑_{0≤i<2ⁿ} g(i)
+func (v *VirtualPolynomial) Sum() fr.Element {
+	var res, prod fr.Element
+	for i := range v.Polys[0] {
+		for _, term := range v.Terms {
+			prod.Set(&term.Coeff)
+			for _, k := range term.Factors {
+				prod.Mul(&prod, &v.Polys[k][i])
+			}
+			res.Add(&res, &prod)
+		}
+	}
+	return res
+}
+
+// evaluateTerms returns g(r) given the evaluations of the multilinear polynomials at r
+func evaluateTerms(terms []Term, evaluations []fr.Element) fr.Element {
+	var res, prod fr.Element
+	for _, term := range terms {
+		prod.Set(&term.Coeff)
+		for _, k := range term.Factors {
+			prod.Mul(&prod, &evaluations[k])
+		}
+		res.Add(&res, &prod)
+	}
+	return res
+}
+
+func termsDegree(terms []Term) int {
+	res := 1
+	for _, term := range terms {
+		if len(term.Factors) > res {
+			res = len(term.Factors)
+		}
+	}
+	return res
+}
+
+type virtualOptions struct {
+	pool    *polynomial.Pool
+	workers *utils.WorkerPool
+}
+
+// VirtualOption customizes the prover of a virtual polynomial
+type VirtualOption func(*virtualOptions)
+
+// WithPool makes the prover allocate its copies of the multilinear polynomials
+// from pool, which must handle slices of length 2ⁿ
+func WithPool(pool *polynomial.Pool) VirtualOption {
+	return func(o *virtualOptions) {
+		o.pool = pool
+	}
+}
+
+// WithWorkers makes the prover compute the rounds in parallel with workers.
+// Without it, the rounds are computed sequentially.
+func WithWorkers(workers *utils.WorkerPool) VirtualOption {
+	return func(o *virtualOptions) {
+		o.workers = workers
+	}
+}
+
+// VirtualClaims is the Claims of the statement ∑_{0≤i<2ⁿ} g(i) = c for a virtual
+// polynomial g. The final evaluation proof is the list of the evaluations of
+// the multilinear polynomials at the challenges, which the caller is expected
+// to check against commitments, for instance with a polynomial commitment
+// scheme.
+type VirtualClaims struct {
+	polys   []polynomial.MultiLin
+	terms   []Term
+	degree  int
+	varsNum int
+	virtualOptions
+}
+
+// NewVirtualClaims returns the claims of the sum of v over the hypercube. The
+// multilinear polynomials of v are copied, v is not modified.
+func NewVirtualClaims(v VirtualPolynomial, opts ...VirtualOption) *VirtualClaims {
+	c := &VirtualClaims{
+		terms:  v.Terms,
+		degree: v.Degree(),
+		polys:  make([]polynomial.MultiLin, len(v.Polys)),
+	}
+	for _, opt := range opts {
+		opt(&c.virtualOptions)
+	}
+	for k := range v.Polys {
+		if c.pool == nil {
+			c.polys[k] = v.Polys[k].Clone()
+		} else {
+			c.polys[k] = c.pool.Clone(v.Polys[k])
+		}
+	}
+	c.varsNum = v.Polys[0].NumVars()
+	return c
+}
+
+func (c *VirtualClaims) VarsNum() int {
+	return c.varsNum
+}
+
+func (c *VirtualClaims) ClaimsNum() int {
+	return 1
+}
+
+// Combine returns g₁, there being a single claim
+func (c *VirtualClaims) Combine(fr.Element) polynomial.Polynomial {
+	return c.computeGJ()
+}
+
+// Next folds the multilinear polynomials at r and returns the next gⱼ
+func (c *VirtualClaims) Next(r fr.Element) polynomial.Polynomial {
+	const minBlockSize = 512
+	n := len(c.polys[0]) / 2
+	if c.workers == nil || n < minBlockSize {
+		for k := range c.polys {
+			c.polys[k].Fold(r)
+		}
+	} else {
+		wgs := make([]*sync.WaitGroup, len(c.polys))
+		for k := range c.polys {
+			wgs[k] = c.workers.Submit(n, c.polys[k].FoldParallel(r), minBlockSize)
+		}
+		for _, wg := range wgs {
+			wg.Wait()
+		}
+	}
+	return c.computeGJ()
+}
+
+// computeGJ returns gⱼ(1), ..., gⱼ(d) where gⱼ = ∑_{0≤i<2ⁿ⁻ʲ} g(r₁, ..., rⱼ₋₁, Xⱼ, i...)
+// and d is the degree of g.
+func (c *VirtualClaims) computeGJ() polynomial.Polynomial {
+
+	// each multilinear polynomial P is linear in Xⱼ, so that
+	// P(.., m, i...) = P(.., m-1, i...) + P(.., 1, i...) - P(.., 0, i...)
+	nbOuter := len(c.polys[0]) / 2
+	nbPolys := len(c.polys)
+
+	gJ := make([]fr.Element, c.degree)
+	var mu sync.Mutex
+	computeAll := func(start, end int) {
+		var step, prod fr.Element
+
+		res := make([]fr.Element, c.degree)
+
+		// evaluations[(d-1)*nbPolys+k] = Pₖ(.., d, i...)
+		evaluations := make([]fr.Element, c.degree*nbPolys)
+
+		for i := start; i < end; i++ {
+			for k, p := range c.polys {
+				evaluations[k].Set(&p[nbOuter+i])
+				step.Sub(&evaluations[k], &p[i])
+				for d := 1; d < c.degree; d++ {
+					evaluations[d*nbPolys+k].Add(&evaluations[(d-1)*nbPolys+k], &step)
+				}
+			}
+
+			for d := 0; d < c.degree; d++ {
+				e := evaluations[d*nbPolys : (d+1)*nbPolys]
+				for _, term := range c.terms {
+					prod.Set(&term.Coeff)
+					for _, k := range term.Factors {
+						prod.Mul(&prod, &e[k])
+					}
+					res[d].Add(&res[d], &prod)
+				}
+			}
+		}
+		mu.Lock()
+		for d := range gJ {
+			gJ[d].Add(&gJ[d], &res[d])
+		}
+		mu.Unlock()
+	}
+
+	const minBlockSize = 64
+
+	if c.workers == nil || nbOuter < minBlockSize {
+		computeAll(0, nbOuter)
+	} else {
+		c.workers.Submit(nbOuter, computeAll, minBlockSize).Wait()
+	}
+
+	return gJ
+}
+
+// ProveFinalEval returns the evaluations of the multilinear polynomials at r.
+// The copies of the polynomials are released.
+func (c *VirtualClaims) ProveFinalEval(r []fr.Element) interface{} {
+	evaluations := make([]fr.Element, len(c.polys))
+	for k := range c.polys {
+		c.polys[k].Fold(r[len(r)-1])
+		evaluations[k].Set(&c.polys[k][0])
+		if c.pool != nil {
+			c.pool.Dump(c.polys[k])
+		}
+	}
+	c.polys = nil
+	return evaluations
+}
+
+// VirtualLazyClaims is the LazyClaims of the statement ∑_{0≤i<2ⁿ} g(i) = c for a
+// virtual polynomial g, on the verifier side. Once the sumcheck is verified,
+// the evaluations of the multilinear polynomials at the challenges, given in
+// the final evaluation proof, must still be checked by the caller.
+type VirtualLazyClaims struct {
+	Terms      []Term
+	NbPolys    int // number of multilinear polynomials
+	NbVars     int // number of variables n
+	ClaimedSum fr.Element
+}
+
+func (c *VirtualLazyClaims) ClaimsNum() int {
+	return 1
+}
+
+func (c *VirtualLazyClaims) VarsNum() int {
+	return c.NbVars
+}
+
+func (c *VirtualLazyClaims) CombinedSum(fr.Element) fr.Element {
+	return c.ClaimedSum
+}
+
+func (c *VirtualLazyClaims) Degree(int) int {
+	return termsDegree(c.Terms)
+}
+
+// VerifyFinalEval checks that the purported value g(r) is consistent with the
+// evaluations of the multilinear polynomials given in proof
+func (c *VirtualLazyClaims) VerifyFinalEval(r []fr.Element, combinationCoeff fr.Element, purportedValue fr.Element, proof interface{}) error {
+	evaluations, ok := proof.([]fr.Element)
+	if !ok || len(evaluations) != c.NbPolys {
+		return errVirtualShape
+	}
+	for _, term := range c.Terms {
+		for _, k := range term.Factors {
+			if k < 0 || k >= c.NbPolys {
+				return errVirtualShape
+			}
+		}
+	}
+	g := evaluateTerms(c.Terms, evaluations)
+	if !g.Equal(&purportedValue) {
+		return errVirtualFinalEval
+	}
+	return nil
+}
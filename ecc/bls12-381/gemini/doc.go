@@ -0,0 +1,38 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package gemini opens multilinear polynomials with the univariate KZG
+// commitment scheme of the kzg package, using the Gemini reduction [BCHO22] as
+// instantiated in HyperKZG.
+//
+// A multilinear polynomial f in n variables, given by its evaluations on the
+// hypercube {0,1}ⁿ (see polynomial.MultiLin), is committed with kzg.Commit as
+// the univariate polynomial f₀ whose coefficients are these evaluations, so that
+// an existing kzg.ProvingKey, for instance from a Powers of Tau ceremony, can be
+// used as is.
+//
+// polynomial.MultiLin reads the last variable Xₙ as the least significant bit of
+// the index, so that evaluating f at z = (z₁, ..., zₙ) amounts to the foldings
+//
+//	fᵢ₊₁(X) = (1-zₙ₋ᵢ) fᵢ,even(X) + zₙ₋ᵢ fᵢ,odd(X)
+//
+// where fᵢ(X) = fᵢ,even(X²) + X fᵢ,odd(X²), and fₙ = f(z). The prover commits to
+// f₁, ..., fₙ₋₁ and, for a random r, opens f₀ at ±r and the fᵢ at ±r and r², from
+// which the verifier checks the foldings. The openings are batched in a single
+// shplonk opening proof.
+//
+// [BCHO22]: https://eprint.iacr.org/2022/420
+package gemini
@@ -0,0 +1,330 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+
+	"github.com/consensys/gnark-crypto/utils/testutils"
+)
+
+// contributedSRS returns a SRS of the given size updated by nbContributions
+// participants, and the proofs of the updates
+func contributedSRS(t *testing.T, size uint64, nbContributions int) (*SRS, []UpdateProof) {
+	srs, err := NewSRS(size, big.NewInt(1))
+	require.NoError(t, err)
+	proofs := make([]UpdateProof, nbContributions)
+	for i := range proofs {
+		proofs[i], err = Contribute(srs)
+		require.NoError(t, err)
+	}
+	return srs, proofs
+}
+
+func TestContributions(t *testing.T) {
+	assert := require.New(t)
+
+	srs, proofs := contributedSRS(t, 32, 3)
+	_, _, g1, _ := bls12381.Generators()
+
+	// verify correct contributions
+	assert.NoError(VerifyContributions(srs, g1, proofs))
+	assert.NoError(VerifyContributions(srs, proofs[0].RunningProduct, proofs[1:]))
+	t.Run("serialization", testutils.SerializationRoundTrip(&proofs[0]))
+
+	// the updated SRS can be used to commit and open
+	f := randomPolynomial(20)
+	digest, err := Commit(f, srs.Pk)
+	assert.NoError(err)
+	var point fr.Element
+	point.SetRandom()
+	proof, err := Open(f, point, srs.Pk)
+	assert.NoError(err)
+	assert.NoError(Verify(&digest, &proof, point, srs.Vk))
+
+	// missing or reordered contributions
+	assert.ErrorIs(VerifyContributions(srs, g1, proofs[1:]), ErrInvalidUpdateProof)
+	assert.ErrorIs(VerifyContributions(srs, g1, proofs[:2]), ErrInvalidContribution)
+	assert.ErrorIs(VerifyContributions(srs, g1, nil), ErrInvalidContribution)
+	assert.ErrorIs(VerifyContributions(srs, g1, []UpdateProof{proofs[1], proofs[0], proofs[2]}), ErrInvalidUpdateProof)
+
+	// wrong proof of knowledge
+	tampered := make([]UpdateProof, len(proofs))
+	copy(tampered, proofs)
+	tampered[1].S.Double(&tampered[1].S)
+	assert.ErrorIs(VerifyContributions(srs, g1, tampered), ErrInvalidUpdateProof)
+
+	// public keys inconsistent with the running products
+	runningProducts := []bls12381.G1Affine{g1, proofs[0].RunningProduct, proofs[1].RunningProduct}
+	assert.NoError(verifyRunningProducts(runningProducts, []bls12381.G2Affine{proofs[0].PotPubkey, proofs[1].PotPubkey}))
+	assert.ErrorIs(verifyRunningProducts(runningProducts, []bls12381.G2Affine{proofs[1].PotPubkey, proofs[0].PotPubkey}), ErrInvalidContribution)
+
+	// SRS not made of powers of the same secret
+	srs.Pk.G1[5] = srs.Pk.G1[6]
+	assert.ErrorIs(VerifyContributions(srs, g1, proofs), ErrInvalidSRS)
+}
+
+func TestVerifySRS(t *testing.T) {
+	assert := require.New(t)
+
+	assert.NoError(VerifySRS(testSrs))
+
+	srs, err := NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+
+	// wrong power of τ in G₂
+	srs.Vk.G2[1].Double(&srs.Vk.G2[1])
+	srs.Vk.Lines[1] = bls12381.PrecomputeLines(srs.Vk.G2[1])
+	assert.ErrorIs(VerifySRS(srs), ErrInvalidSRS)
+
+	// lines inconsistent with [τ]G₂
+	srs, err = NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+	srs.Vk.Lines[1] = srs.Vk.Lines[0]
+	assert.ErrorIs(VerifySRS(srs), ErrInvalidSRS)
+
+	// wrong generator
+	srs, err = NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+	srs.Pk.G1[0] = srs.Pk.G1[1]
+	assert.ErrorIs(VerifySRS(srs), ErrInvalidSRS)
+
+	// wrong last power
+	srs, err = NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+	srs.Pk.G1[15].Double(&srs.Pk.G1[15])
+	assert.ErrorIs(VerifySRS(srs), ErrInvalidSRS)
+}
+
+func TestValidate(t *testing.T) {
+	assert := require.New(t)
+
+	assert.NoError(testSrs.Validate())
+	assert.NoError(testSrs.ValidateParallel(4))
+	assert.NoError(testSrs.ValidateParallel(0))
+
+	// a valid encoding of powers of different secrets is only rejected by Validate
+	srs, err := NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+	other, err := NewSRS(16, big.NewInt(43))
+	assert.NoError(err)
+	srs.Pk.G1[7] = other.Pk.G1[7]
+	var buf bytes.Buffer
+	_, err = srs.WriteTo(&buf)
+	assert.NoError(err)
+	var read SRS
+	_, err = read.ReadFrom(&buf)
+	assert.NoError(err)
+	assert.ErrorIs(read.Validate(), ErrInvalidSRS)
+	assert.ErrorIs(read.ValidateParallel(4), ErrInvalidSRS)
+
+	// points not on the curve
+	srs, err = NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+	srs.Pk.G1[3].Y.Double(&srs.Pk.G1[3].Y)
+	assert.ErrorIs(srs.Validate(), ErrInvalidSRSPoint)
+	srs.Pk.G1[3] = other.Pk.G1[3]
+	srs.Vk.G2[1].Y.Double(&srs.Vk.G2[1].Y)
+	assert.ErrorIs(srs.ValidateParallel(2), ErrInvalidSRSPoint)
+}
+
+// montgomery returns the Montgomery form x·R mod q of x, R being 2^(64·fp.Limbs),
+// from its canonical value, so that the encodings of the ceremony files do not
+// depend on the internal representation of fp.Element
+func montgomery(x *fp.Element) []byte {
+	v := x.BigInt(new(big.Int))
+	v.Lsh(v, 64*fp.Limbs).Mod(v, fp.Modulus())
+	return v.FillBytes(make([]byte, fp.Bytes))
+}
+
+// putMontgomery writes the Montgomery form of x in little endian, as snarkjs
+func putMontgomery(b []byte, x *fp.Element) {
+	m := montgomery(x)
+	for i := range m {
+		b[i] = m[len(m)-1-i]
+	}
+}
+
+// writePtau writes srs in the ptau format, with the SRS of a power n
+// ceremony, i.e. 2ⁿ⁺¹-1 powers in G₁ and 2ⁿ in G₂, the ones not in srs being
+// zero. It writes an extra section before the powers.
+func writePtau(w io.Writer, srs *SRS, power uint32) {
+	section := func(id uint32, content []byte) {
+		binary.Write(w, binary.LittleEndian, id)
+		binary.Write(w, binary.LittleEndian, uint64(len(content)))
+		w.Write(content)
+	}
+
+	w.Write([]byte("ptau"))
+	binary.Write(w, binary.LittleEndian, uint32(1))
+	binary.Write(w, binary.LittleEndian, uint32(4))
+
+	var header bytes.Buffer
+	binary.Write(&header, binary.LittleEndian, uint32(fp.Bytes))
+	q := fp.Modulus().Bytes()
+	for i := len(q) - 1; i >= 0; i-- {
+		header.WriteByte(q[i])
+	}
+	binary.Write(&header, binary.LittleEndian, power)
+	binary.Write(&header, binary.LittleEndian, power)
+	section(ptauSectionHeader, header.Bytes())
+
+	section(7, []byte("contributions"))
+
+	g1 := make([]byte, ((2<<power)-1)*2*fp.Bytes)
+	for i := range srs.Pk.G1 {
+		putMontgomery(g1[2*i*fp.Bytes:], &srs.Pk.G1[i].X)
+		putMontgomery(g1[(2*i+1)*fp.Bytes:], &srs.Pk.G1[i].Y)
+	}
+	section(ptauSectionTauG1, g1)
+
+	g2 := make([]byte, (1<<power)*4*fp.Bytes)
+	for i := range srs.Vk.G2 {
+		coordinates := []*fp.Element{&srs.Vk.G2[i].X.A0, &srs.Vk.G2[i].X.A1, &srs.Vk.G2[i].Y.A0, &srs.Vk.G2[i].Y.A1}
+		for j, c := range coordinates {
+			putMontgomery(g2[(4*i+j)*fp.Bytes:], c)
+		}
+	}
+	section(ptauSectionTauG2, g2)
+}
+
+func TestImportPtau(t *testing.T) {
+	assert := require.New(t)
+
+	srs, _ := contributedSRS(t, 16, 1)
+	var buf bytes.Buffer
+	writePtau(&buf, srs, 4)
+
+	// the first 10 powers
+	imported, err := ImportPtau(bytes.NewReader(buf.Bytes()), 10)
+	assert.NoError(err)
+	assert.Equal(srs.Pk.G1[:10], imported.Pk.G1)
+	assert.Equal(srs.Vk, imported.Vk)
+
+	// all the non zero powers
+	imported, err = ImportPtau(bytes.NewReader(buf.Bytes()), 16)
+	assert.NoError(err)
+	assert.Equal(srs.Pk.G1, imported.Pk.G1)
+
+	// powers at infinity, and more powers than the ceremony
+	_, err = ImportPtau(bytes.NewReader(buf.Bytes()), 17)
+	assert.ErrorIs(err, ErrInvalidSRS)
+	_, err = ImportPtau(bytes.NewReader(buf.Bytes()), 32)
+	assert.ErrorIs(err, ErrPtauTooSmall)
+
+	// wrong magic
+	tampered := bytes.Clone(buf.Bytes())
+	tampered[0] = 'q'
+	_, err = ImportPtau(bytes.NewReader(tampered), 10)
+	assert.ErrorIs(err, ErrInvalidPtau)
+
+	// point not in the subgroup
+	srs.Pk.G1[3].Y.Neg(&srs.Pk.G1[3].Y).Add(&srs.Pk.G1[3].Y, &srs.Pk.G1[3].X)
+	buf.Reset()
+	writePtau(&buf, srs, 4)
+	_, err = ImportPtau(bytes.NewReader(buf.Bytes()), 10)
+	assert.ErrorIs(err, ErrInvalidSRSPoint)
+}
+
+func TestImportEthereumTranscript(t *testing.T) {
+	assert := require.New(t)
+
+	// the first power of τ in G₁ of the transcript
+	_, _, g1, _ := bls12381.Generators()
+	assert.Equal("0x97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb", hexG1(&g1))
+
+	// two sub-ceremonies, of 8 and 16 powers, with three and two contributions
+	var transcript ethereumTranscript
+	srs := make([]*SRS, 2)
+	for k, size := range []uint64{8, 16} {
+		var proofs []UpdateProof
+		srs[k], proofs = contributedSRS(t, size, 3-k)
+
+		_, _, g1, g2 := bls12381.Generators()
+		var tr struct {
+			NumG1Powers uint64 `json:"numG1Powers"`
+			NumG2Powers uint64 `json:"numG2Powers"`
+			PowersOfTau struct {
+				G1Powers []string `json:"G1Powers"`
+				G2Powers []string `json:"G2Powers"`
+			} `json:"powersOfTau"`
+			Witness struct {
+				RunningProducts []string `json:"runningProducts"`
+				PotPubkeys      []string `json:"potPubkeys"`
+			} `json:"witness"`
+		}
+		tr.NumG1Powers, tr.NumG2Powers = size, 2
+		for i := range srs[k].Pk.G1 {
+			tr.PowersOfTau.G1Powers = append(tr.PowersOfTau.G1Powers, hexG1(&srs[k].Pk.G1[i]))
+		}
+		tr.PowersOfTau.G2Powers = []string{hexG2(&srs[k].Vk.G2[0]), hexG2(&srs[k].Vk.G2[1])}
+		tr.Witness.RunningProducts = []string{hexG1(&g1)}
+		tr.Witness.PotPubkeys = []string{hexG2(&g2)}
+		for i := range proofs {
+			tr.Witness.RunningProducts = append(tr.Witness.RunningProducts, hexG1(&proofs[i].RunningProduct))
+			tr.Witness.PotPubkeys = append(tr.Witness.PotPubkeys, hexG2(&proofs[i].PotPubkey))
+		}
+		transcript.Transcripts = append(transcript.Transcripts, tr)
+	}
+	encoded, err := json.Marshal(transcript)
+	assert.NoError(err)
+
+	// the powers are taken from the smallest sub-ceremony large enough
+	for _, size := range []uint64{5, 8, 12} {
+		imported, err := ImportEthereumTranscript(bytes.NewReader(encoded), size)
+		assert.NoError(err)
+		k := 0
+		if size > 8 {
+			k = 1
+		}
+		assert.Equal(srs[k].Pk.G1[:size], imported.Pk.G1)
+		assert.Equal(srs[k].Vk, imported.Vk)
+	}
+	_, err = ImportEthereumTranscript(bytes.NewReader(encoded), 17)
+	assert.ErrorIs(err, ErrEthereumTooSmall)
+
+	// inconsistent witness
+	pubkeys := transcript.Transcripts[1].Witness.PotPubkeys
+	pubkeys[1], pubkeys[2] = pubkeys[2], pubkeys[1]
+	encoded, err = json.Marshal(transcript)
+	assert.NoError(err)
+	_, err = ImportEthereumTranscript(bytes.NewReader(encoded), 12)
+	assert.ErrorIs(err, ErrInvalidContribution)
+}
+
+// hexG1 and hexG2 encode points as in the transcript of the Ethereum ceremony
+func hexG1(p *bls12381.G1Affine) string {
+	b := p.Bytes()
+	return "0x" + hex.EncodeToString(b[:])
+}
+
+func hexG2(p *bls12381.G2Affine) string {
+	b := p.Bytes()
+	return "0x" + hex.EncodeToString(b[:])
+}
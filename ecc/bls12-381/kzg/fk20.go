@@ -0,0 +1,306 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidFK20Sizes         = errors.New("the domain and coset sizes must be powers of 2, with polySize ≤ domainSize and cosetSize ≤ domainSize")
+	ErrInvalidCosetSize         = errors.New("the number of claimed values is not the size of the coset")
+	ErrVerifyCosetOpeningProof  = errors.New("can't verify coset opening proof")
+	ErrInvalidCosetVerifyingKey = errors.New("the powers of α in G₂ do not match the proving key")
+)
+
+// CosetOpeningProof KZG proof for opening at all the points of a coset c·Hₗ,
+// where Hₗ is the subgroup of l-th roots of unity.
+//
+// implements io.ReaderFrom and io.WriterTo
+type CosetOpeningProof struct {
+	// H quotient polynomial (f - I)/(Xˡ - cˡ), where I interpolates f on c·Hₗ
+	H curve.G1Affine
+
+	// ClaimedValues purported values f(cζʲ), ζ being the generator of Hₗ
+	ClaimedValues []fr.Element
+}
+
+// CosetVerifyingKey used to verify coset opening proofs on cosets of size l
+type CosetVerifyingKey struct {
+	G1 []curve.G1Affine  // [G₁, [α]G₁, ..., [αˡ⁻¹]G₁]
+	G2 [2]curve.G2Affine // [G₂, [αˡ]G₂]
+}
+
+// NewCosetVerifyingKey returns the verifying key of the coset opening proofs on
+// cosets of size l, from the proving key and the powers of α in G₂
+// [G₂, [α]G₂, ..., [αˡ]G₂], as published by a Powers of Tau ceremony. For l = 1,
+// these are the G2 of the VerifyingKey. It checks that [αˡ]G₂ matches [αˡ]G₁.
+func NewCosetVerifyingKey(pk ProvingKey, g2Powers []curve.G2Affine, l uint64) (CosetVerifyingKey, error) {
+	if l == 0 || l != ecc.NextPowerOfTwo(l) {
+		return CosetVerifyingKey{}, ErrInvalidCosetSize
+	}
+	if uint64(len(pk.G1)) <= l || uint64(len(g2Powers)) <= l {
+		return CosetVerifyingKey{}, ErrInvalidCosetVerifyingKey
+	}
+
+	// e([αˡ]G₁, G₂) = e(G₁, [αˡ]G₂)
+	var negG1 curve.G1Affine
+	negG1.Neg(&pk.G1[0])
+	check, err := curve.PairingCheck(
+		[]curve.G1Affine{pk.G1[l], negG1},
+		[]curve.G2Affine{g2Powers[0], g2Powers[l]},
+	)
+	if err != nil {
+		return CosetVerifyingKey{}, err
+	}
+	if !check {
+		return CosetVerifyingKey{}, ErrInvalidCosetVerifyingKey
+	}
+
+	return CosetVerifyingKey{
+		G1: pk.G1[:l],
+		G2: [2]curve.G2Affine{g2Powers[0], g2Powers[l]},
+	}, nil
+}
+
+// FK20 computes, following Feist and Khovratovich [FK20], the opening proofs of
+// a polynomial at all the points of a domain of size n, or at all the cosets
+// ωᵏ·Hₗ of the subgroup Hₗ of size l in this domain, in O(n log n) group
+// operations instead of O(n²) for n calls to Open.
+//
+// The commitment to the quotient of f by Xˡ - a is ∑ₖ aᵏhₖ where
+// hₖ = ∑ᵢ fᵢ₊₍ₖ₊₁₎ₗ[αⁱ]G₁. The hₖ are obtained as l Toeplitz matrix-vector
+// products, each embedded in a circulant one computed with FFTs, and the
+// quotients at the N/l cosets, for which a runs over the (n/l)-th roots of
+// unity, with a last FFT in G₁.
+//
+// FK20 holds the FFTs of the SRS, so that they are computed once for all the
+// polynomials.
+//
+// [FK20]: https://eprint.iacr.org/2023/033
+type FK20 struct {
+	polySize   uint64
+	domainSize uint64
+	cosetSize  uint64
+
+	// m is the size of the Toeplitz matrices
+	m uint64
+
+	// srsFFT[r] FFT of size 2m of the r-th circulant embedding, in bit reversed order
+	srsFFT [][]curve.G1Affine
+
+	circulantDomain *fft.Domain
+	twiddlesInv     []*big.Int // twiddles of the inverse FFT of size 2m
+	twiddles        []*big.Int // twiddles of the FFT of size 2m
+	twiddlesCosets  []*big.Int // twiddles of the FFT of size domainSize/cosetSize
+}
+
+// NewFK20 returns a FK20 computing the opening proofs of polynomials of size
+// at most polySize, at the cosets of size cosetSize of the domain of size
+// domainSize. With cosetSize = 1, these are the single point opening proofs at
+// every point of the domain.
+func NewFK20(pk ProvingKey, polySize, domainSize, cosetSize uint64) (*FK20, error) {
+
+	if polySize == 0 || polySize > uint64(len(pk.G1)) {
+		return nil, ErrInvalidPolynomialSize
+	}
+	if domainSize != ecc.NextPowerOfTwo(domainSize) || cosetSize != ecc.NextPowerOfTwo(cosetSize) ||
+		polySize > domainSize || cosetSize > domainSize {
+		return nil, ErrInvalidFK20Sizes
+	}
+
+	fk := &FK20{
+		polySize:   polySize,
+		domainSize: domainSize,
+		cosetSize:  cosetSize,
+		m:          ecc.NextPowerOfTwo((polySize + cosetSize - 1) / cosetSize),
+	}
+	l, m := cosetSize, fk.m
+
+	var err error
+	fk.circulantDomain = fft.NewDomain(2 * m)
+	if fk.twiddlesInv, err = computeTwiddlesInv(int(2 * m)); err != nil {
+		return nil, err
+	}
+	if fk.twiddles, err = computeTwiddles(int(2 * m)); err != nil {
+		return nil, err
+	}
+	if fk.twiddlesCosets, err = computeTwiddles(int(domainSize / cosetSize)); err != nil {
+		return nil, err
+	}
+
+	// the r-th Toeplitz matrix is given by sₜ = [αʳ⁺ᵗˡ]G₁, and embedded in the
+	// circulant matrix of first column (0, .., 0, sₘ₋₁, .., s₀). Only the sₜ with
+	// r+tl < polySize-l can multiply a non zero coefficient.
+	fk.srsFFT = make([][]curve.G1Affine, l)
+	for r := uint64(0); r < l; r++ {
+		s := make([]curve.G1Jac, 2*m)
+		for t := uint64(0); t < m; t++ {
+			if i := r + t*l; i+l < polySize {
+				s[m-1-t].FromAffine(&pk.G1[i])
+			}
+		}
+		// same order as the coefficients transformed with FFTInverse and DIF
+		fftG1(s, fk.twiddlesInv)
+		fk.srsFFT[r] = curve.BatchJacobianToAffineG1(s)
+	}
+
+	return fk, nil
+}
+
+// ComputeQuotients returns the commitments to the quotients of p by Xˡ - ωᵏˡ
+// for 0 ≤ k < n/l, where n is the size of the domain, ω its generator and l the
+// size of the cosets. The k-th one proves the evaluations of p on ωᵏ·Hₗ.
+func (fk *FK20) ComputeQuotients(p []fr.Element) ([]curve.G1Affine, error) {
+
+	if len(p) == 0 || uint64(len(p)) > fk.polySize {
+		return nil, ErrInvalidPolynomialSize
+	}
+	l, m := fk.cosetSize, fk.m
+
+	// Fourier transform of the circulant matrix-vector products, summed over the
+	// l Toeplitz matrices. The r-th vector is (fᵣ, fᵣ₊ₗ, ..., fᵣ₊₍ₘ₋₁₎ₗ, 0, ..., 0),
+	// and FFTInverse includes the normalization of the transform back.
+	coeffs := make([][]fr.Element, l)
+	for r := uint64(0); r < l; r++ {
+		coeffs[r] = make([]fr.Element, 2*m)
+		for u := uint64(0); u < m; u++ {
+			if i := r + u*l; i < uint64(len(p)) {
+				coeffs[r][u] = p[i]
+			}
+		}
+		fk.circulantDomain.FFTInverse(coeffs[r], fft.DIF)
+	}
+
+	h := make([]curve.G1Jac, 2*m)
+	parallel.Execute(int(2*m), func(start, end int) {
+		var tmp curve.G1Jac
+		var b big.Int
+		for i := start; i < end; i++ {
+			for r := uint64(0); r < l; r++ {
+				coeffs[r][i].BigInt(&b)
+				tmp.FromAffine(&fk.srsFFT[r][i])
+				tmp.ScalarMultiplication(&tmp, &b)
+				h[i].AddAssign(&tmp)
+			}
+		}
+	})
+
+	// back to the circulant matrix-vector product, hₖ is its (m+k)-th entry
+	bitReverse(h)
+	fftG1(h, fk.twiddles)
+	bitReverse(h)
+
+	// evaluate ∑ₖ hₖXᵏ at the (n/l)-th roots of unity
+	quotients := make([]curve.G1Jac, fk.domainSize/fk.cosetSize)
+	copy(quotients, h[m:])
+	fftG1(quotients, fk.twiddlesCosets)
+	bitReverse(quotients)
+
+	return curve.BatchJacobianToAffineG1(quotients), nil
+}
+
+// OpenAll returns the opening proofs of p at the n/l cosets ωᵏ·Hₗ, the k-th
+// proof being at ωᵏ·Hₗ.
+func (fk *FK20) OpenAll(p []fr.Element) ([]CosetOpeningProof, error) {
+
+	quotients, err := fk.ComputeQuotients(p)
+	if err != nil {
+		return nil, err
+	}
+
+	// f(ωⁱ), and ωᵏζʲ = ωᵏ⁺ʲⁿᐟˡ
+	evaluations := make([]fr.Element, fk.domainSize)
+	copy(evaluations, p)
+	domain := fft.NewDomain(fk.domainSize)
+	domain.FFT(evaluations, fft.DIF)
+	fft.BitReverse(evaluations)
+
+	nbCosets := fk.domainSize / fk.cosetSize
+	res := make([]CosetOpeningProof, nbCosets)
+	for k := range res {
+		res[k].H = quotients[k]
+		res[k].ClaimedValues = make([]fr.Element, fk.cosetSize)
+		for j := range res[k].ClaimedValues {
+			res[k].ClaimedValues[j] = evaluations[uint64(k)+uint64(j)*nbCosets]
+		}
+	}
+	return res, nil
+}
+
+// VerifyCosetProof verifies a coset opening proof on the coset c·Hₗ, where l
+// is the size of the cosets of vk.
+func VerifyCosetProof(commitment *Digest, proof *CosetOpeningProof, c fr.Element, vk CosetVerifyingKey) error {
+
+	l := uint64(len(vk.G1))
+	if l == 0 || l != ecc.NextPowerOfTwo(l) || uint64(len(proof.ClaimedValues)) != l {
+		return ErrInvalidCosetSize
+	}
+
+	// I(X) = Y(X/c) where Y(ζʲ) = yⱼ
+	interpolation := make([]fr.Element, l)
+	copy(interpolation, proof.ClaimedValues)
+	domain := fft.NewDomain(l)
+	domain.FFTInverse(interpolation, fft.DIF)
+	fft.BitReverse(interpolation)
+	var cInv, acc fr.Element
+	cInv.Inverse(&c)
+	acc.SetOne()
+	for t := range interpolation {
+		interpolation[t].Mul(&interpolation[t], &acc)
+		acc.Mul(&acc, &cInv)
+	}
+
+	// [f(α) - I(α) + cˡH(α)]G₁
+	var cl fr.Element
+	cl.Exp(c, new(big.Int).SetUint64(l))
+	points := make([]curve.G1Affine, 0, l+2)
+	scalars := make([]fr.Element, l+2)
+	points = append(points, *commitment, proof.H)
+	points = append(points, vk.G1...)
+	scalars[0].SetOne()
+	scalars[1] = cl
+	for t := range interpolation {
+		scalars[t+2].Neg(&interpolation[t])
+	}
+	var totalG1 curve.G1Affine
+	if _, err := totalG1.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+
+	// e([f(α) - I(α) + cˡH(α)]G₁, G₂).e([-H(α)]G₁, [αˡ]G₂) == 1
+	var negH curve.G1Affine
+	negH.Neg(&proof.H)
+	check, err := curve.PairingCheck(
+		[]curve.G1Affine{totalG1, negH},
+		vk.G2[:],
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyCosetOpeningProof
+	}
+	return nil
+}
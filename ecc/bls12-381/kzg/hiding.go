@@ -0,0 +1,363 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidBlindingSize = errors.New("the blinding polynomial is not the size of the polynomial")
+)
+
+// HidingProvingKey used to create or open hiding commitments, following the
+// PolyCommit_Ped scheme of Kate, Zaverucha and Goldberg [KZG10]. H = [γ]G₁ is
+// a second generator whose discrete logarithm γ is unknown.
+//
+// [KZG10]: https://www.iacr.org/archive/asiacrypt2010/6477178/6477178.pdf
+type HidingProvingKey struct {
+	G1       []curve.G1Affine // [G₁ [α]G₁ , [α²]G₁, ... ]
+	Blinding []curve.G1Affine // [H [α]H , [α²]H, ... ]
+}
+
+// HidingVerifyingKey used to verify hiding opening proofs
+type HidingVerifyingKey struct {
+	VerifyingKey
+	H curve.G1Affine
+}
+
+// HidingSRS must be computed through MPC and comprises the HidingProvingKey and
+// the HidingVerifyingKey
+//
+// implements io.ReaderFrom and io.WriterTo
+type HidingSRS struct {
+	Pk HidingProvingKey
+	Vk HidingVerifyingKey
+}
+
+// HidingOpeningProof hiding KZG proof for opening at a single point.
+//
+// implements io.ReaderFrom and io.WriterTo
+type HidingOpeningProof struct {
+	// H commitment [ψ(α)]G₁ + [ψ̂(α)]H to the quotients ψ = (f - f(z))/(X - z)
+	// and ψ̂ = (r - r(z))/(X - z), r being the blinding polynomial
+	H curve.G1Affine
+
+	// ClaimedValue purported value f(z)
+	ClaimedValue fr.Element
+
+	// BlindingValue value r(z) of the blinding polynomial
+	BlindingValue fr.Element
+}
+
+// BatchHidingOpeningProof hiding opening proof for many polynomials at the same point
+//
+// implements io.ReaderFrom and io.WriterTo
+type BatchHidingOpeningProof struct {
+	// H commitment to the quotients of ∑ᵢγⁱfᵢ and ∑ᵢγⁱrᵢ
+	H curve.G1Affine
+
+	// ClaimedValues purported values fᵢ(z)
+	ClaimedValues []fr.Element
+
+	// BlindingValues values rᵢ(z) of the blinding polynomials
+	BlindingValues []fr.Element
+}
+
+// NewHidingSRS returns a new hiding SRS using alpha and gamma as randomness
+// source, H being [γ]G₁.
+//
+// In production, a SRS generated through MPC should be used.
+func NewHidingSRS(size uint64, bAlpha, bGamma *big.Int) (*HidingSRS, error) {
+
+	srs, err := NewSRS(size, bAlpha)
+	if err != nil {
+		return nil, err
+	}
+
+	var res HidingSRS
+	res.Pk.G1 = srs.Pk.G1
+	res.Vk.VerifyingKey = srs.Vk
+	res.Pk.Blinding = make([]curve.G1Affine, size)
+	parallel.Execute(int(size), func(start, end int) {
+		for i := start; i < end; i++ {
+			res.Pk.Blinding[i].ScalarMultiplication(&srs.Pk.G1[i], bGamma)
+		}
+	})
+	res.Vk.H = res.Pk.Blinding[0]
+
+	return &res, nil
+}
+
+// CommitHiding commits to a polynomial, in canonical form, with a random
+// blinding polynomial r of the same size: the commitment is [f(α)]G₁ + [r(α)]H.
+// It returns the commitment and r, which is needed to open it.
+func CommitHiding(p []fr.Element, pk HidingProvingKey, nbTasks ...int) (Digest, []fr.Element, error) {
+
+	if len(p) == 0 || len(p) > len(pk.G1) || len(p) > len(pk.Blinding) {
+		return Digest{}, nil, ErrInvalidPolynomialSize
+	}
+
+	blinding := make([]fr.Element, len(p))
+	for i := range blinding {
+		if _, err := blinding[i].SetRandom(); err != nil {
+			return Digest{}, nil, err
+		}
+	}
+
+	res, err := commitHiding(p, blinding, pk, nbTasks...)
+	if err != nil {
+		return Digest{}, nil, err
+	}
+	return res, blinding, nil
+}
+
+// commitHiding returns [f(α)]G₁ + [r(α)]H
+func commitHiding(p, blinding []fr.Element, pk HidingProvingKey, nbTasks ...int) (Digest, error) {
+
+	points := make([]curve.G1Affine, 0, len(p)+len(blinding))
+	points = append(points, pk.G1[:len(p)]...)
+	points = append(points, pk.Blinding[:len(blinding)]...)
+	scalars := make([]fr.Element, 0, len(p)+len(blinding))
+	scalars = append(scalars, p...)
+	scalars = append(scalars, blinding...)
+
+	config := ecc.MultiExpConfig{}
+	if len(nbTasks) > 0 {
+		config.NbTasks = nbTasks[0]
+	}
+	var res Digest
+	if _, err := res.MultiExp(points, scalars, config); err != nil {
+		return Digest{}, err
+	}
+	return res, nil
+}
+
+// OpenHiding computes an opening proof at point of the polynomial p committed
+// with CommitHiding, blinding being the blinding polynomial it returned. The
+// proof reveals p(point) and blinding(point).
+func OpenHiding(p, blinding []fr.Element, point fr.Element, pk HidingProvingKey) (HidingOpeningProof, error) {
+
+	if len(p) == 0 || len(p) > len(pk.G1) || len(p) > len(pk.Blinding) {
+		return HidingOpeningProof{}, ErrInvalidPolynomialSize
+	}
+	if len(blinding) != len(p) {
+		return HidingOpeningProof{}, ErrInvalidBlindingSize
+	}
+
+	res := HidingOpeningProof{
+		ClaimedValue:  eval(p, point),
+		BlindingValue: eval(blinding, point),
+	}
+
+	// compute the quotients, reusing the memory of the copies
+	_p := make([]fr.Element, len(p))
+	copy(_p, p)
+	h := dividePolyByXminusA(_p, res.ClaimedValue, point)
+	_blinding := make([]fr.Element, len(blinding))
+	copy(_blinding, blinding)
+	hBlinding := dividePolyByXminusA(_blinding, res.BlindingValue, point)
+
+	var err error
+	if res.H, err = commitHiding(h, hBlinding, pk); err != nil {
+		return HidingOpeningProof{}, err
+	}
+
+	return res, nil
+}
+
+// VerifyHiding verifies a hiding KZG opening proof at a single point
+func VerifyHiding(commitment *Digest, proof *HidingOpeningProof, point fr.Element, vk HidingVerifyingKey) error {
+
+	// [f(a)]G₁ + [-a]([H(α)]G₁) = [f(a) - a*H(α)]G₁
+	var totalG1, blindingG1 curve.G1Jac
+	var pointNeg fr.Element
+	var cmInt, pointInt, blindingInt big.Int
+	proof.ClaimedValue.BigInt(&cmInt)
+	pointNeg.Neg(&point).BigInt(&pointInt)
+	totalG1.JointScalarMultiplication(&vk.G1, &proof.H, &cmInt, &pointInt)
+
+	// [f(a) - a*H(α)]G₁ + [r(a)]H
+	proof.BlindingValue.BigInt(&blindingInt)
+	blindingG1.FromAffine(&vk.H)
+	blindingG1.ScalarMultiplication(&blindingG1, &blindingInt)
+	totalG1.AddAssign(&blindingG1)
+
+	// [f(a) + r(a)γ - a*H(α)]G₁ + [-(f(α) + r(α)γ)]G₁
+	var commitmentJac curve.G1Jac
+	commitmentJac.FromAffine(commitment)
+	totalG1.SubAssign(&commitmentJac)
+
+	// e([f(α)-f(a)+(r(α)-r(a))γ+aH(α)]G₁], G₂).e([-H(α)]G₁, [α]G₂) == 1
+	var totalG1Aff curve.G1Affine
+	totalG1Aff.FromJacobian(&totalG1)
+	check, err := curve.PairingCheckFixedQ(
+		[]curve.G1Affine{totalG1Aff, proof.H},
+		vk.Lines[:],
+	)
+
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// BatchOpenHidingSinglePoint creates a batch hiding opening proof at point of a
+// list of polynomials committed with CommitHiding, as BatchOpenSinglePoint.
+//
+// * blindings are the blinding polynomials returned by CommitHiding
+// * dataTranscript extra data that might be needed to derive the challenge used for folding
+func BatchOpenHidingSinglePoint(polynomials, blindings [][]fr.Element, digests []Digest, point fr.Element, hf hash.Hash, pk HidingProvingKey, dataTranscript ...[]byte) (BatchHidingOpeningProof, error) {
+
+	nbDigests := len(digests)
+	if nbDigests == 0 {
+		return BatchHidingOpeningProof{}, ErrZeroNbDigests
+	}
+	if nbDigests != len(polynomials) || nbDigests != len(blindings) {
+		return BatchHidingOpeningProof{}, ErrInvalidNbDigests
+	}
+	largestPoly := 0
+	for i, p := range polynomials {
+		if len(p) == 0 || len(p) > len(pk.G1) || len(p) > len(pk.Blinding) {
+			return BatchHidingOpeningProof{}, ErrInvalidPolynomialSize
+		}
+		if len(blindings[i]) != len(p) {
+			return BatchHidingOpeningProof{}, ErrInvalidBlindingSize
+		}
+		if len(p) > largestPoly {
+			largestPoly = len(p)
+		}
+	}
+
+	// compute the purported values
+	res := BatchHidingOpeningProof{
+		ClaimedValues:  make([]fr.Element, nbDigests),
+		BlindingValues: make([]fr.Element, nbDigests),
+	}
+	parallel.Execute(nbDigests, func(start, end int) {
+		for i := start; i < end; i++ {
+			res.ClaimedValues[i] = eval(polynomials[i], point)
+			res.BlindingValues[i] = eval(blindings[i], point)
+		}
+	})
+
+	// derive the challenge γ, binded to the point, the commitments and the values
+	gamma, err := deriveHidingGamma(point, digests, res.ClaimedValues, res.BlindingValues, hf, dataTranscript...)
+	if err != nil {
+		return BatchHidingOpeningProof{}, err
+	}
+
+	// ∑ᵢγⁱfᵢ and ∑ᵢγⁱrᵢ, and their values at point
+	gammai := powers(gamma, nbDigests)
+	foldedPolynomials := make([]fr.Element, largestPoly)
+	foldedBlindings := make([]fr.Element, largestPoly)
+	var foldedEvaluation, foldedBlindingValue, t fr.Element
+	for i := range polynomials {
+		parallel.Execute(len(polynomials[i]), func(start, end int) {
+			var pj fr.Element
+			for j := start; j < end; j++ {
+				pj.Mul(&polynomials[i][j], &gammai[i])
+				foldedPolynomials[j].Add(&foldedPolynomials[j], &pj)
+				pj.Mul(&blindings[i][j], &gammai[i])
+				foldedBlindings[j].Add(&foldedBlindings[j], &pj)
+			}
+		})
+		t.Mul(&res.ClaimedValues[i], &gammai[i])
+		foldedEvaluation.Add(&foldedEvaluation, &t)
+		t.Mul(&res.BlindingValues[i], &gammai[i])
+		foldedBlindingValue.Add(&foldedBlindingValue, &t)
+	}
+
+	// compute the quotients, reusing the memory of the folded polynomials
+	h := dividePolyByXminusA(foldedPolynomials, foldedEvaluation, point)
+	hBlinding := dividePolyByXminusA(foldedBlindings, foldedBlindingValue, point)
+	if res.H, err = commitHiding(h, hBlinding, pk); err != nil {
+		return BatchHidingOpeningProof{}, err
+	}
+
+	return res, nil
+}
+
+// BatchVerifyHidingSinglePoint verifies a batched hiding opening proof at a
+// single point of a list of polynomials.
+//
+// * digests list of digests on which opening proof is done
+// * batchOpeningProof proof of correct opening on the digests
+// * dataTranscript extra data that might be needed to derive the challenge used for the folding
+func BatchVerifyHidingSinglePoint(digests []Digest, batchOpeningProof *BatchHidingOpeningProof, point fr.Element, hf hash.Hash, vk HidingVerifyingKey, dataTranscript ...[]byte) error {
+
+	nbDigests := len(digests)
+	if nbDigests == 0 {
+		return ErrZeroNbDigests
+	}
+	if nbDigests != len(batchOpeningProof.ClaimedValues) || nbDigests != len(batchOpeningProof.BlindingValues) {
+		return ErrInvalidNbDigests
+	}
+
+	gamma, err := deriveHidingGamma(point, digests, batchOpeningProof.ClaimedValues, batchOpeningProof.BlindingValues, hf, dataTranscript...)
+	if err != nil {
+		return err
+	}
+
+	// fold the digests and the values
+	gammai := powers(gamma, nbDigests)
+	foldedDigest, foldedEvaluation, err := fold(digests, batchOpeningProof.ClaimedValues, gammai)
+	if err != nil {
+		return err
+	}
+	var foldedBlindingValue, t fr.Element
+	for i := range batchOpeningProof.BlindingValues {
+		t.Mul(&batchOpeningProof.BlindingValues[i], &gammai[i])
+		foldedBlindingValue.Add(&foldedBlindingValue, &t)
+	}
+
+	foldedProof := HidingOpeningProof{
+		H:             batchOpeningProof.H,
+		ClaimedValue:  foldedEvaluation,
+		BlindingValue: foldedBlindingValue,
+	}
+	return VerifyHiding(&foldedDigest, &foldedProof, point, vk)
+}
+
+// deriveHidingGamma derives the challenge γ used to fold hiding proofs, binded
+// to the point, the commitments, the claimed values and the blinding values
+func deriveHidingGamma(point fr.Element, digests []Digest, claimedValues, blindingValues []fr.Element, hf hash.Hash, dataTranscript ...[]byte) (fr.Element, error) {
+	values := make([]fr.Element, 0, len(claimedValues)+len(blindingValues))
+	values = append(values, claimedValues...)
+	values = append(values, blindingValues...)
+	return deriveGamma(newGammaTranscript(hf), point, digests, values, dataTranscript...)
+}
+
+// powers returns [1, γ, γ², ..., γⁿ⁻¹]
+func powers(gamma fr.Element, n int) []fr.Element {
+	res := make([]fr.Element, n)
+	res[0].SetOne()
+	for i := 1; i < n; i++ {
+		res[i].Mul(&res[i-1], &gamma)
+	}
+	return res
+}
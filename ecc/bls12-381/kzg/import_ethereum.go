@@ -0,0 +1,143 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+var (
+	ErrInvalidEthereumTranscript = errors.New("invalid ethereum KZG ceremony transcript")
+	ErrEthereumTooSmall          = errors.New("the ethereum KZG ceremony transcripts have less powers than the requested SRS size")
+)
+
+// ethereumTranscript JSON encoding of the transcript of the Ethereum KZG
+// ceremony. The points are hex encoded in compressed form, prefixed with 0x.
+type ethereumTranscript struct {
+	Transcripts []struct {
+		NumG1Powers uint64 `json:"numG1Powers"`
+		NumG2Powers uint64 `json:"numG2Powers"`
+		PowersOfTau struct {
+			G1Powers []string `json:"G1Powers"`
+			G2Powers []string `json:"G2Powers"`
+		} `json:"powersOfTau"`
+		Witness struct {
+			RunningProducts []string `json:"runningProducts"`
+			PotPubkeys      []string `json:"potPubkeys"`
+		} `json:"witness"`
+	} `json:"transcripts"`
+}
+
+// ImportEthereumTranscript returns the SRS of size size made of the powers of τ
+// of the Ethereum KZG ceremony, read from its JSON transcript. They are taken
+// from the smallest of the sub-ceremonies with at least size powers in G₁.
+//
+// The witness of the sub-ceremony is verified: each running product is the
+// previous one multiplied by the secret of the corresponding public key, and
+// the last one is [τ]G₁. The SRS is then checked with VerifySRS.
+func ImportEthereumTranscript(r io.Reader, size uint64) (*SRS, error) {
+
+	if size < 2 {
+		return nil, ErrMinSRSSize
+	}
+
+	var transcript ethereumTranscript
+	if err := json.NewDecoder(r).Decode(&transcript); err != nil {
+		return nil, err
+	}
+
+	selected := -1
+	for i, t := range transcript.Transcripts {
+		if t.NumG1Powers >= size && (selected == -1 || t.NumG1Powers < transcript.Transcripts[selected].NumG1Powers) {
+			selected = i
+		}
+	}
+	if selected == -1 {
+		return nil, ErrEthereumTooSmall
+	}
+	t := &transcript.Transcripts[selected]
+	if uint64(len(t.PowersOfTau.G1Powers)) != t.NumG1Powers || uint64(len(t.PowersOfTau.G2Powers)) != t.NumG2Powers ||
+		t.NumG2Powers < 2 || len(t.Witness.RunningProducts) == 0 ||
+		len(t.Witness.RunningProducts) != len(t.Witness.PotPubkeys) {
+		return nil, ErrInvalidEthereumTranscript
+	}
+
+	g1, err := decodeEthereumPoints[curve.G1Affine](t.PowersOfTau.G1Powers[:size])
+	if err != nil {
+		return nil, err
+	}
+	g2, err := decodeEthereumPoints[curve.G2Affine](t.PowersOfTau.G2Powers[:2])
+	if err != nil {
+		return nil, err
+	}
+
+	// the first running product is G₁ and the first public key a placeholder
+	runningProducts, err := decodeEthereumPoints[curve.G1Affine](t.Witness.RunningProducts)
+	if err != nil {
+		return nil, err
+	}
+	pubkeys, err := decodeEthereumPoints[curve.G2Affine](t.Witness.PotPubkeys[1:])
+	if err != nil {
+		return nil, err
+	}
+	_, _, g1Gen, _ := curve.Generators()
+	if !runningProducts[0].Equal(&g1Gen) || !runningProducts[len(runningProducts)-1].Equal(&g1[1]) {
+		return nil, ErrInvalidContribution
+	}
+	if err := verifyRunningProducts(runningProducts, pubkeys); err != nil {
+		return nil, err
+	}
+
+	var srs SRS
+	srs.Pk.G1 = g1
+	srs.Vk.G1 = g1[0]
+	srs.Vk.G2[0] = g2[0]
+	srs.Vk.G2[1] = g2[1]
+	srs.Vk.Lines[0] = curve.PrecomputeLines(srs.Vk.G2[0])
+	srs.Vk.Lines[1] = curve.PrecomputeLines(srs.Vk.G2[1])
+
+	if err := VerifySRS(&srs); err != nil {
+		return nil, err
+	}
+	return &srs, nil
+}
+
+// decodeEthereumPoints decodes 0x prefixed hex encoded compressed points, and
+// checks they are in the subgroup
+func decodeEthereumPoints[T curve.G1Affine | curve.G2Affine, PT interface {
+	*T
+	SetBytes([]byte) (int, error)
+}](encoded []string) ([]T, error) {
+	res := make([]T, len(encoded))
+	return res, decodePoints(len(res), func(i int) error {
+		if !strings.HasPrefix(encoded[i], "0x") {
+			return ErrInvalidEthereumTranscript
+		}
+		b, err := hex.DecodeString(encoded[i][2:])
+		if err != nil {
+			return err
+		}
+		_, err = PT(&res[i]).SetBytes(b)
+		return err
+	})
+}
@@ -0,0 +1,253 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidPtau  = errors.New("invalid ptau file")
+	ErrPtauTooSmall = errors.New("the ptau file has less powers than the requested SRS size")
+)
+
+// sections of a ptau file used to build the SRS
+const (
+	ptauSectionHeader = 1
+	ptauSectionTauG1  = 2
+	ptauSectionTauG2  = 3
+)
+
+// ImportPtau reads the first size powers of τ in G₁ and [τ]G₂ from a Powers of
+// Tau file in the snarkjs format (.ptau), and returns the corresponding SRS,
+// after checking it with VerifySRS. The contributions recorded in the file are
+// not verified.
+//
+// The file starts with the magic "ptau", a version and a number of sections,
+// each made of an id, a size and the content. All the integers are little
+// endian. The coordinates of the points are stored in little endian Montgomery
+// form, the points at infinity being zero. Only the header section and the
+// powers of τ in G₁ and G₂ are read, the other ones are skipped.
+func ImportPtau(r io.Reader, size uint64) (*SRS, error) {
+
+	if size < 2 {
+		return nil, ErrMinSRSSize
+	}
+
+	br := bufio.NewReader(r)
+	var magic [4]byte
+	if _, err := io.ReadFull(br, magic[:]); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(magic[:], []byte("ptau")) {
+		return nil, ErrInvalidPtau
+	}
+	var version, nbSections uint32
+	if err := binary.Read(br, binary.LittleEndian, &version); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(br, binary.LittleEndian, &nbSections); err != nil {
+		return nil, err
+	}
+
+	var (
+		headerRead bool
+		power      uint32
+		g1         []curve.G1Affine
+		g2         []curve.G2Affine
+	)
+	for s := uint32(0); s < nbSections; s++ {
+		var id uint32
+		var sectionSize uint64
+		if err := binary.Read(br, binary.LittleEndian, &id); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(br, binary.LittleEndian, &sectionSize); err != nil {
+			return nil, err
+		}
+		section := io.LimitReader(br, int64(sectionSize))
+
+		var err error
+		switch id {
+		case ptauSectionHeader:
+			power, err = readPtauHeader(section)
+			headerRead = true
+		case ptauSectionTauG1:
+			// the section holds 2^(power+1) - 1 points
+			if !headerRead {
+				return nil, ErrInvalidPtau
+			}
+			if size > (uint64(2)<<power)-1 {
+				return nil, ErrPtauTooSmall
+			}
+			g1, err = readPtauG1(section, size)
+		case ptauSectionTauG2:
+			if !headerRead {
+				return nil, ErrInvalidPtau
+			}
+			g2, err = readPtauG2(section, 2)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// skip the rest of the section
+		if _, err := io.Copy(io.Discard, section); err != nil {
+			return nil, err
+		}
+	}
+	if g1 == nil || g2 == nil {
+		return nil, ErrInvalidPtau
+	}
+
+	var srs SRS
+	srs.Pk.G1 = g1
+	srs.Vk.G1 = g1[0]
+	srs.Vk.G2[0] = g2[0]
+	srs.Vk.G2[1] = g2[1]
+	srs.Vk.Lines[0] = curve.PrecomputeLines(srs.Vk.G2[0])
+	srs.Vk.Lines[1] = curve.PrecomputeLines(srs.Vk.G2[1])
+
+	if err := VerifySRS(&srs); err != nil {
+		return nil, err
+	}
+	return &srs, nil
+}
+
+// readPtauHeader reads the header section, checks that it describes the base
+// field of the curve and returns the power of the file
+func readPtauHeader(r io.Reader) (uint32, error) {
+	var n8 uint32
+	if err := binary.Read(r, binary.LittleEndian, &n8); err != nil {
+		return 0, err
+	}
+	if n8 != fp.Bytes {
+		return 0, ErrInvalidPtau
+	}
+	var q [fp.Bytes]byte
+	if _, err := io.ReadFull(r, q[:]); err != nil {
+		return 0, err
+	}
+	for i, j := 0, len(q)-1; i < j; i, j = i+1, j-1 {
+		q[i], q[j] = q[j], q[i]
+	}
+	if new(big.Int).SetBytes(q[:]).Cmp(fp.Modulus()) != 0 {
+		return 0, ErrInvalidPtau
+	}
+	var power, ceremonyPower uint32
+	if err := binary.Read(r, binary.LittleEndian, &power); err != nil {
+		return 0, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &ceremonyPower); err != nil {
+		return 0, err
+	}
+	if power > 63 {
+		return 0, ErrInvalidPtau
+	}
+	return power, nil
+}
+
+// readPtauG1 reads n points of G₁ and checks they are in the subgroup
+func readPtauG1(r io.Reader, n uint64) ([]curve.G1Affine, error) {
+	const pointSize = 2 * fp.Bytes
+	buf := make([]byte, n*pointSize)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	res := make([]curve.G1Affine, n)
+	return res, decodePoints(len(res), func(i int) error {
+		b := buf[i*pointSize:]
+		if err := setMontgomery(&res[i].X, b[:fp.Bytes]); err != nil {
+			return err
+		}
+		if err := setMontgomery(&res[i].Y, b[fp.Bytes:]); err != nil {
+			return err
+		}
+		if !res[i].IsOnCurve() || !res[i].IsInSubGroup() {
+			return ErrInvalidSRSPoint
+		}
+		return nil
+	})
+}
+
+// readPtauG2 reads n points of G₂ and checks they are in the subgroup. The
+// coordinates in Fp² are stored as a₀ || a₁.
+func readPtauG2(r io.Reader, n uint64) ([]curve.G2Affine, error) {
+	const pointSize = 4 * fp.Bytes
+	buf := make([]byte, n*pointSize)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	res := make([]curve.G2Affine, n)
+	return res, decodePoints(len(res), func(i int) error {
+		b := buf[i*pointSize:]
+		coordinates := []*fp.Element{&res[i].X.A0, &res[i].X.A1, &res[i].Y.A0, &res[i].Y.A1}
+		for j, c := range coordinates {
+			if err := setMontgomery(c, b[j*fp.Bytes:(j+1)*fp.Bytes]); err != nil {
+				return err
+			}
+		}
+		if !res[i].IsOnCurve() || !res[i].IsInSubGroup() {
+			return ErrInvalidSRSPoint
+		}
+		return nil
+	})
+}
+
+// setMontgomery sets z to the element whose Montgomery form is given by b, in
+// little endian. b must be smaller than the modulus.
+func setMontgomery(z *fp.Element, b []byte) error {
+	var buf [fp.Bytes]byte
+	copy(buf[:], b)
+	v, err := fp.LittleEndian.Element(&buf)
+	if err != nil {
+		return err
+	}
+	// the Montgomery form of v is b·R, and the element with Montgomery form 1 is R⁻¹
+	var rInv fp.Element
+	rInv[0] = 1
+	z.Mul(&v, &rInv)
+	return nil
+}
+
+// decodePoints runs decode(i) for 0 ≤ i < n in parallel and returns the first
+// error encountered, if any
+func decodePoints(n int, decode func(i int) error) error {
+	chErr := make(chan error, 1)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			if err := decode(i); err != nil {
+				select {
+				case chErr <- err:
+				default:
+				}
+				return
+			}
+		}
+	})
+	close(chErr)
+	return <-chErr
+}
@@ -0,0 +1,27 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kzg4844 implements the KZG commitment API of EIP-4844 (Ethereum
+// blobs) on top of the bls12-381 kzg and fft packages.
+//
+// A blob is a vector of 4096 field elements, the evaluations of a polynomial
+// of degree < 4096 on the 4096-th roots of unity, taken in bit-reversed order.
+// Commitments and proofs are computed directly in this Lagrange form, with a
+// trusted setup in Lagrange basis; the Fiat-Shamir challenges, the batch
+// verification and the serialization follow the Deneb consensus specification.
+//
+// See
+//   - https://eips.ethereum.org/EIPS/eip-4844
+//   - https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md
+package kzg4844
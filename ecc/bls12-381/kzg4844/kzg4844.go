@@ -0,0 +1,471 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kzg4844
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+)
+
+const (
+	// ScalarsPerBlob is the number of field elements of a blob
+	ScalarsPerBlob = 4096
+	// BytesPerFieldElement is the size of a serialized field element
+	BytesPerFieldElement = fr.Bytes
+	// BytesPerBlob is the size of a serialized blob
+	BytesPerBlob = ScalarsPerBlob * BytesPerFieldElement
+	// BytesPerCommitment is the size of a serialized commitment
+	BytesPerCommitment = bls12381.SizeOfG1AffineCompressed
+	// BytesPerProof is the size of a serialized proof
+	BytesPerProof = bls12381.SizeOfG1AffineCompressed
+
+	fiatShamirProtocolDomain   = "FSBLOBVERIFY_V1_"
+	randomChallengeBatchDomain = "RCKZGBATCH___V1_"
+)
+
+var (
+	ErrVerifyOpeningProof = errors.New("can't verify opening proof")
+	ErrInvalidSetupSize   = errors.New("the trusted setup must contain 4096 G1 points and at least 2 G2 points")
+	ErrLengthMismatch     = errors.New("the number of blobs, commitments and proofs must match")
+)
+
+// Blob is a serialized vector of 4096 canonical field elements, in big endian
+type Blob [BytesPerBlob]byte
+
+// Scalar is a serialized canonical field element, in big endian
+type Scalar [BytesPerFieldElement]byte
+
+// Commitment is a serialized KZG commitment, a compressed G1 point
+type Commitment [BytesPerCommitment]byte
+
+// Proof is a serialized KZG opening proof, a compressed G1 point
+type Proof [BytesPerProof]byte
+
+// Context holds the trusted setup in Lagrange basis along with the
+// precomputed domain.
+type Context struct {
+	// lagrangeG1 are the commitments [Lᵢ(τ)]G₁ to the Lagrange polynomials
+	// of the domain, in bit-reversed order
+	lagrangeG1 []bls12381.G1Affine
+
+	// vk holds [G₁, G₂, [τ]G₂]
+	vk kzg.VerifyingKey
+
+	// rootsBrp are the 4096-th roots of unity in bit-reversed order
+	rootsBrp []fr.Element
+}
+
+// NewContext returns a context from a trusted setup given in Lagrange basis
+// (bit-reversed, as in the Ethereum trusted setup), together with [G₂, [τ]G₂].
+func NewContext(lagrangeG1 []bls12381.G1Affine, g2 [2]bls12381.G2Affine) (*Context, error) {
+	if len(lagrangeG1) != ScalarsPerBlob {
+		return nil, ErrInvalidSetupSize
+	}
+	ctx := &Context{
+		lagrangeG1: make([]bls12381.G1Affine, ScalarsPerBlob),
+		rootsBrp:   make([]fr.Element, ScalarsPerBlob),
+	}
+	copy(ctx.lagrangeG1, lagrangeG1)
+
+	_, _, ctx.vk.G1, _ = bls12381.Generators()
+	ctx.vk.G2 = g2
+	ctx.vk.Lines[0] = bls12381.PrecomputeLines(ctx.vk.G2[0])
+	ctx.vk.Lines[1] = bls12381.PrecomputeLines(ctx.vk.G2[1])
+
+	// the primitive root 7 of the specification is the multiplicative
+	// generator used by the fft package, so that the domains coincide
+	domain := fft.NewDomain(ScalarsPerBlob)
+	fft.BuildExpTable(domain.Generator, ctx.rootsBrp)
+	fft.BitReverse(ctx.rootsBrp)
+
+	return ctx, nil
+}
+
+// NewContextFromSRS returns a context from a trusted setup in monomial basis,
+// of size at least 4096. The Lagrange basis is computed with kzg.ToLagrangeG1.
+func NewContextFromSRS(srs *kzg.SRS) (*Context, error) {
+	if len(srs.Pk.G1) < ScalarsPerBlob {
+		return nil, ErrInvalidSetupSize
+	}
+	lagrangeG1, err := kzg.ToLagrangeG1(srs.Pk.G1[:ScalarsPerBlob])
+	if err != nil {
+		return nil, err
+	}
+	bitReverse(lagrangeG1)
+	return NewContext(lagrangeG1, srs.Vk.G2)
+}
+
+// BlobToKZGCommitment returns the commitment to the polynomial whose
+// evaluations are given by the blob.
+func (ctx *Context) BlobToKZGCommitment(blob *Blob) (Commitment, error) {
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return Commitment{}, err
+	}
+	commitment, err := ctx.commit(polynomial)
+	if err != nil {
+		return Commitment{}, err
+	}
+	return commitment.Bytes(), nil
+}
+
+// ComputeKZGProof returns the proof of the evaluation at z of the polynomial
+// whose evaluations are given by the blob, together with the evaluation y.
+func (ctx *Context) ComputeKZGProof(blob *Blob, z Scalar) (Proof, Scalar, error) {
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return Proof{}, Scalar{}, err
+	}
+	var zz fr.Element
+	if err := zz.SetBytesCanonical(z[:]); err != nil {
+		return Proof{}, Scalar{}, err
+	}
+	proof, y, err := ctx.computeKZGProof(polynomial, zz)
+	if err != nil {
+		return Proof{}, Scalar{}, err
+	}
+	return proof.Bytes(), y.Bytes(), nil
+}
+
+// ComputeBlobKZGProof returns the proof of the evaluation of the blob at the
+// Fiat-Shamir challenge derived from the blob and its commitment.
+func (ctx *Context) ComputeBlobKZGProof(blob *Blob, commitment Commitment) (Proof, error) {
+	if _, err := bytesToG1(commitment[:]); err != nil {
+		return Proof{}, err
+	}
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return Proof{}, err
+	}
+	z := computeChallenge(blob, &commitment)
+	proof, _, err := ctx.computeKZGProof(polynomial, z)
+	if err != nil {
+		return Proof{}, err
+	}
+	return proof.Bytes(), nil
+}
+
+// VerifyKZGProof verifies that proof attests that the polynomial committed to
+// in commitment evaluates to y at z. It returns ErrVerifyOpeningProof if the
+// proof is invalid.
+func (ctx *Context) VerifyKZGProof(commitment Commitment, z, y Scalar, proof Proof) error {
+	c, err := bytesToG1(commitment[:])
+	if err != nil {
+		return err
+	}
+	var zz, yy fr.Element
+	if err := zz.SetBytesCanonical(z[:]); err != nil {
+		return err
+	}
+	if err := yy.SetBytesCanonical(y[:]); err != nil {
+		return err
+	}
+	H, err := bytesToG1(proof[:])
+	if err != nil {
+		return err
+	}
+	return ctx.verifyKZGProof(&c, zz, yy, &H)
+}
+
+// VerifyBlobKZGProof verifies a proof computed by ComputeBlobKZGProof.
+func (ctx *Context) VerifyBlobKZGProof(blob *Blob, commitment Commitment, proof Proof) error {
+	c, err := bytesToG1(commitment[:])
+	if err != nil {
+		return err
+	}
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return err
+	}
+	H, err := bytesToG1(proof[:])
+	if err != nil {
+		return err
+	}
+	z := computeChallenge(blob, &commitment)
+	y := ctx.evaluate(polynomial, z)
+	return ctx.verifyKZGProof(&c, z, y, &H)
+}
+
+// VerifyBlobKZGProofBatch verifies proofs computed by ComputeBlobKZGProof for
+// several blobs, with a random linear combination and a single pairing check.
+func (ctx *Context) VerifyBlobKZGProofBatch(blobs []Blob, commitments []Commitment, proofs []Proof) error {
+	if len(blobs) != len(commitments) || len(blobs) != len(proofs) {
+		return ErrLengthMismatch
+	}
+	n := len(blobs)
+	C := make([]bls12381.G1Affine, n)
+	H := make([]bls12381.G1Affine, n)
+	zs := make([]fr.Element, n)
+	ys := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		var err error
+		if C[i], err = bytesToG1(commitments[i][:]); err != nil {
+			return err
+		}
+		polynomial, err := blobToPolynomial(&blobs[i])
+		if err != nil {
+			return err
+		}
+		if H[i], err = bytesToG1(proofs[i][:]); err != nil {
+			return err
+		}
+		zs[i] = computeChallenge(&blobs[i], &commitments[i])
+		ys[i] = ctx.evaluate(polynomial, zs[i])
+	}
+	return ctx.verifyKZGProofBatch(C, zs, ys, H, commitments, proofs)
+}
+
+// commit returns ∑ᵢ pᵢ[Lᵢ(τ)]G₁
+func (ctx *Context) commit(polynomial []fr.Element) (bls12381.G1Affine, error) {
+	var res bls12381.G1Affine
+	if _, err := res.MultiExp(ctx.lagrangeG1, polynomial, ecc.MultiExpConfig{}); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// computeKZGProof computes the quotient (p(X)-y)/(X-z) in evaluation form and
+// commits to it. When z is in the domain, the evaluation of the quotient at z
+// is obtained from the derivative formula.
+func (ctx *Context) computeKZGProof(polynomial []fr.Element, z fr.Element) (bls12381.G1Affine, fr.Element, error) {
+	y := ctx.evaluate(polynomial, z)
+
+	// denominators ωᵢ - z, and z⋅(z-ωᵢ) for the evaluation at z if z = ωₘ
+	m := -1
+	denominators := make([]fr.Element, ScalarsPerBlob)
+	for i := range denominators {
+		denominators[i].Sub(&ctx.rootsBrp[i], &z)
+		if denominators[i].IsZero() {
+			m = i
+		}
+	}
+	denominators = fr.BatchInvert(denominators)
+
+	quotient := make([]fr.Element, ScalarsPerBlob)
+	for i := range quotient {
+		if i == m {
+			continue
+		}
+		quotient[i].Sub(&polynomial[i], &y).Mul(&quotient[i], &denominators[i])
+	}
+
+	if m != -1 {
+		// q(ωₘ) = ∑_{i≠m} (pᵢ-y)⋅ωᵢ / (z⋅(z-ωᵢ)), and 1/(z-ωᵢ) = -1/(ωᵢ-z)
+		var zInv, tmp fr.Element
+		zInv.Inverse(&z)
+		for i := range quotient {
+			if i == m {
+				continue
+			}
+			tmp.Sub(&polynomial[i], &y).
+				Mul(&tmp, &ctx.rootsBrp[i]).
+				Mul(&tmp, &denominators[i])
+			quotient[m].Sub(&quotient[m], &tmp)
+		}
+		quotient[m].Mul(&quotient[m], &zInv)
+	}
+
+	proof, err := ctx.commit(quotient)
+	return proof, y, err
+}
+
+// evaluate returns the evaluation at z of the polynomial given in evaluation
+// form, with the barycentric formula
+//
+// p(z) = (zⁿ-1)/n ∑ᵢ pᵢ⋅ωᵢ/(z-ωᵢ)
+func (ctx *Context) evaluate(polynomial []fr.Element, z fr.Element) fr.Element {
+	denominators := make([]fr.Element, ScalarsPerBlob)
+	for i := range denominators {
+		denominators[i].Sub(&z, &ctx.rootsBrp[i])
+		if denominators[i].IsZero() {
+			return polynomial[i]
+		}
+	}
+	denominators = fr.BatchInvert(denominators)
+
+	var res, tmp fr.Element
+	for i := range polynomial {
+		tmp.Mul(&polynomial[i], &ctx.rootsBrp[i]).Mul(&tmp, &denominators[i])
+		res.Add(&res, &tmp)
+	}
+
+	var zn, nInv fr.Element
+	zn.Exp(z, big.NewInt(ScalarsPerBlob))
+	zn.Sub(&zn, new(fr.Element).SetOne())
+	nInv.SetUint64(ScalarsPerBlob).Inverse(&nInv)
+	res.Mul(&res, &zn).Mul(&res, &nInv)
+
+	return res
+}
+
+// verifyKZGProof checks that e([C]-[y]G₁, -G₂)⋅e(H, [τ]G₂-[z]G₂) == 1
+func (ctx *Context) verifyKZGProof(commitment *bls12381.G1Affine, z, y fr.Element, proof *bls12381.G1Affine) error {
+	var yG1, cMinusY bls12381.G1Affine
+	var bY big.Int
+	yG1.ScalarMultiplication(&ctx.vk.G1, y.BigInt(&bY))
+	cMinusY.Sub(commitment, &yG1)
+
+	var zG2, tauMinusZ, negG2 bls12381.G2Affine
+	var bZ big.Int
+	zG2.ScalarMultiplication(&ctx.vk.G2[0], z.BigInt(&bZ))
+	tauMinusZ.Sub(&ctx.vk.G2[1], &zG2)
+	negG2.Neg(&ctx.vk.G2[0])
+
+	check, err := bls12381.PairingCheck(
+		[]bls12381.G1Affine{cMinusY, *proof},
+		[]bls12381.G2Affine{negG2, tauMinusZ},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// verifyKZGProofBatch checks the proofs with powers of a random challenge r
+// derived from the transcript:
+//
+// e(∑ rⁱHᵢ, -[τ]G₂)⋅e(∑ rⁱ(Cᵢ - [yᵢ]G₁ + [zᵢ]Hᵢ), G₂) == 1
+func (ctx *Context) verifyKZGProofBatch(C []bls12381.G1Affine, zs, ys []fr.Element, H []bls12381.G1Affine, commitments []Commitment, proofs []Proof) error {
+	n := len(C)
+	if n == 0 {
+		return nil
+	}
+	if n == 1 {
+		return ctx.verifyKZGProof(&C[0], zs[0], ys[0], &H[0])
+	}
+
+	// r = hash_to_bls_field(DOMAIN ∥ n_per_blob ∥ n ∥ (Cᵢ ∥ zᵢ ∥ yᵢ ∥ Hᵢ)ᵢ)
+	h := sha256.New()
+	h.Write([]byte(randomChallengeBatchDomain))
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], ScalarsPerBlob)
+	h.Write(buf[:])
+	binary.BigEndian.PutUint64(buf[:], uint64(n))
+	h.Write(buf[:])
+	for i := 0; i < n; i++ {
+		h.Write(commitments[i][:])
+		zb, yb := zs[i].Bytes(), ys[i].Bytes()
+		h.Write(zb[:])
+		h.Write(yb[:])
+		h.Write(proofs[i][:])
+	}
+	var r fr.Element
+	r.SetBytes(h.Sum(nil))
+
+	rPowers := make([]fr.Element, n)
+	rPowers[0].SetOne()
+	for i := 1; i < n; i++ {
+		rPowers[i].Mul(&rPowers[i-1], &r)
+	}
+
+	// ∑ rⁱHᵢ
+	var proofLincomb bls12381.G1Affine
+	if _, err := proofLincomb.MultiExp(H, rPowers, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+
+	// ∑ rⁱ(Cᵢ + [zᵢ]Hᵢ) - [∑ rⁱyᵢ]G₁
+	points := make([]bls12381.G1Affine, 2*n+1)
+	scalars := make([]fr.Element, 2*n+1)
+	copy(points, C)
+	copy(points[n:], H)
+	points[2*n] = ctx.vk.G1
+	copy(scalars, rPowers)
+	var tmp fr.Element
+	for i := 0; i < n; i++ {
+		scalars[n+i].Mul(&zs[i], &rPowers[i])
+		tmp.Mul(&ys[i], &rPowers[i])
+		scalars[2*n].Sub(&scalars[2*n], &tmp)
+	}
+	var rhs bls12381.G1Affine
+	if _, err := rhs.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+
+	var negTau bls12381.G2Affine
+	negTau.Neg(&ctx.vk.G2[1])
+	check, err := bls12381.PairingCheck(
+		[]bls12381.G1Affine{proofLincomb, rhs},
+		[]bls12381.G2Affine{negTau, ctx.vk.G2[0]},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// computeChallenge returns
+// hash_to_bls_field(FIAT_SHAMIR_PROTOCOL_DOMAIN ∥ ScalarsPerBlob ∥ blob ∥ commitment)
+// where the degree is encoded on 16 bytes in big endian
+func computeChallenge(blob *Blob, commitment *Commitment) fr.Element {
+	h := sha256.New()
+	h.Write([]byte(fiatShamirProtocolDomain))
+	var degree [16]byte
+	binary.BigEndian.PutUint64(degree[8:], ScalarsPerBlob)
+	h.Write(degree[:])
+	h.Write(blob[:])
+	h.Write(commitment[:])
+
+	var res fr.Element
+	res.SetBytes(h.Sum(nil))
+	return res
+}
+
+// blobToPolynomial deserializes the blob, checking that the elements are
+// canonical.
+func blobToPolynomial(blob *Blob) ([]fr.Element, error) {
+	res := make([]fr.Element, ScalarsPerBlob)
+	for i := range res {
+		if err := res[i].SetBytesCanonical(blob[i*BytesPerFieldElement : (i+1)*BytesPerFieldElement]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// bytesToG1 decompresses a commitment or a proof, checking that the point is
+// in the subgroup. The point at infinity is allowed.
+func bytesToG1(buf []byte) (bls12381.G1Affine, error) {
+	var p bls12381.G1Affine
+	_, err := p.SetBytes(buf)
+	return p, err
+}
+
+func bitReverse[T any](a []T) {
+	n := uint64(len(a))
+	nn := uint64(64 - bits.TrailingZeros64(n))
+
+	for i := uint64(0); i < n; i++ {
+		irev := bits.Reverse64(i) >> nn
+		if irev > i {
+			a[i], a[irev] = a[irev], a[i]
+		}
+	}
+}
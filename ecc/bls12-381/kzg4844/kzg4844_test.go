@@ -0,0 +1,241 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kzg4844
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testSRS     *kzg.SRS
+	testContext *Context
+	testOnce    sync.Once
+)
+
+// getTestContext returns a context from an insecure SRS, for testing only
+func getTestContext(t testing.TB) (*kzg.SRS, *Context) {
+	testOnce.Do(func() {
+		var err error
+		testSRS, err = kzg.NewSRS(ScalarsPerBlob, big.NewInt(42))
+		if err != nil {
+			panic(err)
+		}
+		testContext, err = NewContextFromSRS(testSRS)
+		if err != nil {
+			panic(err)
+		}
+	})
+	return testSRS, testContext
+}
+
+func randomBlob() (*Blob, []fr.Element) {
+	var blob Blob
+	evaluations := make([]fr.Element, ScalarsPerBlob)
+	for i := range evaluations {
+		evaluations[i].SetRandom()
+		b := evaluations[i].Bytes()
+		copy(blob[i*BytesPerFieldElement:], b[:])
+	}
+	return &blob, evaluations
+}
+
+// toCanonical returns the coefficients of the polynomial whose evaluations on
+// the bit-reversed domain are given
+func toCanonical(evaluations []fr.Element) []fr.Element {
+	coefficients := make([]fr.Element, len(evaluations))
+	copy(coefficients, evaluations)
+	domain := fft.NewDomain(uint64(len(coefficients)))
+	domain.FFTInverse(coefficients, fft.DIT)
+	return coefficients
+}
+
+func TestConsistencyWithKZG(t *testing.T) {
+	assert := require.New(t)
+	srs, ctx := getTestContext(t)
+
+	blob, evaluations := randomBlob()
+	coefficients := toCanonical(evaluations)
+
+	// commitment
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	assert.NoError(err)
+	digest, err := kzg.Commit(coefficients, srs.Pk)
+	assert.NoError(err)
+	assert.Equal(digest.Bytes(), [BytesPerCommitment]byte(commitment))
+
+	// opening proof outside of the domain
+	var z fr.Element
+	z.SetRandom()
+	proof, y, err := ctx.ComputeKZGProof(blob, z.Bytes())
+	assert.NoError(err)
+	openingProof, err := kzg.Open(coefficients, z, srs.Pk)
+	assert.NoError(err)
+	assert.Equal(openingProof.H.Bytes(), [BytesPerProof]byte(proof))
+	assert.Equal(openingProof.ClaimedValue.Bytes(), [BytesPerFieldElement]byte(y))
+	assert.NoError(ctx.VerifyKZGProof(commitment, z.Bytes(), y, proof))
+}
+
+func TestProofInDomain(t *testing.T) {
+	assert := require.New(t)
+	srs, ctx := getTestContext(t)
+
+	blob, evaluations := randomBlob()
+	coefficients := toCanonical(evaluations)
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	assert.NoError(err)
+
+	// the evaluation at the i-th root of the bit-reversed domain is the i-th
+	// element of the blob
+	for _, i := range []int{0, 1, 1234, ScalarsPerBlob - 1} {
+		z := ctx.rootsBrp[i]
+		proof, y, err := ctx.ComputeKZGProof(blob, z.Bytes())
+		assert.NoError(err)
+		assert.Equal(evaluations[i].Bytes(), [BytesPerFieldElement]byte(y))
+
+		openingProof, err := kzg.Open(coefficients, z, srs.Pk)
+		assert.NoError(err)
+		assert.Equal(openingProof.H.Bytes(), [BytesPerProof]byte(proof))
+		assert.NoError(ctx.VerifyKZGProof(commitment, z.Bytes(), y, proof))
+	}
+}
+
+func TestBlobProof(t *testing.T) {
+	assert := require.New(t)
+	_, ctx := getTestContext(t)
+
+	const nbBlobs = 3
+	blobs := make([]Blob, nbBlobs)
+	commitments := make([]Commitment, nbBlobs)
+	proofs := make([]Proof, nbBlobs)
+	for i := range blobs {
+		blob, _ := randomBlob()
+		blobs[i] = *blob
+		var err error
+		commitments[i], err = ctx.BlobToKZGCommitment(&blobs[i])
+		assert.NoError(err)
+		proofs[i], err = ctx.ComputeBlobKZGProof(&blobs[i], commitments[i])
+		assert.NoError(err)
+		assert.NoError(ctx.VerifyBlobKZGProof(&blobs[i], commitments[i], proofs[i]))
+	}
+	assert.NoError(ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+	assert.NoError(ctx.VerifyBlobKZGProofBatch(nil, nil, nil))
+	assert.Equal(ErrLengthMismatch, ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs[1:]))
+
+	// swapping two proofs must fail
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	assert.Equal(ErrVerifyOpeningProof, ctx.VerifyBlobKZGProof(&blobs[0], commitments[0], proofs[0]))
+	assert.Equal(ErrVerifyOpeningProof, ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+}
+
+func TestInvalidInputs(t *testing.T) {
+	assert := require.New(t)
+	_, ctx := getTestContext(t)
+
+	blob, _ := randomBlob()
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	assert.NoError(err)
+	var z fr.Element
+	z.SetRandom()
+	proof, y, err := ctx.ComputeKZGProof(blob, z.Bytes())
+	assert.NoError(err)
+
+	// wrong evaluation
+	var wrongY fr.Element
+	assert.NoError(wrongY.SetBytesCanonical(y[:]))
+	wrongY.Add(&wrongY, new(fr.Element).SetOne())
+	assert.Equal(ErrVerifyOpeningProof, ctx.VerifyKZGProof(commitment, z.Bytes(), wrongY.Bytes(), proof))
+
+	// non canonical field element
+	var modulus Scalar
+	fr.Modulus().FillBytes(modulus[:])
+	assert.Error(ctx.VerifyKZGProof(commitment, modulus, y, proof))
+	_, _, err = ctx.ComputeKZGProof(blob, modulus)
+	assert.Error(err)
+	copy(blob[BytesPerFieldElement:], modulus[:])
+	_, err = ctx.BlobToKZGCommitment(blob)
+	assert.Error(err)
+
+	// invalid point
+	var invalid Commitment
+	invalid[0] = 0xff
+	assert.Error(ctx.VerifyKZGProof(invalid, z.Bytes(), y, proof))
+}
+
+func TestLoadTrustedSetup(t *testing.T) {
+	assert := require.New(t)
+	srs, ctx := getTestContext(t)
+
+	var setup trustedSetupJSON
+	for i := range ctx.lagrangeG1 {
+		b := ctx.lagrangeG1[i].Bytes()
+		setup.G1Lagrange = append(setup.G1Lagrange, "0x"+hex.EncodeToString(b[:]))
+	}
+	for i := range srs.Vk.G2 {
+		b := srs.Vk.G2[i].Bytes()
+		setup.G2Monomial = append(setup.G2Monomial, "0x"+hex.EncodeToString(b[:]))
+	}
+	var buf bytes.Buffer
+	assert.NoError(json.NewEncoder(&buf).Encode(setup))
+
+	loaded, err := LoadTrustedSetup(&buf)
+	assert.NoError(err)
+	assert.Equal(ctx.lagrangeG1, loaded.lagrangeG1)
+	assert.Equal(ctx.vk.G2, loaded.vk.G2)
+
+	// legacy keys
+	setup.LegacyG1Lagrange, setup.G1Lagrange = setup.G1Lagrange, nil
+	setup.LegacyG2, setup.G2Monomial = setup.G2Monomial, nil
+	buf.Reset()
+	assert.NoError(json.NewEncoder(&buf).Encode(setup))
+	loaded, err = LoadTrustedSetup(&buf)
+	assert.NoError(err)
+	assert.Equal(ctx.lagrangeG1, loaded.lagrangeG1)
+
+	// truncated setup
+	setup.LegacyG1Lagrange = setup.LegacyG1Lagrange[1:]
+	buf.Reset()
+	assert.NoError(json.NewEncoder(&buf).Encode(setup))
+	_, err = LoadTrustedSetup(&buf)
+	assert.Equal(ErrInvalidSetupSize, err)
+}
+
+func BenchmarkBlobToKZGCommitment(b *testing.B) {
+	_, ctx := getTestContext(b)
+	blob, _ := randomBlob()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = ctx.BlobToKZGCommitment(blob)
+	}
+}
+
+func BenchmarkComputeBlobKZGProof(b *testing.B) {
+	_, ctx := getTestContext(b)
+	blob, _ := randomBlob()
+	commitment, _ := ctx.BlobToKZGCommitment(blob)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = ctx.ComputeBlobKZGProof(blob, commitment)
+	}
+}
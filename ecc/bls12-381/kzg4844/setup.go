@@ -0,0 +1,93 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kzg4844
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// trustedSetupJSON is the format of the Ethereum trusted setup. Both the
+// current (g1_lagrange, g2_monomial) and the legacy (setup_G1_lagrange,
+// setup_G2) keys are supported.
+type trustedSetupJSON struct {
+	G1Monomial []string `json:"g1_monomial"`
+	G1Lagrange []string `json:"g1_lagrange"`
+	G2Monomial []string `json:"g2_monomial"`
+
+	LegacyG1Lagrange []string `json:"setup_G1_lagrange"`
+	LegacyG2         []string `json:"setup_G2"`
+}
+
+// LoadTrustedSetup reads a trusted setup in the JSON format of the Ethereum
+// KZG ceremony (trusted_setup.json) and returns the corresponding context.
+//
+// The points are given as hex encoded compressed points, and are checked to be
+// in the correct subgroup.
+func LoadTrustedSetup(r io.Reader) (*Context, error) {
+	var setup trustedSetupJSON
+	if err := json.NewDecoder(r).Decode(&setup); err != nil {
+		return nil, err
+	}
+	g1Lagrange, g2Monomial := setup.G1Lagrange, setup.G2Monomial
+	if len(g1Lagrange) == 0 {
+		g1Lagrange = setup.LegacyG1Lagrange
+	}
+	if len(g2Monomial) == 0 {
+		g2Monomial = setup.LegacyG2
+	}
+	if len(g1Lagrange) != ScalarsPerBlob || len(g2Monomial) < 2 {
+		return nil, ErrInvalidSetupSize
+	}
+
+	lagrangeG1 := make([]bls12381.G1Affine, ScalarsPerBlob)
+	for i := range lagrangeG1 {
+		buf, err := decodeHex(g1Lagrange[i])
+		if err != nil {
+			return nil, err
+		}
+		if _, err := lagrangeG1[i].SetBytes(buf); err != nil {
+			return nil, err
+		}
+	}
+
+	var g2 [2]bls12381.G2Affine
+	for i := range g2 {
+		buf, err := decodeHex(g2Monomial[i])
+		if err != nil {
+			return nil, err
+		}
+		if _, err := g2[i].SetBytes(buf); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewContext(lagrangeG1, g2)
+}
+
+// decodeHex decodes a hex string, with or without the 0x prefix
+func decodeHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(s, "0x")
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("invalid hex encoding in trusted setup: " + err.Error())
+	}
+	return buf, nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kzg4844
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+// The official test vectors of the consensus specification are not vendored.
+// To run them, copy the mainnet trusted setup to testdata/trusted_setup.json
+// and the tests/general/deneb/kzg directory of the consensus-spec-tests
+// release to testdata, so that the test cases are found at
+// testdata/<handler>/kzg-mainnet/<case>/data.yaml. Once the trusted setup is
+// found, every handler must have test cases.
+const testdataDir = "testdata"
+
+type vectorInput struct {
+	Blob        string   `yaml:"blob"`
+	Commitment  string   `yaml:"commitment"`
+	Z           string   `yaml:"z"`
+	Y           string   `yaml:"y"`
+	Proof       string   `yaml:"proof"`
+	Blobs       []string `yaml:"blobs"`
+	Commitments []string `yaml:"commitments"`
+	Proofs      []string `yaml:"proofs"`
+}
+
+type vector struct {
+	Input  vectorInput `yaml:"input"`
+	Output interface{} `yaml:"output"`
+}
+
+func TestConsensusSpecVectors(t *testing.T) {
+	f, err := os.Open(filepath.Join(testdataDir, "trusted_setup.json"))
+	if err != nil {
+		t.Skip("consensus-spec test vectors not found in testdata")
+	}
+	defer f.Close()
+	ctx, err := LoadTrustedSetup(f)
+	require.NoError(t, err)
+
+	handlers := map[string]func(*testing.T, *Context, *vector){
+		"blob_to_kzg_commitment":      runBlobToKZGCommitment,
+		"compute_kzg_proof":           runComputeKZGProof,
+		"compute_blob_kzg_proof":      runComputeBlobKZGProof,
+		"verify_kzg_proof":            runVerifyKZGProof,
+		"verify_blob_kzg_proof":       runVerifyBlobKZGProof,
+		"verify_blob_kzg_proof_batch": runVerifyBlobKZGProofBatch,
+	}
+	for handler, run := range handlers {
+		files, err := filepath.Glob(filepath.Join(testdataDir, handler, "kzg-mainnet", "*", "data.yaml"))
+		require.NoError(t, err)
+		require.NotEmpty(t, files, "no consensus-spec test vectors found for %s", handler)
+		for _, file := range files {
+			t.Run(handler+"/"+filepath.Base(filepath.Dir(file)), func(t *testing.T) {
+				data, err := os.ReadFile(file)
+				require.NoError(t, err)
+				var v vector
+				require.NoError(t, yaml.Unmarshal(data, &v))
+				run(t, ctx, &v)
+			})
+		}
+	}
+}
+
+// decodeFixed decodes s into dst, returning false if the encoding or the
+// length is invalid, which the test vectors expect to be rejected
+func decodeFixed(s string, dst []byte) bool {
+	buf, err := decodeHex(s)
+	if err != nil || len(buf) != len(dst) {
+		return false
+	}
+	copy(dst, buf)
+	return true
+}
+
+func decodeBlob(s string) (*Blob, bool) {
+	var blob Blob
+	ok := decodeFixed(s, blob[:])
+	return &blob, ok
+}
+
+// expectBytes checks the output of a computation: a nil output means the
+// inputs are invalid
+func expectBytes(t *testing.T, v *vector, res []byte, err error) {
+	if v.Output == nil {
+		require.Error(t, err)
+		return
+	}
+	require.NoError(t, err)
+	var expected = make([]byte, len(res))
+	require.True(t, decodeFixed(v.Output.(string), expected))
+	require.Equal(t, expected, res)
+}
+
+// expectVerify checks the output of a verification: a nil output means the
+// inputs are invalid, false that the proof is rejected
+func expectVerify(t *testing.T, v *vector, err error) {
+	switch v.Output {
+	case nil:
+		require.Error(t, err)
+		require.NotEqual(t, ErrVerifyOpeningProof, err)
+	case true:
+		require.NoError(t, err)
+	case false:
+		require.Equal(t, ErrVerifyOpeningProof, err)
+	}
+}
+
+func runBlobToKZGCommitment(t *testing.T, ctx *Context, v *vector) {
+	blob, ok := decodeBlob(v.Input.Blob)
+	if !ok {
+		require.Nil(t, v.Output)
+		return
+	}
+	c, err := ctx.BlobToKZGCommitment(blob)
+	expectBytes(t, v, c[:], err)
+}
+
+func runComputeKZGProof(t *testing.T, ctx *Context, v *vector) {
+	blob, ok := decodeBlob(v.Input.Blob)
+	var z Scalar
+	if !ok || !decodeFixed(v.Input.Z, z[:]) {
+		require.Nil(t, v.Output)
+		return
+	}
+	proof, y, err := ctx.ComputeKZGProof(blob, z)
+	if v.Output == nil {
+		require.Error(t, err)
+		return
+	}
+	require.NoError(t, err)
+	output := v.Output.([]interface{})
+	var expectedProof Proof
+	var expectedY Scalar
+	require.True(t, decodeFixed(output[0].(string), expectedProof[:]))
+	require.True(t, decodeFixed(output[1].(string), expectedY[:]))
+	require.Equal(t, expectedProof, proof)
+	require.Equal(t, expectedY, y)
+}
+
+func runComputeBlobKZGProof(t *testing.T, ctx *Context, v *vector) {
+	blob, ok := decodeBlob(v.Input.Blob)
+	var c Commitment
+	if !ok || !decodeFixed(v.Input.Commitment, c[:]) {
+		require.Nil(t, v.Output)
+		return
+	}
+	proof, err := ctx.ComputeBlobKZGProof(blob, c)
+	expectBytes(t, v, proof[:], err)
+}
+
+func runVerifyKZGProof(t *testing.T, ctx *Context, v *vector) {
+	var c Commitment
+	var z, y Scalar
+	var proof Proof
+	if !decodeFixed(v.Input.Commitment, c[:]) || !decodeFixed(v.Input.Z, z[:]) ||
+		!decodeFixed(v.Input.Y, y[:]) || !decodeFixed(v.Input.Proof, proof[:]) {
+		require.Nil(t, v.Output)
+		return
+	}
+	expectVerify(t, v, ctx.VerifyKZGProof(c, z, y, proof))
+}
+
+func runVerifyBlobKZGProof(t *testing.T, ctx *Context, v *vector) {
+	blob, ok := decodeBlob(v.Input.Blob)
+	var c Commitment
+	var proof Proof
+	if !ok || !decodeFixed(v.Input.Commitment, c[:]) || !decodeFixed(v.Input.Proof, proof[:]) {
+		require.Nil(t, v.Output)
+		return
+	}
+	expectVerify(t, v, ctx.VerifyBlobKZGProof(blob, c, proof))
+}
+
+func runVerifyBlobKZGProofBatch(t *testing.T, ctx *Context, v *vector) {
+	blobs := make([]Blob, len(v.Input.Blobs))
+	commitments := make([]Commitment, len(v.Input.Commitments))
+	proofs := make([]Proof, len(v.Input.Proofs))
+	for i := range blobs {
+		if !decodeFixed(v.Input.Blobs[i], blobs[i][:]) {
+			require.Nil(t, v.Output)
+			return
+		}
+	}
+	for i := range commitments {
+		if !decodeFixed(v.Input.Commitments[i], commitments[i][:]) {
+			require.Nil(t, v.Output)
+			return
+		}
+	}
+	for i := range proofs {
+		if !decodeFixed(v.Input.Proofs[i], proofs[i][:]) {
+			require.Nil(t, v.Output)
+			return
+		}
+	}
+	expectVerify(t, v, ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package transcript
+
+import (
+	"crypto/sha256"
+	"hash"
+	"math/big"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/mimc"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/stretchr/testify/require"
+)
+
+func randomScalars(t *testing.T, n int) []fr.Element {
+	res := make([]fr.Element, n)
+	for i := range res {
+		_, err := res[i].SetRandom()
+		require.NoError(t, err)
+	}
+	return res
+}
+
+func randomPoints(t *testing.T, n int) []curve.G1Affine {
+	_, _, g, _ := curve.Generators()
+	res := make([]curve.G1Affine, n)
+	for i, s := range randomScalars(t, n) {
+		res[i].ScalarMultiplication(&g, s.BigInt(new(big.Int)))
+	}
+	return res
+}
+
+func TestStreaming(t *testing.T) {
+	scalars := randomScalars(t, 3)
+	points := randomPoints(t, 2)
+
+	for name, newHash := range map[string]func() hash.Hash{"sha256": sha256.New, "mimc": func() hash.Hash { return mimc.NewMiMC() }} {
+		t.Run(name, func(t *testing.T) {
+			assert := require.New(t)
+
+			challenges := func(scalars []fr.Element, points []curve.G1Affine) []fr.Element {
+				ts := NewStreaming(newHash(), "test")
+				assert.NoError(ts.AppendMessage("message", []byte("hello")))
+				assert.NoError(ts.AppendScalar("scalars", scalars...))
+				assert.NoError(ts.AppendPoint("points", points...))
+				alpha, err := ts.ChallengeScalar("alpha")
+				assert.NoError(err)
+				assert.NoError(ts.AppendScalar("alpha", alpha))
+				betas, err := ts.ChallengeScalars("beta", 3)
+				assert.NoError(err)
+				return append([]fr.Element{alpha}, betas...)
+			}
+
+			reference := challenges(scalars, points)
+			assert.Len(reference, 4)
+			assert.Equal(reference, challenges(scalars, points))
+			for i := range reference {
+				for j := i + 1; j < len(reference); j++ {
+					assert.NotEqual(reference[i], reference[j])
+				}
+			}
+
+			// the challenges are bound to all the absorbed elements
+			assert.NotEqual(reference, challenges(scalars[1:], points))
+			assert.NotEqual(reference, challenges(scalars, points[1:]))
+			other := append([]fr.Element{}, scalars...)
+			other[0].SetOne()
+			assert.NotEqual(reference, challenges(other, points))
+		})
+	}
+}
+
+func TestLegacy(t *testing.T) {
+	assert := require.New(t)
+
+	scalars := randomScalars(t, 3)
+	points := randomPoints(t, 2)
+
+	// the transcript as the protocols build it
+	fs := fiatshamir.NewTranscript(sha256.New(), "alpha", "beta")
+	for i := range points {
+		assert.NoError(fs.Bind("alpha", points[i].Marshal()))
+	}
+	for i := range scalars {
+		assert.NoError(fs.Bind("alpha", scalars[i].Marshal()))
+	}
+	assert.NoError(fs.Bind("beta", []byte("hello")))
+	b, err := fs.ComputeChallenge("alpha")
+	assert.NoError(err)
+	var alpha fr.Element
+	alpha.SetBytes(b)
+	b, err = fs.ComputeChallenge("beta")
+	assert.NoError(err)
+	var beta fr.Element
+	beta.SetBytes(b)
+
+	// the same transcript through the adapter
+	var ts Transcript = NewLegacy(fiatshamir.NewTranscript(sha256.New(), "alpha", "beta"))
+	assert.NoError(ts.AppendPoint("alpha", points...))
+	assert.NoError(ts.AppendScalar("alpha", scalars...))
+	assert.NoError(ts.AppendMessage("beta", []byte("hello")))
+	c, err := ts.ChallengeScalar("alpha")
+	assert.NoError(err)
+	assert.Equal(alpha, c)
+	_, err = ts.ChallengeScalars("beta", 2)
+	assert.ErrorIs(err, ErrMultipleChallenges)
+	cs, err := ts.ChallengeScalars("beta", 1)
+	assert.NoError(err)
+	assert.Equal([]fr.Element{beta}, cs)
+
+	// undeclared challenges are rejected
+	_, err = ts.ChallengeScalar("gamma")
+	assert.Error(err)
+}
@@ -0,0 +1,185 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package twistededwards
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// MultiExp computes the multi-scalar multiplication ∑ scalars[i]⋅points[i]
+// and stores the result in p. It implements the bucket method of section 4
+// of https://eprint.iacr.org/2012/549.pdf with signed digits, the windows
+// of the scalars being processed in parallel.
+//
+// This call return an error if len(scalars) != len(points) or if provided config is invalid.
+func (p *PointExtended) MultiExp(points []PointAffine, scalars []fr.Element, config ecc.MultiExpConfig) (*PointExtended, error) {
+	// ensure len(points) == len(scalars)
+	nbPoints := len(points)
+	if nbPoints != len(scalars) {
+		return nil, errors.New("len(points) != len(scalars)")
+	}
+
+	// if nbTasks is not set, use all available CPUs
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU()
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+
+	if nbPoints == 0 {
+		p.setInfinity()
+		return p, nil
+	}
+
+	return p.multiExp(points, scalars, config), nil
+}
+
+// multiExp computes ∑ scalars[i]⋅points[i] with the bucket method. The
+// scalars are in Montgomery form and interpreted as integers.
+func (p *PointExtended) multiExp(points []PointAffine, scalars []fr.Element, config ecc.MultiExpConfig) *PointExtended {
+	nbPoints := len(points)
+
+	// regular form of the scalars, and number of bits to process
+	words := make([]fr.Element, nbPoints)
+	nbBits := 0
+	for i := range scalars {
+		words[i] = scalars[i].Bits()
+		if l := words[i].BitLen(); l > nbBits {
+			nbBits = l
+		}
+	}
+	if nbBits == 0 {
+		return p.setInfinity()
+	}
+
+	// cost = (bits/c + 1) * (nbPoints + 2^{c-1})
+	c, min := 2, math.MaxFloat64
+	for cc := 2; cc <= 16; cc++ {
+		cost := float64(nbBits/cc+1) * float64(nbPoints+(1<<(cc-1)))
+		if cost < min {
+			min, c = cost, cc
+		}
+	}
+	// one more window for the carry of the signed digits
+	nbChunks := (nbBits+c-1)/c + 1
+
+	// digits[k*nbPoints+i] is the k-th signed digit of the i-th scalar, in
+	// [-2^{c-1}, 2^{c-1}[. If the c-bit window w of a scalar is larger than
+	// 2^{c-1}, we borrow 2^c from the next window so that the digit is w-2^c.
+	digits := make([]int32, nbChunks*nbPoints)
+	parallel.Execute(nbPoints, func(start, end int) {
+		for i := start; i < end; i++ {
+			carry := int32(0)
+			for k := 0; k < nbChunks; k++ {
+				d := int32(window(&words[i], k*c, c)) + carry
+				carry = 0
+				if d >= 1<<(c-1) {
+					d -= 1 << c
+					carry = 1
+				}
+				digits[k*nbPoints+i] = d
+			}
+		}
+	}, config.NbTasks)
+
+	// extended coordinates of the points, for the complete addition law
+	extPoints := make([]PointExtended, nbPoints)
+	parallel.Execute(nbPoints, func(start, end int) {
+		for i := start; i < end; i++ {
+			extPoints[i].FromAffine(&points[i])
+		}
+	}, config.NbTasks)
+
+	// each window is processed by its own task
+	chunks := make([]PointExtended, nbChunks)
+	sem := make(chan struct{}, config.NbTasks)
+	var wg sync.WaitGroup
+	for k := 0; k < nbChunks; k++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(k int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			processChunk(&chunks[k], c, extPoints, digits[k*nbPoints:(k+1)*nbPoints])
+		}(k)
+	}
+	wg.Wait()
+
+	// ∑ 2^{c⋅k} chunks[k]
+	var res PointExtended
+	res.Set(&chunks[nbChunks-1])
+	for k := nbChunks - 2; k >= 0; k-- {
+		for j := 0; j < c; j++ {
+			res.Double(&res)
+		}
+		res.Add(&res, &chunks[k])
+	}
+
+	return p.Set(&res)
+}
+
+// processChunk sets res to ∑ digits[i]⋅points[i]: the points are added in
+// 2^{c-1} buckets according to their digits, and the buckets are reduced
+// with a running sum.
+func processChunk(res *PointExtended, c int, points []PointExtended, digits []int32) {
+	buckets := make([]PointExtended, 1<<(c-1))
+	for j := range buckets {
+		buckets[j].setInfinity()
+	}
+
+	var neg PointExtended
+	for i, d := range digits {
+		if d > 0 {
+			buckets[d-1].Add(&buckets[d-1], &points[i])
+		} else if d < 0 {
+			neg.Neg(&points[i])
+			buckets[-d-1].Add(&buckets[-d-1], &neg)
+		}
+	}
+
+	// ∑ (j+1)⋅buckets[j]
+	var runningSum PointExtended
+	runningSum.setInfinity()
+	res.setInfinity()
+	for j := len(buckets) - 1; j >= 0; j-- {
+		runningSum.Add(&runningSum, &buckets[j])
+		res.Add(res, &runningSum)
+	}
+}
+
+// window returns the c bits of the regular form w of a scalar starting at
+// bit offset
+func window(w *fr.Element, offset, c int) uint64 {
+	i, shift := offset/64, uint(offset%64)
+	if i >= len(w) {
+		return 0
+	}
+	res := w[i] >> shift
+	if shift+uint(c) > 64 && i+1 < len(w) {
+		res |= w[i+1] << (64 - shift)
+	}
+	return res & (1<<uint(c) - 1)
+}
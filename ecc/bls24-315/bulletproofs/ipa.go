@@ -0,0 +1,185 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bulletproofs
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// InnerProductProof proves the knowledge of a, b such that
+// P = <a, G> + <b, H> + <a, b>⋅Q, in a logarithmic number of rounds.
+type InnerProductProof struct {
+	// L, R commitments to the cross terms of each round
+	L, R []curve.G1Affine
+
+	// A, B the folded vectors after the last round
+	A, B fr.Element
+}
+
+// proveInnerProduct runs the inner product argument on a, b, for the bases G,
+// H and Q. At each round, with u the challenge,
+//   - a ← u⋅a_lo + u⁻¹⋅a_hi, b ← u⁻¹⋅b_lo + u⋅b_hi,
+//   - G ← u⁻¹⋅G_lo + u⋅G_hi, H ← u⋅H_lo + u⁻¹⋅H_hi,
+//
+// so that P ← P + u²⋅L + u⁻²⋅R.
+// a, b are modified.
+func proveInnerProduct(fs *fiatshamir.Transcript, G, H []curve.G1Affine, Q *curve.G1Affine, a, b []fr.Element) (InnerProductProof, error) {
+	var proof InnerProductProof
+
+	G = append([]curve.G1Affine(nil), G...)
+	H = append([]curve.G1Affine(nil), H...)
+
+	for round := 0; len(a) > 1; round++ {
+		m := len(a) / 2
+		aLo, aHi := a[:m], a[m:]
+		bLo, bHi := b[:m], b[m:]
+		GLo, GHi := G[:m], G[m:]
+		HLo, HHi := H[:m], H[m:]
+
+		// L = <a_lo, G_hi> + <b_hi, H_lo> + <a_lo, b_hi>⋅Q
+		// R = <a_hi, G_lo> + <b_lo, H_hi> + <a_hi, b_lo>⋅Q
+		cL := innerProduct(aLo, bHi)
+		cR := innerProduct(aHi, bLo)
+		L, err := crossTerm(GHi, HLo, Q, aLo, bHi, &cL)
+		if err != nil {
+			return InnerProductProof{}, err
+		}
+		R, err := crossTerm(GLo, HHi, Q, aHi, bLo, &cR)
+		if err != nil {
+			return InnerProductProof{}, err
+		}
+		proof.L = append(proof.L, L)
+		proof.R = append(proof.R, R)
+
+		u, err := deriveChallenge(fs, roundChallengeID(round), pointBytes(&L), pointBytes(&R))
+		if err != nil {
+			return InnerProductProof{}, err
+		}
+		var uInv fr.Element
+		uInv.Inverse(&u)
+
+		var tmp fr.Element
+		for i := 0; i < m; i++ {
+			aLo[i].Mul(&aLo[i], &u)
+			tmp.Mul(&aHi[i], &uInv)
+			aLo[i].Add(&aLo[i], &tmp)
+
+			bLo[i].Mul(&bLo[i], &uInv)
+			tmp.Mul(&bHi[i], &u)
+			bLo[i].Add(&bLo[i], &tmp)
+		}
+		foldPoints(GLo, GHi, &uInv, &u)
+		foldPoints(HLo, HHi, &u, &uInv)
+
+		a, b, G, H = aLo, bLo, GLo, HLo
+	}
+
+	proof.A = a[0]
+	proof.B = b[0]
+
+	return proof, nil
+}
+
+// crossTerm returns <a, G> + <b, H> + c⋅Q
+func crossTerm(G, H []curve.G1Affine, Q *curve.G1Affine, a, b []fr.Element, c *fr.Element) (curve.G1Affine, error) {
+	m := len(a)
+	points := make([]curve.G1Affine, 0, 2*m+1)
+	points = append(points, G...)
+	points = append(points, H...)
+	points = append(points, *Q)
+	scalars := make([]fr.Element, 0, 2*m+1)
+	scalars = append(scalars, a...)
+	scalars = append(scalars, b...)
+	scalars = append(scalars, *c)
+
+	var res curve.G1Affine
+	_, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{})
+	return res, err
+}
+
+// foldPoints sets lo[i] to xLo⋅lo[i] + xHi⋅hi[i]
+func foldPoints(lo, hi []curve.G1Affine, xLo, xHi *fr.Element) {
+	var xLoBigInt, xHiBigInt big.Int
+	xLo.BigInt(&xLoBigInt)
+	xHi.BigInt(&xHiBigInt)
+
+	res := make([]curve.G1Jac, len(lo))
+	parallel.Execute(len(lo), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i].JointScalarMultiplication(&lo[i], &hi[i], &xLoBigInt, &xHiBigInt)
+		}
+	})
+	copy(lo, curve.BatchJacobianToAffineG1(res))
+}
+
+// scalePoints returns the points scalars[i]⋅points[i]
+func scalePoints(points []curve.G1Affine, scalars []fr.Element) []curve.G1Affine {
+	res := make([]curve.G1Jac, len(points))
+	parallel.Execute(len(points), func(start, end int) {
+		var s big.Int
+		for i := start; i < end; i++ {
+			scalars[i].BigInt(&s)
+			res[i].FromAffine(&points[i])
+			res[i].ScalarMultiplication(&res[i], &s)
+		}
+	})
+	return curve.BatchJacobianToAffineG1(res)
+}
+
+// deriveRoundChallenges returns the challenges of the rounds of the inner
+// product argument
+func deriveRoundChallenges(fs *fiatshamir.Transcript, proof *InnerProductProof) ([]fr.Element, error) {
+	u := make([]fr.Element, len(proof.L))
+	for k := range u {
+		var err error
+		if u[k], err = deriveChallenge(fs, roundChallengeID(k), pointBytes(&proof.L[k]), pointBytes(&proof.R[k])); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+// foldingCoefficients returns s such that the bases G folded with the
+// challenges u are <s, G>: sᵢ = ∏ₖ uₖ^{±1}, the exponent being 1 if the bit
+// of i for the round k is set, the first round splitting on the most
+// significant bit.
+func foldingCoefficients(u []fr.Element) []fr.Element {
+	nbRounds := len(u)
+	uInv := fr.BatchInvert(u)
+	s := make([]fr.Element, 1<<nbRounds)
+	s[0].SetOne()
+	for k := 0; k < nbRounds; k++ {
+		s[0].Mul(&s[0], &uInv[k])
+	}
+	for k := 0; k < nbRounds; k++ {
+		// the indices of the entries already computed are multiple of 2ʲ⁺¹,
+		// j = nbRounds-1-k being the bit of round k
+		var uSquare fr.Element
+		uSquare.Square(&u[k])
+		bit := 1 << (nbRounds - 1 - k)
+		for i := 0; i < len(s); i += 2 * bit {
+			s[i+bit].Mul(&s[i], &uSquare)
+		}
+	}
+	return s
+}
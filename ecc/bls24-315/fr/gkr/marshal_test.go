@@ -0,0 +1,74 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package gkr
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/polynomial"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/sumcheck"
+)
+
+func TestProofSerialization(t *testing.T) {
+
+	proof := make(Proof, 3)
+	for i := range proof {
+		proof[i].PartialSumPolys = make([]polynomial.Polynomial, i+1)
+		for j := range proof[i].PartialSumPolys {
+			proof[i].PartialSumPolys[j] = make(polynomial.Polynomial, 3)
+			for k := range proof[i].PartialSumPolys[j] {
+				proof[i].PartialSumPolys[j][k].SetRandom()
+			}
+		}
+		finalEvalProof := make([]fr.Element, i)
+		for k := range finalEvalProof {
+			finalEvalProof[k].SetRandom()
+		}
+		proof[i].FinalEvalProof = finalEvalProof
+	}
+	proof = append(proof, sumcheck.Proof{})
+
+	var buf bytes.Buffer
+	written, err := proof.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != int64(buf.Len()) {
+		t.Fatal("wrong number of bytes written")
+	}
+
+	var decoded Proof
+	read, err := decoded.ReadFrom(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != written {
+		t.Fatal("wrong number of bytes read")
+	}
+	if !reflect.DeepEqual(proof, decoded) {
+		t.Fatal("decoded proof does not match")
+	}
+
+	b := buf.Bytes()
+	b[0]++
+	if _, err := decoded.ReadFrom(bytes.NewReader(b)); err != ErrSerializationVersion {
+		t.Fatal("expected ErrSerializationVersion")
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+
+	"github.com/consensys/gnark-crypto/utils/testutils"
+)
+
+// contributedSRS returns a SRS of the given size updated by nbContributions
+// participants, and the proofs of the updates
+func contributedSRS(t *testing.T, size uint64, nbContributions int) (*SRS, []UpdateProof) {
+	srs, err := NewSRS(size, big.NewInt(1))
+	require.NoError(t, err)
+	proofs := make([]UpdateProof, nbContributions)
+	for i := range proofs {
+		proofs[i], err = Contribute(srs)
+		require.NoError(t, err)
+	}
+	return srs, proofs
+}
+
+func TestContributions(t *testing.T) {
+	assert := require.New(t)
+
+	srs, proofs := contributedSRS(t, 32, 3)
+	_, _, g1, _ := bls24315.Generators()
+
+	// verify correct contributions
+	assert.NoError(VerifyContributions(srs, g1, proofs))
+	assert.NoError(VerifyContributions(srs, proofs[0].RunningProduct, proofs[1:]))
+	t.Run("serialization", testutils.SerializationRoundTrip(&proofs[0]))
+
+	// the updated SRS can be used to commit and open
+	f := randomPolynomial(20)
+	digest, err := Commit(f, srs.Pk)
+	assert.NoError(err)
+	var point fr.Element
+	point.SetRandom()
+	proof, err := Open(f, point, srs.Pk)
+	assert.NoError(err)
+	assert.NoError(Verify(&digest, &proof, point, srs.Vk))
+
+	// missing or reordered contributions
+	assert.ErrorIs(VerifyContributions(srs, g1, proofs[1:]), ErrInvalidUpdateProof)
+	assert.ErrorIs(VerifyContributions(srs, g1, proofs[:2]), ErrInvalidContribution)
+	assert.ErrorIs(VerifyContributions(srs, g1, nil), ErrInvalidContribution)
+	assert.ErrorIs(VerifyContributions(srs, g1, []UpdateProof{proofs[1], proofs[0], proofs[2]}), ErrInvalidUpdateProof)
+
+	// wrong proof of knowledge
+	tampered := make([]UpdateProof, len(proofs))
+	copy(tampered, proofs)
+	tampered[1].S.Double(&tampered[1].S)
+	assert.ErrorIs(VerifyContributions(srs, g1, tampered), ErrInvalidUpdateProof)
+
+	// public keys inconsistent with the running products
+	runningProducts := []bls24315.G1Affine{g1, proofs[0].RunningProduct, proofs[1].RunningProduct}
+	assert.NoError(verifyRunningProducts(runningProducts, []bls24315.G2Affine{proofs[0].PotPubkey, proofs[1].PotPubkey}))
+	assert.ErrorIs(verifyRunningProducts(runningProducts, []bls24315.G2Affine{proofs[1].PotPubkey, proofs[0].PotPubkey}), ErrInvalidContribution)
+
+	// SRS not made of powers of the same secret
+	srs.Pk.G1[5] = srs.Pk.G1[6]
+	assert.ErrorIs(VerifyContributions(srs, g1, proofs), ErrInvalidSRS)
+}
+
+func TestVerifySRS(t *testing.T) {
+	assert := require.New(t)
+
+	assert.NoError(VerifySRS(testSrs))
+
+	srs, err := NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+
+	// wrong power of τ in G₂
+	srs.Vk.G2[1].Double(&srs.Vk.G2[1])
+	srs.Vk.Lines[1] = bls24315.PrecomputeLines(srs.Vk.G2[1])
+	assert.ErrorIs(VerifySRS(srs), ErrInvalidSRS)
+
+	// lines inconsistent with [τ]G₂
+	srs, err = NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+	srs.Vk.Lines[1] = srs.Vk.Lines[0]
+	assert.ErrorIs(VerifySRS(srs), ErrInvalidSRS)
+
+	// wrong generator
+	srs, err = NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+	srs.Pk.G1[0] = srs.Pk.G1[1]
+	assert.ErrorIs(VerifySRS(srs), ErrInvalidSRS)
+
+	// wrong last power
+	srs, err = NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+	srs.Pk.G1[15].Double(&srs.Pk.G1[15])
+	assert.ErrorIs(VerifySRS(srs), ErrInvalidSRS)
+}
+
+func TestValidate(t *testing.T) {
+	assert := require.New(t)
+
+	assert.NoError(testSrs.Validate())
+	assert.NoError(testSrs.ValidateParallel(4))
+	assert.NoError(testSrs.ValidateParallel(0))
+
+	// a valid encoding of powers of different secrets is only rejected by Validate
+	srs, err := NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+	other, err := NewSRS(16, big.NewInt(43))
+	assert.NoError(err)
+	srs.Pk.G1[7] = other.Pk.G1[7]
+	var buf bytes.Buffer
+	_, err = srs.WriteTo(&buf)
+	assert.NoError(err)
+	var read SRS
+	_, err = read.ReadFrom(&buf)
+	assert.NoError(err)
+	assert.ErrorIs(read.Validate(), ErrInvalidSRS)
+	assert.ErrorIs(read.ValidateParallel(4), ErrInvalidSRS)
+
+	// points not on the curve
+	srs, err = NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+	srs.Pk.G1[3].Y.Double(&srs.Pk.G1[3].Y)
+	assert.ErrorIs(srs.Validate(), ErrInvalidSRSPoint)
+	srs.Pk.G1[3] = other.Pk.G1[3]
+	srs.Vk.G2[1].Y.Double(&srs.Vk.G2[1].Y)
+	assert.ErrorIs(srs.ValidateParallel(2), ErrInvalidSRSPoint)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/transcript"
 	"github.com/consensys/gnark-crypto/fiat-shamir"
 
 	"github.com/consensys/gnark-crypto/internal/parallel"
@@ -255,6 +256,17 @@ func Verify(commitment *Digest, proof *OpeningProof, point fr.Element, vk Verify
 // * polynomials is the list of polynomials to open, they are supposed to be of the same size.
 // * dataTranscript extra data that might be needed to derive the challenge used for folding
 func BatchOpenSinglePoint(polynomials [][]fr.Element, digests []Digest, point fr.Element, hf hash.Hash, pk ProvingKey, dataTranscript ...[]byte) (BatchOpeningProof, error) {
+	return batchOpenSinglePoint(polynomials, digests, point, newGammaTranscript(hf), pk, dataTranscript...)
+}
+
+// BatchOpenSinglePointWithTranscript is BatchOpenSinglePoint, the challenge γ being
+// derived from fs (Merlin, streaming, ...) under the label "gamma". With a transcript
+// whose challenges are declared up front, "gamma" must be one of them.
+func BatchOpenSinglePointWithTranscript(polynomials [][]fr.Element, digests []Digest, point fr.Element, fs transcript.Transcript, pk ProvingKey) (BatchOpeningProof, error) {
+	return batchOpenSinglePoint(polynomials, digests, point, fs, pk)
+}
+
+func batchOpenSinglePoint(polynomials [][]fr.Element, digests []Digest, point fr.Element, fs transcript.Transcript, pk ProvingKey, dataTranscript ...[]byte) (BatchOpeningProof, error) {
 
 	// check for invalid sizes
 	nbDigests := len(digests)
@@ -290,7 +302,7 @@ func BatchOpenSinglePoint(polynomials [][]fr.Element, digests []Digest, point fr
 	wg.Wait()
 
 	// derive the challenge γ, binded to the point and the commitments
-	gamma, err := deriveGamma(point, digests, res.ClaimedValues, hf, dataTranscript...)
+	gamma, err := deriveGamma(fs, point, digests, res.ClaimedValues, dataTranscript...)
 	if err != nil {
 		return BatchOpeningProof{}, err
 	}
@@ -350,6 +362,16 @@ func BatchOpenSinglePoint(polynomials [][]fr.Element, digests []Digest, point fr
 // * transcript extra data needed to derive the challenge used for folding.
 // * returns the folded version of batchOpeningProof, Digest, the folded version of digests
 func FoldProof(digests []Digest, batchOpeningProof *BatchOpeningProof, point fr.Element, hf hash.Hash, dataTranscript ...[]byte) (OpeningProof, Digest, error) {
+	return foldProof(digests, batchOpeningProof, point, newGammaTranscript(hf), dataTranscript...)
+}
+
+// FoldProofWithTranscript is FoldProof, the challenge γ being derived from fs, as in
+// BatchOpenSinglePointWithTranscript.
+func FoldProofWithTranscript(digests []Digest, batchOpeningProof *BatchOpeningProof, point fr.Element, fs transcript.Transcript) (OpeningProof, Digest, error) {
+	return foldProof(digests, batchOpeningProof, point, fs)
+}
+
+func foldProof(digests []Digest, batchOpeningProof *BatchOpeningProof, point fr.Element, fs transcript.Transcript, dataTranscript ...[]byte) (OpeningProof, Digest, error) {
 
 	nbDigests := len(digests)
 
@@ -359,7 +381,7 @@ func FoldProof(digests []Digest, batchOpeningProof *BatchOpeningProof, point fr.
 	}
 
 	// derive the challenge γ, binded to the point and the commitments
-	gamma, err := deriveGamma(point, digests, batchOpeningProof.ClaimedValues, hf, dataTranscript...)
+	gamma, err := deriveGamma(fs, point, digests, batchOpeningProof.ClaimedValues, dataTranscript...)
 	if err != nil {
 		return OpeningProof{}, Digest{}, ErrInvalidNbDigests
 	}
@@ -407,6 +429,20 @@ func BatchVerifySinglePoint(digests []Digest, batchOpeningProof *BatchOpeningPro
 
 }
 
+// BatchVerifySinglePointWithTranscript verifies a batched opening proof created by
+// BatchOpenSinglePointWithTranscript, fs being in the same state as the prover's.
+func BatchVerifySinglePointWithTranscript(digests []Digest, batchOpeningProof *BatchOpeningProof, point fr.Element, fs transcript.Transcript, vk VerifyingKey) error {
+
+	// fold the proof
+	foldedProof, foldedDigest, err := FoldProofWithTranscript(digests, batchOpeningProof, point, fs)
+	if err != nil {
+		return err
+	}
+
+	// verify the foldedProof against the foldedDigest
+	return Verify(&foldedDigest, &foldedProof, point, vk)
+}
+
 // BatchVerifyMultiPoints batch verifies a list of opening proofs at different points.
 // The purpose of the batching is to have only one pairing for verifying several proofs.
 //
@@ -539,38 +575,32 @@ func fold(di []Digest, fai []fr.Element, ci []fr.Element) (Digest, fr.Element, e
 }
 
 // deriveGamma derives a challenge using Fiat Shamir to fold proofs.
-func deriveGamma(point fr.Element, digests []Digest, claimedValues []fr.Element, hf hash.Hash, dataTranscript ...[]byte) (fr.Element, error) {
+func deriveGamma(fs transcript.Transcript, point fr.Element, digests []Digest, claimedValues []fr.Element, dataTranscript ...[]byte) (fr.Element, error) {
 
 	// derive the challenge gamma, binded to the point and the commitments
-	fs := fiatshamir.NewTranscript(hf, "gamma")
-	if err := fs.Bind("gamma", point.Marshal()); err != nil {
+	if err := fs.AppendScalar("gamma", point); err != nil {
 		return fr.Element{}, err
 	}
-	for i := range digests {
-		if err := fs.Bind("gamma", digests[i].Marshal()); err != nil {
-			return fr.Element{}, err
-		}
+	if err := fs.AppendPoint("gamma", digests...); err != nil {
+		return fr.Element{}, err
 	}
-	for i := range claimedValues {
-		if err := fs.Bind("gamma", claimedValues[i].Marshal()); err != nil {
-			return fr.Element{}, err
-		}
+	if err := fs.AppendScalar("gamma", claimedValues...); err != nil {
+		return fr.Element{}, err
 	}
 
 	for i := 0; i < len(dataTranscript); i++ {
-		if err := fs.Bind("gamma", dataTranscript[i]); err != nil {
+		if err := fs.AppendMessage("gamma", dataTranscript[i]); err != nil {
 			return fr.Element{}, err
 		}
 	}
 
-	gammaByte, err := fs.ComputeChallenge("gamma")
-	if err != nil {
-		return fr.Element{}, err
-	}
-	var gamma fr.Element
-	gamma.SetBytes(gammaByte)
+	return fs.ChallengeScalar("gamma")
+}
 
-	return gamma, nil
+// newGammaTranscript returns the transcript deriving γ from hf in BatchOpenSinglePoint
+// and FoldProof
+func newGammaTranscript(hf hash.Hash) transcript.Transcript {
+	return transcript.NewLegacy(fiatshamir.NewTranscript(hf, "gamma"))
 }
 
 // dividePolyByXminusA computes (f-f(a))/(x-a), in canonical basis, in regular form
@@ -0,0 +1,182 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fft"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidDomainSize  = errors.New("the domain size must be a power of 2, at most the size of the SRS")
+	ErrInvalidLagrangeKey = errors.New("invalid Lagrange proving key")
+)
+
+// ProvingKeyLagrange used to commit to and open polynomials given by their
+// evaluations on a domain {1, ω, ..., ωⁿ⁻¹} of size n, without converting them
+// to canonical form.
+//
+// implements io.ReaderFrom and io.WriterTo
+type ProvingKeyLagrange struct {
+	// G1 [L₀(α)]G₁, ..., [Lₙ₋₁(α)]G₁ where Lᵢ is the i-th Lagrange polynomial of
+	// the domain, Lᵢ(ωʲ) = δᵢⱼ
+	G1 []curve.G1Affine
+
+	// BitReversed is true if G1 is in bit reversed order, as the output of a DIF FFT
+	BitReversed bool
+}
+
+// NewProvingKeyLagrange returns the Lagrange form of pk on domain, in natural or
+// bit reversed order. Only the cardinality of the domain is used: the
+// evaluations are on the subgroup of size n, not on a coset.
+func NewProvingKeyLagrange(pk ProvingKey, domain *fft.Domain, bitReversed bool) (ProvingKeyLagrange, error) {
+	n := domain.Cardinality
+	if n < 2 || n > uint64(len(pk.G1)) || n != ecc.NextPowerOfTwo(n) {
+		return ProvingKeyLagrange{}, ErrInvalidDomainSize
+	}
+	g1, err := ToLagrangeG1(pk.G1[:n])
+	if err != nil {
+		return ProvingKeyLagrange{}, err
+	}
+	if bitReversed {
+		bitReverse(g1)
+	}
+	return ProvingKeyLagrange{G1: g1, BitReversed: bitReversed}, nil
+}
+
+// CommitLagrange commits to a polynomial given by its evaluations on the domain
+// of pk, in the order of pk. The commitment is the same as Commit on its
+// canonical form, so the openings can be verified with Verify.
+func CommitLagrange(p []fr.Element, pk ProvingKeyLagrange, nbTasks ...int) (Digest, error) {
+
+	if len(p) != len(pk.G1) {
+		return Digest{}, ErrInvalidPolynomialSize
+	}
+
+	var res curve.G1Affine
+
+	config := ecc.MultiExpConfig{}
+	if len(nbTasks) > 0 {
+		config.NbTasks = nbTasks[0]
+	}
+	if _, err := res.MultiExp(pk.G1, p, config); err != nil {
+		return Digest{}, err
+	}
+
+	return res, nil
+}
+
+// OpenLagrange computes an opening proof at point of the polynomial given by
+// its evaluations p on the domain of pk, in the order of pk. The evaluation at
+// point and the quotient (f - f(point))/(X - point) are computed in evaluation
+// form with barycentric formulas, in O(n) field operations.
+func OpenLagrange(p []fr.Element, point fr.Element, pk ProvingKeyLagrange) (OpeningProof, error) {
+
+	n := len(p)
+	if n != len(pk.G1) || n < 2 {
+		return OpeningProof{}, ErrInvalidPolynomialSize
+	}
+	roots, err := domainRoots(uint64(n), pk.BitReversed)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+
+	// index of point in the domain, or -1
+	inDomain := -1
+	var pointN fr.Element
+	pointN.Exp(point, big.NewInt(int64(n)))
+	if pointN.IsOne() {
+		for i := range roots {
+			if roots[i].Equal(&point) {
+				inDomain = i
+				break
+			}
+		}
+	}
+
+	// 1/(z - ωᵢ), and 0 for ωᵢ = z
+	inv := make([]fr.Element, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			inv[i].Sub(&point, &roots[i])
+		}
+	})
+	inv = fr.BatchInvert(inv)
+
+	var res OpeningProof
+	if inDomain != -1 {
+		res.ClaimedValue = p[inDomain]
+	} else {
+		// f(z) = (zⁿ - 1)/n ∑ᵢ fᵢωᵢ/(z - ωᵢ)
+		var t, nInv fr.Element
+		for i := range p {
+			t.Mul(&p[i], &roots[i]).Mul(&t, &inv[i])
+			res.ClaimedValue.Add(&res.ClaimedValue, &t)
+		}
+		nInv.SetUint64(uint64(n)).Inverse(&nInv)
+		pointN.Sub(&pointN, new(fr.Element).SetOne()).Mul(&pointN, &nInv)
+		res.ClaimedValue.Mul(&res.ClaimedValue, &pointN)
+	}
+
+	// qᵢ = (fᵢ - f(z))/(ωᵢ - z)
+	q := make([]fr.Element, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			q[i].Sub(&res.ClaimedValue, &p[i]).Mul(&q[i], &inv[i])
+		}
+	})
+
+	// q(z) = ∑_{ωᵢ≠z} (fᵢ - f(z))ωᵢ/(z(z - ωᵢ)) when z = ωₖ is in the domain
+	if inDomain != -1 {
+		var t, pointInv fr.Element
+		q[inDomain].SetZero()
+		for i := range p {
+			if i == inDomain {
+				continue
+			}
+			t.Sub(&p[i], &res.ClaimedValue).Mul(&t, &roots[i]).Mul(&t, &inv[i])
+			q[inDomain].Add(&q[inDomain], &t)
+		}
+		pointInv.Inverse(&point)
+		q[inDomain].Mul(&q[inDomain], &pointInv)
+	}
+
+	if res.H, err = CommitLagrange(q, pk); err != nil {
+		return OpeningProof{}, err
+	}
+	return res, nil
+}
+
+// domainRoots returns the n-th roots of unity ωⁱ, in natural or bit reversed order
+func domainRoots(n uint64, bitReversed bool) ([]fr.Element, error) {
+	generator, err := fr.Generator(n)
+	if err != nil {
+		return nil, err
+	}
+	roots := make([]fr.Element, n)
+	fft.BuildExpTable(generator, roots)
+	if bitReversed {
+		fft.BitReverse(roots)
+	}
+	return roots, nil
+}
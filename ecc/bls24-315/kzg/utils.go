@@ -39,9 +39,6 @@ func ToLagrangeG1(coeffs []curve.G1Affine) ([]curve.G1Affine, error) {
 	}
 	size := len(coeffs)
 
-	numCPU := uint64(runtime.NumCPU())
-	maxSplits := bits.TrailingZeros64(ecc.NextPowerOfTwo(numCPU)) << 1
-
 	twiddlesInv, err := computeTwiddlesInv(size)
 	if err != nil {
 		return nil, err
@@ -53,7 +50,7 @@ func ToLagrangeG1(coeffs []curve.G1Affine) ([]curve.G1Affine, error) {
 		jCoeffs[i].FromAffine(&coeffs[i])
 	}
 
-	difFFTG1(jCoeffs, twiddlesInv, 0, maxSplits, nil)
+	fftG1(jCoeffs, twiddlesInv)
 
 	// TODO @gbotrel generify the cobra bitreverse function, benchmark it and use it everywhere
 	bitReverse(jCoeffs)
@@ -74,6 +71,15 @@ func ToLagrangeG1(coeffs []curve.G1Affine) ([]curve.G1Affine, error) {
 	return curve.BatchJacobianToAffineG1(jCoeffs), nil
 }
 
+// fftG1 computes the FFT of a with the given twiddles, the input being in
+// natural order and the output in bit reversed order
+func fftG1(a []curve.G1Jac, twiddles []*big.Int) {
+	numCPU := uint64(runtime.NumCPU())
+	maxSplits := bits.TrailingZeros64(ecc.NextPowerOfTwo(numCPU)) << 1
+
+	difFFTG1(a, twiddles, 0, maxSplits, nil)
+}
+
 func computeTwiddlesInv(cardinality int) ([]*big.Int, error) {
 	generator, err := fr.Generator(uint64(cardinality))
 	if err != nil {
@@ -83,6 +89,21 @@ func computeTwiddlesInv(cardinality int) ([]*big.Int, error) {
 	// inverse the generator
 	generator.Inverse(&generator)
 
+	return twiddlesFromGenerator(generator, cardinality), nil
+}
+
+func computeTwiddles(cardinality int) ([]*big.Int, error) {
+	generator, err := fr.Generator(uint64(cardinality))
+	if err != nil {
+		return nil, err
+	}
+
+	return twiddlesFromGenerator(generator, cardinality), nil
+}
+
+// twiddlesFromGenerator returns the powers of generator used by difFFTG1
+func twiddlesFromGenerator(generator fr.Element, cardinality int) []*big.Int {
+
 	// nb fft stages
 	nbStages := uint64(bits.TrailingZeros64(uint64(cardinality)))
 
@@ -91,7 +112,7 @@ func computeTwiddlesInv(cardinality int) ([]*big.Int, error) {
 	w := generator
 	r[0] = new(big.Int).SetUint64(1)
 	if len(r) == 1 {
-		return r, nil
+		return r
 	}
 	r[1] = new(big.Int)
 	w.BigInt(r[1])
@@ -101,7 +122,7 @@ func computeTwiddlesInv(cardinality int) ([]*big.Int, error) {
 		w.BigInt(r[j])
 	}
 
-	return r, nil
+	return r
 }
 
 func bitReverse[T any](a []T) {
@@ -0,0 +1,31 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package transcript provides Fiat-Shamir transcripts absorbing scalars of fr and
+// points of G1, and squeezing challenges in fr.
+//
+// Two implementations of the Transcript interface are provided:
+//   - Streaming, a sponge-like transcript on top of fiatshamir.StreamingTranscript,
+//     whose challenges need not be declared up front. The challenges are derived
+//     from fr.Bytes+16 bytes, so that their bias after reduction modulo r is
+//     negligible (< 2⁻¹²⁸).
+//   - Legacy, an adapter for fiatshamir.Transcript, which binds each element with
+//     its Marshal encoding and sets the challenges from the digests with SetBytes,
+//     as the existing protocols do. The transcripts are unchanged, bit-for-bit.
+//
+// Both work with byte oriented hash functions (SHA-256, Keccak, ...) and with
+// algebraic ones (MiMC, ...).
+package transcript
@@ -16,6 +16,9 @@
 
 // Package eddsa provides EdDSA signature scheme on bls24-315's twisted edwards curve.
 //
+// Signatures can be verified one by one with PublicKey.Verify, or in batch with
+// BatchVerify, which reports the indices of the invalid signatures.
+//
 // # See also
 //
 // https://en.wikipedia.org/wiki/EdDSA
@@ -0,0 +1,187 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bulletproofs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func randomBlindings(t testing.TB, size int) []fr.Element {
+	res := make([]fr.Element, size)
+	for i := range res {
+		_, err := res[i].SetRandom()
+		require.NoError(t, err)
+	}
+	return res
+}
+
+func TestRangeProof(t *testing.T) {
+	assert := require.New(t)
+
+	pp, err := Setup(64, 4)
+	assert.NoError(err)
+
+	for _, values := range [][]uint64{
+		{0},
+		{math.MaxUint64},
+		{42, 1 << 63},
+		{1, 2, 3, math.MaxUint64},
+	} {
+		blindings := randomBlindings(t, len(values))
+		proof, commitments, err := Prove(&pp, values, blindings, sha256.New())
+		assert.NoError(err)
+		for j := range values {
+			expected := pp.Commit(values[j], blindings[j])
+			assert.True(expected.Equal(&commitments[j]))
+		}
+		assert.NoError(Verify(&pp, &proof, commitments, sha256.New()))
+
+		// commitment to another value
+		wrongCommitments := append([]curve.G1Affine(nil), commitments...)
+		wrongCommitments[0] = pp.Commit(values[0]^1, blindings[0])
+		assert.ErrorIs(Verify(&pp, &proof, wrongCommitments, sha256.New()), ErrVerifyRangeProof)
+
+		// tampered proof
+		wrongProof := proof
+		wrongProof.THat.SetOne()
+		assert.ErrorIs(Verify(&pp, &wrongProof, commitments, sha256.New()), ErrVerifyRangeProof)
+		wrongProof = proof
+		wrongProof.IPA.A.Double(&proof.IPA.A)
+		assert.ErrorIs(Verify(&pp, &wrongProof, commitments, sha256.New()), ErrVerifyRangeProof)
+	}
+}
+
+func TestRangeProofErrors(t *testing.T) {
+	assert := require.New(t)
+
+	_, err := Setup(12, 1)
+	assert.ErrorIs(err, ErrInvalidNbBits)
+	_, err = Setup(8, 3)
+	assert.ErrorIs(err, ErrInvalidNbValues)
+
+	pp, err := Setup(8, 2)
+	assert.NoError(err)
+
+	_, _, err = Prove(&pp, []uint64{256}, randomBlindings(t, 1), sha256.New())
+	assert.ErrorIs(err, ErrValueOutOfRange)
+	_, _, err = Prove(&pp, []uint64{1, 2, 3}, randomBlindings(t, 3), sha256.New())
+	assert.ErrorIs(err, ErrInvalidNbValues)
+	_, _, err = Prove(&pp, []uint64{1, 2, 3, 4}, randomBlindings(t, 4), sha256.New())
+	assert.ErrorIs(err, ErrInvalidNbValues)
+	_, _, err = Prove(&pp, []uint64{1, 2}, randomBlindings(t, 1), sha256.New())
+	assert.ErrorIs(err, ErrLengthMismatch)
+
+	// a proof for 8 bits does not verify against 16 bits parameters
+	proof, commitments, err := Prove(&pp, []uint64{255, 0}, randomBlindings(t, 2), sha256.New())
+	assert.NoError(err)
+	assert.NoError(Verify(&pp, &proof, commitments, sha256.New()))
+	pp16, err := Setup(16, 2)
+	assert.NoError(err)
+	assert.Error(Verify(&pp16, &proof, commitments, sha256.New()))
+
+	// the number of commitments must match the proof
+	assert.Error(Verify(&pp, &proof, commitments[:1], sha256.New()))
+}
+
+func TestSetupDeterministic(t *testing.T) {
+	assert := require.New(t)
+
+	pp1, err := Setup(8, 2)
+	assert.NoError(err)
+	pp2, err := Setup(8, 4)
+	assert.NoError(err)
+
+	// parameters for more values extend the ones for less values
+	assert.True(pp1.G.Equal(&pp2.G))
+	assert.True(pp1.H.Equal(&pp2.H))
+	assert.Equal(pp1.Gs, pp2.Gs[:len(pp1.Gs)])
+	assert.Equal(pp1.Hs, pp2.Hs[:len(pp1.Hs)])
+	assert.False(pp1.G.Equal(&pp1.H))
+}
+
+func TestSerialization(t *testing.T) {
+	assert := require.New(t)
+
+	pp, err := Setup(32, 2)
+	assert.NoError(err)
+
+	proof, commitments, err := Prove(&pp, []uint64{7, 1 << 31}, randomBlindings(t, 2), sha256.New())
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	written, err := proof.WriteTo(&buf)
+	assert.NoError(err)
+	assert.Equal(int64(buf.Len()), written)
+
+	var decoded Proof
+	read, err := decoded.ReadFrom(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Equal(written, read)
+	assert.Equal(proof, decoded)
+	assert.NoError(Verify(&pp, &decoded, commitments, sha256.New()))
+
+	_, err = decoded.ReadFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	assert.Error(err)
+}
+
+func benchmarkProve(b *testing.B, nbValues int) {
+	pp, err := Setup(64, nbValues)
+	require.NoError(b, err)
+	values := make([]uint64, nbValues)
+	for i := range values {
+		values[i] = uint64(i) * 0x9e3779b97f4a7c15
+	}
+	blindings := randomBlindings(b, nbValues)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = Prove(&pp, values, blindings, sha256.New())
+	}
+}
+
+func benchmarkVerify(b *testing.B, nbValues int) {
+	pp, err := Setup(64, nbValues)
+	require.NoError(b, err)
+	values := make([]uint64, nbValues)
+	for i := range values {
+		values[i] = uint64(i) * 0x9e3779b97f4a7c15
+	}
+	proof, commitments, err := Prove(&pp, values, randomBlindings(b, nbValues), sha256.New())
+	require.NoError(b, err)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Verify(&pp, &proof, commitments, sha256.New())
+	}
+}
+
+func BenchmarkProve(b *testing.B) {
+	b.Run("1", func(b *testing.B) { benchmarkProve(b, 1) })
+	b.Run("8", func(b *testing.B) { benchmarkProve(b, 8) })
+}
+
+func BenchmarkVerify(b *testing.B) {
+	b.Run("1", func(b *testing.B) { benchmarkVerify(b, 1) })
+	b.Run("8", func(b *testing.B) { benchmarkVerify(b, 8) })
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fri
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidParameters is returned by New when the options are not consistent
+var ErrInvalidParameters = errors.New("invalid FRI parameters")
+
+const (
+	defaultFoldingFactor = 2
+
+	// maxGrindingBits bounds the work of the prover for the proof of work
+	maxGrindingBits = 32
+
+	// targetSecurity is the conjectured security, in bits, reached by default by
+	// choosing the number of queries
+	targetSecurity = 128
+)
+
+// Option customizes a FRI instance built with New
+type Option func(*friConfig) error
+
+type friConfig struct {
+	blowup          int
+	foldingFactor   int
+	nbQueries       int
+	grindingBits    int
+	finalPolyDegree int
+}
+
+// WithBlowup sets the blowup factor ρ⁻¹ = size_code_word/size_polynomial, a power
+// of 2 ≥ 2. Defaults to 8.
+func WithBlowup(blowup int) Option {
+	return func(cfg *friConfig) error {
+		if blowup < 2 || blowup&(blowup-1) != 0 {
+			return fmt.Errorf("%w: the blowup factor must be a power of 2 ≥ 2", ErrInvalidParameters)
+		}
+		cfg.blowup = blowup
+		return nil
+	}
+}
+
+// WithFoldingFactor sets the arity of the folding, 2, 4, 8 or 16: at each round,
+// the degree of the polynomial is divided by the folding factor. Defaults to 2.
+func WithFoldingFactor(k int) Option {
+	return func(cfg *friConfig) error {
+		if k != 2 && k != 4 && k != 8 && k != 16 {
+			return fmt.Errorf("%w: the folding factor must be 2, 4, 8 or 16", ErrInvalidParameters)
+		}
+		cfg.foldingFactor = k
+		return nil
+	}
+}
+
+// WithQueries sets the number of queries of the verifier. Defaults to the
+// number of queries needed for 128 bits of conjectured security.
+func WithQueries(nbQueries int) Option {
+	return func(cfg *friConfig) error {
+		if nbQueries < 1 {
+			return fmt.Errorf("%w: at least one query is needed", ErrInvalidParameters)
+		}
+		cfg.nbQueries = nbQueries
+		return nil
+	}
+}
+
+// WithGrinding sets the number of bits of the proof of work the prover solves
+// before deriving the queries, at most 32. Each bit of grinding adds one bit of
+// security. Defaults to 0.
+func WithGrinding(bits int) Option {
+	return func(cfg *friConfig) error {
+		if bits < 0 || bits > maxGrindingBits {
+			return fmt.Errorf("%w: the grinding must be between 0 and %d bits", ErrInvalidParameters, maxGrindingBits)
+		}
+		cfg.grindingBits = bits
+		return nil
+	}
+}
+
+// WithFinalPolyDegree stops the folding once the degree of the folded polynomial
+// is at most d, the coefficients of the final polynomial being sent in the
+// proof. Only the largest power of 2 ≤ d+1 is relevant. Defaults to 0, that is
+// the polynomial is folded down to a constant. New returns ErrInvalidParameters
+// if the folding factor does not allow to reach this degree, e.g. folding by 4
+// polynomials of size 8 down to a constant.
+func WithFinalPolyDegree(d int) Option {
+	return func(cfg *friConfig) error {
+		if d < 0 {
+			return fmt.Errorf("%w: the degree of the final polynomial must be non negative", ErrInvalidParameters)
+		}
+		cfg.finalPolyDegree = d
+		return nil
+	}
+}
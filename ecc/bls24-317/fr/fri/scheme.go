@@ -0,0 +1,597 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fri
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"math"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/fft"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrProofShape = errors.New("the proof does not match the parameters of the scheme")
+	ErrGrinding   = errors.New("the proof of work of the prover is wrong")
+	ErrFinalPoly  = errors.New("the final polynomial does not match the last folding")
+	ErrPolySize   = errors.New("the polynomial has more coefficients than the size of the scheme")
+)
+
+const (
+	// johnsonProximity is the parameter m of the Johnson bound used to
+	// estimate the proven soundness, see [BCIKS20] and [ethSTARK]
+	johnsonProximity = 3
+)
+
+// Scheme is a FRI instance whose blowup, folding factor, number of queries,
+// grinding and final polynomial are chosen with options, see New.
+//
+// The codeword of a polynomial p of degree < n is its evaluation on the
+// subgroup D₀ of size N = blowup*n. At round i, the evaluation of the folded
+// polynomial pᵢ on Dᵢ (|Dᵢ| = N/kⁱ, k the folding factor) is committed with a
+// Merkle tree whose j-th leaf is the fiber {pᵢ(ωᵢʲ⁺ᵗᴺⁱᐟᵏ), t < k} of ωᵢʲᵏ under
+// x -> xᵏ. Writing pᵢ(X) = ∑ₜ Xᵗpᵢ,ₜ(Xᵏ), the next polynomial is
+// pᵢ₊₁ = ∑ₜ αᵢᵗpᵢ,ₜ where αᵢ is derived with Fiat Shamir. The folding stops
+// once the polynomial is small enough, its coefficients being sent in clear.
+type Scheme struct {
+
+	// hash function that is used for Fiat Shamir and for committing to
+	// the oracles.
+	h hash.Hash
+
+	// domain used to build the Reed Solomon code from the given polynomial.
+	// The size of the domain is blowup*size.
+	domain *fft.Domain
+
+	// size of the polynomials (power of 2)
+	size uint64
+
+	blowup        int
+	foldingFactor int
+	nbQueries     int
+	grindingBits  int
+
+	// nbFoldings number of foldings, each folded polynomial but the last one
+	// is committed
+	nbFoldings int
+
+	// finalSize number of coefficients of the final polynomial
+	finalSize int
+
+	// zetaInv[t] = ζ⁻ᵗ where ζ is a primitive k-th root of unity
+	zetaInv []fr.Element
+
+	// foldingFactorInv k⁻¹
+	foldingFactorInv fr.Element
+}
+
+// Proof is a proof of proximity built by a Scheme.
+type Proof struct {
+
+	// Commitments Merkle roots of the committed evaluations. The first one
+	// is the commitment to the evaluation of the polynomial.
+	Commitments [][]byte
+
+	// Openings multi proofs of the fibers hit by the queries, one per commitment
+	Openings []merkletree.MultiProof
+
+	// FinalPoly coefficients of the fully folded polynomial
+	FinalPoly []fr.Element
+
+	// Nonce solution to the proof of work of the prover
+	Nonce uint64
+}
+
+// New creates a FRI scheme capable to handle polynomials of size (rounded to the
+// next power of 2) elements, using h for the Merkle trees and Fiat Shamir.
+//
+// Without options, the scheme uses a blowup of 8, folds by 2 down to a constant
+// and makes enough queries to reach 128 bits of conjectured security.
+func New(size uint64, h hash.Hash, opts ...Option) (*Scheme, error) {
+
+	cfg := friConfig{
+		blowup:        rho,
+		foldingFactor: defaultFoldingFactor,
+	}
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.nbQueries == 0 {
+		logBlowup := bits.TrailingZeros(uint(cfg.blowup))
+		cfg.nbQueries = (targetSecurity - cfg.grindingBits + logBlowup - 1) / logBlowup
+		if cfg.nbQueries < 1 {
+			cfg.nbQueries = 1
+		}
+	}
+
+	s := &Scheme{
+		h:             h,
+		size:          ecc.NextPowerOfTwo(size),
+		blowup:        cfg.blowup,
+		foldingFactor: cfg.foldingFactor,
+		nbQueries:     cfg.nbQueries,
+		grindingBits:  cfg.grindingBits,
+	}
+
+	// the final polynomial has at most d+1 coefficients, rounded down to a power of 2
+	maxFinalSize := uint64(1) << (bits.Len64(uint64(cfg.finalPolyDegree)+1) - 1)
+	s.finalSize = int(s.size)
+	for uint64(s.finalSize) > maxFinalSize && s.finalSize >= s.foldingFactor {
+		s.finalSize /= s.foldingFactor
+		s.nbFoldings++
+	}
+	if s.nbFoldings == 0 {
+		return nil, fmt.Errorf("%w: the polynomials are too small to be folded", ErrInvalidParameters)
+	}
+	if uint64(s.finalSize) > maxFinalSize {
+		return nil, fmt.Errorf("%w: the polynomials can not be folded by %d down to size %d", ErrInvalidParameters, s.foldingFactor, maxFinalSize)
+	}
+
+	n := s.size * uint64(s.blowup)
+	if _, err := fft.Generator(n); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameters, err)
+	}
+	s.domain = fft.NewDomain(n)
+
+	// ζ = ω^{N/k}
+	var zetaInv fr.Element
+	zetaInv.Exp(s.domain.GeneratorInv, new(big.Int).SetUint64(n/uint64(s.foldingFactor)))
+	s.zetaInv = make([]fr.Element, s.foldingFactor)
+	s.zetaInv[0].SetOne()
+	for t := 1; t < s.foldingFactor; t++ {
+		s.zetaInv[t].Mul(&s.zetaInv[t-1], &zetaInv)
+	}
+	s.foldingFactorInv.SetUint64(uint64(s.foldingFactor)).Inverse(&s.foldingFactorInv)
+
+	return s, nil
+}
+
+// NbQueries returns the number of queries of the verifier
+func (s *Scheme) NbQueries() int {
+	return s.nbQueries
+}
+
+// NbFoldings returns the number of foldings of the polynomial
+func (s *Scheme) NbFoldings() int {
+	return s.nbFoldings
+}
+
+// SoundnessBits returns the conjectured and the proven security, in bits, of the
+// scheme.
+//
+// The conjectured security is nbQueries*log₂(blowup)+grindingBits [ethSTARK,
+// conjecture 1], bounded by log₂|Fᵣ|-log₂(N) where N is the size of the
+// evaluation domain.
+// The proven security relies on the Johnson bound with m = 3 [BCIKS20,
+// theorem 8.3]: the commit phase adds an error (k-1)*nbFoldings*(m+½)⁷N²/(3ρ^{3/2}|Fᵣ|)
+// and each query is passed with probability √ρ(1+1/2m), where ρ = 1/blowup.
+//
+// [ethSTARK]: https://eprint.iacr.org/2021/582
+// [BCIKS20]: https://eprint.iacr.org/2020/654
+func (s *Scheme) SoundnessBits() (conjectured, proven int) {
+
+	logBlowup := float64(bits.TrailingZeros(uint(s.blowup)))
+	logDomain := float64(bits.TrailingZeros64(s.domain.Cardinality))
+	logField := float64(fr.Bits)
+
+	c := float64(s.nbQueries)*logBlowup + float64(s.grindingBits)
+	c = math.Min(c, logField-logDomain)
+
+	m := float64(johnsonProximity)
+	logCommit := math.Log2(float64((s.foldingFactor-1)*s.nbFoldings)) + 7*math.Log2(m+0.5) +
+		2*logDomain - math.Log2(3) + 1.5*logBlowup - logField
+	logQuery := float64(s.nbQueries)*(math.Log2(1+1/(2*m))-logBlowup/2) - float64(s.grindingBits)
+
+	// log₂(2^{logCommit}+2^{logQuery})
+	hi, lo := math.Max(logCommit, logQuery), math.Min(logCommit, logQuery)
+	p := -(hi + math.Log2(1+math.Exp2(lo-hi)))
+
+	return int(math.Floor(c)), int(math.Floor(math.Max(p, 0)))
+}
+
+// BuildProofOfProximity generates a proof that a function, given as an oracle from
+// the verifier point of view, is in fact δ-close to a polynomial. p is given
+// in canonical basis and must have at most size coefficients.
+func (s *Scheme) BuildProofOfProximity(p []fr.Element) (Proof, error) {
+
+	if uint64(len(p)) > s.size {
+		return Proof{}, ErrPolySize
+	}
+
+	// evaluate p on the domain, in natural order
+	evals := make([]fr.Element, s.domain.Cardinality)
+	copy(evals, p)
+	s.domain.FFT(evals, fft.DIF)
+	fft.BitReverse(evals)
+
+	proof, _, err := s.proveEvaluations(evals, nil)
+	return proof, err
+}
+
+// proveEvaluations builds the proof of proximity of evals, the evaluation of a
+// polynomial on the domain in natural order. It returns the positions of the
+// queries in the domain as well. evals is modified.
+//
+// If seed is not nil, it is bound to the transcript before the first folding,
+// so that the queries depend on the context of the proof, e.g. the data from
+// which evals was computed.
+func (s *Scheme) proveEvaluations(evals []fr.Element, seed []byte) (Proof, []uint64, error) {
+
+	fs, err := s.newTranscript(seed)
+	if err != nil {
+		return Proof{}, nil, err
+	}
+	proof := Proof{
+		Commitments: make([][]byte, s.nbFoldings),
+		Openings:    make([]merkletree.MultiProof, s.nbFoldings),
+	}
+
+	// step 1: commit to the successive foldings
+	trees := make([]*merkletree.RetainedTree, s.nbFoldings)
+	var gInv fr.Element
+	gInv.Set(&s.domain.GeneratorInv)
+	for i := 0; i < s.nbFoldings; i++ {
+
+		trees[i] = merkletree.NewRetainedTree(s.h, s.leaves(evals))
+		proof.Commitments[i] = trees[i].Root()
+		alpha, err := s.deriveFoldingChallenge(fs, i, proof.Commitments[i])
+		if err != nil {
+			return Proof{}, nil, err
+		}
+
+		// the j-th entry of the folded evaluation is computed from the j-th fiber
+		nbFibers := len(evals) / s.foldingFactor
+		folded := make([]fr.Element, nbFibers)
+		fiber := make([]fr.Element, s.foldingFactor)
+		var xInv fr.Element
+		xInv.SetOne()
+		for j := 0; j < nbFibers; j++ {
+			for t := range fiber {
+				fiber[t] = evals[j+t*nbFibers]
+			}
+			folded[j] = s.fold(fiber, &xInv, &alpha)
+			xInv.Mul(&xInv, &gInv)
+		}
+		evals = folded
+
+		// g <- gᵏ
+		gInv.Exp(gInv, big.NewInt(int64(s.foldingFactor)))
+	}
+
+	// the final polynomial is interpolated from its evaluation, the entries
+	// beyond finalSize are zero when p is of degree < size
+	finalDomain := fft.NewDomain(uint64(len(evals)))
+	finalDomain.FFTInverse(evals, fft.DIF)
+	fft.BitReverse(evals)
+	proof.FinalPoly = evals[:s.finalSize]
+
+	// step 2: grinding then queries
+	proof.Nonce, err = s.grind(fs, proof.FinalPoly)
+	if err != nil {
+		return Proof{}, nil, err
+	}
+	positions, err := s.deriveQueriesPositions(fs, proof.Nonce)
+	if err != nil {
+		return Proof{}, nil, err
+	}
+	queries := make([]uint64, len(positions))
+	copy(queries, positions)
+
+	for i := 0; i < s.nbFoldings; i++ {
+		nbFibers := trees[i].NumLeaves()
+		indices := make([]uint64, len(positions))
+		for q := range positions {
+			indices[q] = positions[q] % nbFibers
+			positions[q] = indices[q]
+		}
+		proof.Openings[i], err = trees[i].ProveMulti(indices)
+		if err != nil {
+			return Proof{}, nil, err
+		}
+	}
+
+	return proof, queries, nil
+}
+
+// VerifyProofOfProximity verifies the proof. It returns an error if the
+// verification fails.
+func (s *Scheme) VerifyProofOfProximity(proof Proof) error {
+	_, err := s.verify(proof, nil)
+	return err
+}
+
+// verify verifies the proof, built with the given seed, and returns the
+// positions of the queries in the domain.
+func (s *Scheme) verify(proof Proof, seed []byte) ([]uint64, error) {
+
+	if len(proof.Commitments) != s.nbFoldings || len(proof.Openings) != s.nbFoldings ||
+		len(proof.FinalPoly) != s.finalSize {
+		return nil, ErrProofShape
+	}
+
+	fs, err := s.newTranscript(seed)
+	if err != nil {
+		return nil, err
+	}
+	alphas := make([]fr.Element, s.nbFoldings)
+	for i := 0; i < s.nbFoldings; i++ {
+		alphas[i], err = s.deriveFoldingChallenge(fs, i, proof.Commitments[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkGrinding(fs, proof.FinalPoly, proof.Nonce); err != nil {
+		return nil, err
+	}
+	positions, err := s.deriveQueriesPositions(fs, proof.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	queries := make([]uint64, len(positions))
+	copy(queries, positions)
+
+	// expected[q] value of the current folded polynomial at positions[q]
+	expected := make([]fr.Element, len(positions))
+	var gInv fr.Element
+	gInv.Set(&s.domain.GeneratorInv)
+	n := s.domain.Cardinality
+	for i := 0; i < s.nbFoldings; i++ {
+
+		opening := &proof.Openings[i]
+		nbFibers := n / uint64(s.foldingFactor)
+		if opening.NumLeaves != nbFibers {
+			return nil, ErrProofShape
+		}
+		if !merkletree.VerifyMultiProof(s.h, proof.Commitments[i], opening) {
+			return nil, ErrMerklePath
+		}
+
+		fibers := make(map[uint64][]fr.Element, len(opening.Indices))
+		for l, index := range opening.Indices {
+			fiber, err := s.parseLeaf(opening.Leaves[l])
+			if err != nil {
+				return nil, err
+			}
+			fibers[index] = fiber
+		}
+
+		for q := range positions {
+			j := positions[q] % nbFibers
+			fiber, ok := fibers[j]
+			if !ok {
+				return nil, ErrProofShape
+			}
+
+			// correctness of the previous folding
+			if i > 0 && !fiber[positions[q]/nbFibers].Equal(&expected[q]) {
+				return nil, ErrProximityTestFolding
+			}
+
+			var xInv fr.Element
+			xInv.Exp(gInv, new(big.Int).SetUint64(j))
+			expected[q] = s.fold(fiber, &xInv, &alphas[i])
+			positions[q] = j
+		}
+
+		gInv.Exp(gInv, big.NewInt(int64(s.foldingFactor)))
+		n = nbFibers
+	}
+
+	// the last folding must agree with the final polynomial
+	var g fr.Element
+	g.Inverse(&gInv)
+	for q := range positions {
+		var x, y fr.Element
+		x.Exp(g, new(big.Int).SetUint64(positions[q]))
+		for l := len(proof.FinalPoly) - 1; l >= 0; l-- {
+			y.Mul(&y, &x).Add(&y, &proof.FinalPoly[l])
+		}
+		if !y.Equal(&expected[q]) {
+			return nil, ErrFinalPoly
+		}
+	}
+
+	return queries, nil
+}
+
+// fold returns pᵢ₊₁(xᵏ) = ∑ₜ αᵗpᵢ,ₜ(xᵏ), from the fiber {pᵢ(xζᵗ), t < k}.
+//
+// Since pᵢ(xζˢ) = ∑ₜ(xζˢ)ᵗpᵢ,ₜ(xᵏ), the pᵢ,ₜ(xᵏ) are recovered with an
+// inverse DFT of size k: pᵢ,ₜ(xᵏ) = k⁻¹x⁻ᵗ∑ₛζ⁻ˢᵗpᵢ(xζˢ).
+func (s *Scheme) fold(fiber []fr.Element, xInv, alpha *fr.Element) fr.Element {
+
+	var res, c, tmp, acc, r fr.Element
+	acc.SetOne()
+	r.Mul(alpha, xInv)
+	for t := 0; t < s.foldingFactor; t++ {
+		c.SetZero()
+		for l := 0; l < s.foldingFactor; l++ {
+			tmp.Mul(&fiber[l], &s.zetaInv[(l*t)%s.foldingFactor])
+			c.Add(&c, &tmp)
+		}
+		c.Mul(&c, &acc)
+		res.Add(&res, &c)
+		acc.Mul(&acc, &r)
+	}
+	res.Mul(&res, &s.foldingFactorInv)
+
+	return res
+}
+
+// leaves returns the leaves of the Merkle tree committing to evals, the j-th
+// leaf being the fiber evals[j], evals[j+N/k], .., evals[j+(k-1)N/k]
+func (s *Scheme) leaves(evals []fr.Element) [][]byte {
+	nbFibers := len(evals) / s.foldingFactor
+	res := make([][]byte, nbFibers)
+	for j := range res {
+		res[j] = make([]byte, 0, s.foldingFactor*fr.Bytes)
+		for t := 0; t < s.foldingFactor; t++ {
+			b := evals[j+t*nbFibers].Bytes()
+			res[j] = append(res[j], b[:]...)
+		}
+	}
+	return res
+}
+
+// parseLeaf returns the fiber stored in a leaf
+func (s *Scheme) parseLeaf(leaf []byte) ([]fr.Element, error) {
+	if len(leaf) != s.foldingFactor*fr.Bytes {
+		return nil, ErrProofShape
+	}
+	res := make([]fr.Element, s.foldingFactor)
+	for t := range res {
+		if err := res[t].SetBytesCanonical(leaf[t*fr.Bytes : (t+1)*fr.Bytes]); err != nil {
+			return nil, ErrProofShape
+		}
+	}
+	return res, nil
+}
+
+// challengesID returns the names of the challenges of the Fiat Shamir transcript
+func (s *Scheme) challengesID() []string {
+	res := make([]string, s.nbFoldings+2)
+	for i := 0; i < s.nbFoldings; i++ {
+		res[i] = fmt.Sprintf("alpha%d", i)
+	}
+	res[s.nbFoldings] = "grinding"
+	res[s.nbFoldings+1] = "queries"
+	return res
+}
+
+// newTranscript returns the transcript of a proof of proximity, seed being
+// bound to the challenge of the first folding if it is not nil
+func (s *Scheme) newTranscript(seed []byte) (*fiatshamir.Transcript, error) {
+	fs := fiatshamir.NewTranscript(s.h, s.challengesID()...)
+	if seed != nil {
+		if err := fs.Bind("alpha0", seed); err != nil {
+			return nil, err
+		}
+	}
+	return fs, nil
+}
+
+// deriveFoldingChallenge derives the challenge of the i-th folding
+func (s *Scheme) deriveFoldingChallenge(fs *fiatshamir.Transcript, i int, root []byte) (fr.Element, error) {
+	var res fr.Element
+	id := fmt.Sprintf("alpha%d", i)
+	if err := fs.Bind(id, root); err != nil {
+		return res, err
+	}
+	b, err := fs.ComputeChallenge(id)
+	if err != nil {
+		return res, err
+	}
+	res.SetBytes(b)
+	return res, nil
+}
+
+// powSeed binds the final polynomial to the transcript and returns the seed of
+// the proof of work
+func (s *Scheme) powSeed(fs *fiatshamir.Transcript, finalPoly []fr.Element) ([]byte, error) {
+	for i := range finalPoly {
+		if err := fs.Bind("grinding", finalPoly[i].Marshal()); err != nil {
+			return nil, err
+		}
+	}
+	return fs.ComputeChallenge("grinding")
+}
+
+// powBits returns the number of leading zero bits of H(seed ∥ nonce)
+func (s *Scheme) powBits(seed []byte, nonce uint64) int {
+	var bNonce [8]byte
+	binary.BigEndian.PutUint64(bNonce[:], nonce)
+	s.h.Reset()
+	s.h.Write(seed)
+	s.h.Write(bNonce[:])
+	digest := s.h.Sum(nil)
+	s.h.Reset()
+
+	res := 0
+	for _, b := range digest {
+		if b != 0 {
+			return res + bits.LeadingZeros8(b)
+		}
+		res += 8
+	}
+	return res
+}
+
+// grind finds the smallest nonce such that H(seed ∥ nonce) starts with
+// grindingBits zeros
+func (s *Scheme) grind(fs *fiatshamir.Transcript, finalPoly []fr.Element) (uint64, error) {
+	seed, err := s.powSeed(fs, finalPoly)
+	if err != nil {
+		return 0, err
+	}
+	var nonce uint64
+	for s.powBits(seed, nonce) < s.grindingBits {
+		nonce++
+	}
+	return nonce, nil
+}
+
+// checkGrinding checks the proof of work of the prover
+func (s *Scheme) checkGrinding(fs *fiatshamir.Transcript, finalPoly []fr.Element, nonce uint64) error {
+	seed, err := s.powSeed(fs, finalPoly)
+	if err != nil {
+		return err
+	}
+	if s.powBits(seed, nonce) < s.grindingBits {
+		return ErrGrinding
+	}
+	return nil
+}
+
+// deriveQueriesPositions derives the positions, in the evaluation domain, of
+// the queries of the verifier. The q-th position is H(seed ∥ q) mod N.
+func (s *Scheme) deriveQueriesPositions(fs *fiatshamir.Transcript, nonce uint64) ([]uint64, error) {
+	var bNonce [8]byte
+	binary.BigEndian.PutUint64(bNonce[:], nonce)
+	if err := fs.Bind("queries", bNonce[:]); err != nil {
+		return nil, err
+	}
+	seed, err := fs.ComputeChallenge("queries")
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]uint64, s.nbQueries)
+	var bq [8]byte
+	for q := range res {
+		binary.BigEndian.PutUint64(bq[:], uint64(q))
+		s.h.Reset()
+		s.h.Write(seed)
+		s.h.Write(bq[:])
+		digest := s.h.Sum(nil)
+
+		// the cardinality is a power of 2, so the reduction is not biased
+		res[q] = binary.BigEndian.Uint64(digest[:8]) % s.domain.Cardinality
+	}
+	s.h.Reset()
+
+	return res, nil
+}
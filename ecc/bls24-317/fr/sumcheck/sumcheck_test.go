@@ -22,6 +22,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/polynomial"
 	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/test_vector_utils"
 	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/utils"
 	"github.com/stretchr/testify/assert"
 	"hash"
 	"math/bits"
@@ -159,3 +160,64 @@ func TestSumcheckDeterministicHashSingleClaimMultilin(t *testing.T) {
 		}
 	}
 }
+
+func testSumcheckVirtualPolynomial(nbVars int, opts ...VirtualOption) error {
+	polys := make([]polynomial.MultiLin, 3)
+	for k := range polys {
+		polys[k] = make(polynomial.MultiLin, 1<<nbVars)
+		for i := range polys[k] {
+			polys[k][i].SetUint64(uint64((k+2)*i%7 + k))
+		}
+	}
+
+	// g = 2P₀P₁P₂ + 3P₀² + P₂
+	var two, three, one fr.Element
+	two.SetUint64(2)
+	three.SetUint64(3)
+	one.SetUint64(1)
+	v := VirtualPolynomial{
+		Polys: polys,
+		Terms: []Term{
+			{Coeff: two, Factors: []int{0, 1, 2}},
+			{Coeff: three, Factors: []int{0, 0}},
+			{Coeff: one, Factors: []int{2}},
+		},
+	}
+
+	proof, err := Prove(NewVirtualClaims(v, opts...), fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1)))
+	if err != nil {
+		return err
+	}
+
+	lazyClaims := VirtualLazyClaims{
+		Terms:      v.Terms,
+		NbPolys:    len(polys),
+		NbVars:     nbVars,
+		ClaimedSum: v.Sum(),
+	}
+	if err = Verify(&lazyClaims, proof, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1))); err != nil {
+		return err
+	}
+
+	// the polynomials must not have been modified by the prover
+	if s := v.Sum(); !s.Equal(&lazyClaims.ClaimedSum) {
+		return fmt.Errorf("the virtual polynomial was modified")
+	}
+
+	lazyClaims.ClaimedSum.Add(&lazyClaims.ClaimedSum, &one)
+	if Verify(&lazyClaims, proof, fiatshamir.WithHash(test_vector_utils.NewMessageCounter(1, 1))) == nil {
+		return fmt.Errorf("wrong sum accepted")
+	}
+	return nil
+}
+
+func TestSumcheckVirtualPolynomial(t *testing.T) {
+	for _, nbVars := range []int{1, 3, 8} {
+		assert.NoError(t, testSumcheckVirtualPolynomial(nbVars), "failed with %d variables", nbVars)
+	}
+
+	workers := utils.NewWorkerPool()
+	defer workers.Stop()
+	pool := polynomial.NewPool(1 << 8)
+	assert.NoError(t, testSumcheckVirtualPolynomial(8, WithWorkers(workers), WithPool(&pool)))
+}
@@ -0,0 +1,238 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package gemini
+
+import (
+	"errors"
+	"hash"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/polynomial"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/kzg"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/shplonk"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/transcript"
+	"github.com/consensys/gnark-crypto/fiat-shamir"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidPolynomialSize = errors.New("invalid polynomial size (not a power of 2, larger than SRS or < 2)")
+	ErrInvalidPointSize      = errors.New("the number of coordinates of the point is not the number of variables")
+	ErrClaimedValue          = errors.New("the claimed value is not the evaluation of the polynomial at the point")
+	ErrInvalidProof          = errors.New("malformed opening proof")
+	ErrDegenerateChallenge   = errors.New("the challenge r is 0, 1 or -1")
+	ErrVerifyOpeningProof    = errors.New("can't verify opening proof")
+)
+
+// OpeningProof proof of the evaluation of a multilinear polynomial committed
+// with kzg.Commit.
+//
+// implements io.ReaderFrom and io.WriterTo
+type OpeningProof struct {
+	// Folds commitments to the foldings f₁, ..., fₙ₋₁
+	Folds []kzg.Digest
+
+	// Opening batched opening of f₀ at (r, -r) and of fᵢ at (r, -r, r²)
+	Opening shplonk.OpeningProof
+}
+
+// Commit commits to a multilinear polynomial, given by its evaluations on the
+// hypercube, as the univariate polynomial whose coefficients are these
+// evaluations.
+func Commit(p polynomial.MultiLin, pk kzg.ProvingKey, nbTasks ...int) (kzg.Digest, error) {
+	return kzg.Commit(p, pk, nbTasks...)
+}
+
+// Open proves that the multilinear polynomial p, committed in digest, evaluates
+// to claimedValue at point. For p in n variables, pk must have at least 2ⁿ+3n-2
+// points.
+//
+// * dataTranscript extra data that might be needed to derive the challenge r
+func Open(p polynomial.MultiLin, point []fr.Element, claimedValue fr.Element, digest kzg.Digest, hf hash.Hash, pk kzg.ProvingKey, dataTranscript ...[]byte) (OpeningProof, error) {
+
+	nbVars, err := nbVarsOf(len(p))
+	if err != nil {
+		return OpeningProof{}, err
+	}
+	// the shplonk opening of the 3n-1 evaluations needs 3n-2 more points than the size of p
+	if len(p)+3*nbVars-2 > len(pk.G1) {
+		return OpeningProof{}, ErrInvalidPolynomialSize
+	}
+	if len(point) != nbVars {
+		return OpeningProof{}, ErrInvalidPointSize
+	}
+
+	// foldings[i] = fᵢ, foldings[n] = [f(z)]
+	foldings := make([][]fr.Element, nbVars+1)
+	foldings[0] = p
+	for i := 0; i < nbVars; i++ {
+		prev := foldings[i]
+		u := point[nbVars-1-i]
+		next := make([]fr.Element, len(prev)/2)
+		parallel.Execute(len(next), func(start, end int) {
+			var t fr.Element
+			for j := start; j < end; j++ {
+				t.Sub(&prev[2*j+1], &prev[2*j]).Mul(&t, &u)
+				next[j].Add(&prev[2*j], &t)
+			}
+		})
+		foldings[i+1] = next
+	}
+	if !foldings[nbVars][0].Equal(&claimedValue) {
+		return OpeningProof{}, ErrClaimedValue
+	}
+
+	res := OpeningProof{
+		Folds: make([]kzg.Digest, nbVars-1),
+	}
+	for i := range res.Folds {
+		if res.Folds[i], err = kzg.Commit(foldings[i+1], pk); err != nil {
+			return OpeningProof{}, err
+		}
+	}
+
+	r, err := deriveR(hf, digest, point, claimedValue, res.Folds, dataTranscript...)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+	points, err := openingPoints(r, nbVars)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+	digests := make([]kzg.Digest, nbVars)
+	digests[0] = digest
+	copy(digests[1:], res.Folds)
+
+	res.Opening, err = shplonk.BatchOpen(foldings[:nbVars], digests, points, hf, pk)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+
+	return res, nil
+}
+
+// Verify verifies that the multilinear polynomial committed in digest evaluates
+// to claimedValue at point.
+//
+// * dataTranscript extra data that might be needed to derive the challenge r
+func Verify(digest *kzg.Digest, proof *OpeningProof, point []fr.Element, claimedValue fr.Element, hf hash.Hash, vk kzg.VerifyingKey, dataTranscript ...[]byte) error {
+
+	nbVars := len(point)
+	if nbVars < 1 {
+		return ErrInvalidPointSize
+	}
+	if len(proof.Folds) != nbVars-1 || len(proof.Opening.ClaimedValues) != nbVars {
+		return ErrInvalidProof
+	}
+	for i, values := range proof.Opening.ClaimedValues {
+		if (i == 0 && len(values) != 2) || (i != 0 && len(values) != 3) {
+			return ErrInvalidProof
+		}
+	}
+
+	r, err := deriveR(hf, *digest, point, claimedValue, proof.Folds, dataTranscript...)
+	if err != nil {
+		return err
+	}
+	points, err := openingPoints(r, nbVars)
+	if err != nil {
+		return err
+	}
+
+	// fᵢ₊₁(r²) = (1-u)(fᵢ(r)+fᵢ(-r))/2 + u(fᵢ(r)-fᵢ(-r))/2r, multiplied by 2r
+	var twoR, left, right, sum, diff, oneMinusU fr.Element
+	twoR.Double(&r)
+	for i := 0; i < nbVars; i++ {
+		values := proof.Opening.ClaimedValues[i]
+		u := &point[nbVars-1-i]
+		if i+1 < nbVars {
+			left.Mul(&proof.Opening.ClaimedValues[i+1][2], &twoR)
+		} else {
+			left.Mul(&claimedValue, &twoR)
+		}
+		sum.Add(&values[0], &values[1])
+		diff.Sub(&values[0], &values[1])
+		oneMinusU.SetOne().Sub(&oneMinusU, u)
+		right.Mul(&sum, &oneMinusU).Mul(&right, &r)
+		diff.Mul(&diff, u)
+		right.Add(&right, &diff)
+		if !left.Equal(&right) {
+			return ErrVerifyOpeningProof
+		}
+	}
+
+	digests := make([]kzg.Digest, nbVars)
+	digests[0] = *digest
+	copy(digests[1:], proof.Folds)
+
+	return shplonk.BatchVerify(proof.Opening, digests, points, hf, vk)
+}
+
+// nbVarsOf returns the number of variables of a multilinear polynomial given
+// by size evaluations
+func nbVarsOf(size int) (int, error) {
+	if size < 2 || size&(size-1) != 0 {
+		return 0, ErrInvalidPolynomialSize
+	}
+	return bits.TrailingZeros(uint(size)), nil
+}
+
+// openingPoints returns the points at which f₀, ..., fₙ₋₁ are opened: (r, -r)
+// for f₀ and (r, -r, r²) for the others. They must be distinct, hence r ∉ {0, 1, -1}.
+func openingPoints(r fr.Element, nbVars int) ([][]fr.Element, error) {
+	var minusR, rSquare fr.Element
+	minusR.Neg(&r)
+	rSquare.Square(&r)
+	if r.IsZero() || rSquare.IsOne() {
+		return nil, ErrDegenerateChallenge
+	}
+	res := make([][]fr.Element, nbVars)
+	res[0] = []fr.Element{r, minusR}
+	for i := 1; i < nbVars; i++ {
+		res[i] = []fr.Element{r, minusR, rSquare}
+	}
+	return res, nil
+}
+
+// deriveR derives the challenge r using Fiat Shamir, binded to the commitment,
+// the point, the claimed value and the commitments to the foldings.
+func deriveR(hf hash.Hash, digest kzg.Digest, point []fr.Element, claimedValue fr.Element, folds []kzg.Digest, dataTranscript ...[]byte) (fr.Element, error) {
+
+	fs := transcript.NewLegacy(fiatshamir.NewTranscript(hf, "r"))
+	if err := fs.AppendPoint("r", digest); err != nil {
+		return fr.Element{}, err
+	}
+	if err := fs.AppendScalar("r", point...); err != nil {
+		return fr.Element{}, err
+	}
+	if err := fs.AppendScalar("r", claimedValue); err != nil {
+		return fr.Element{}, err
+	}
+	if err := fs.AppendPoint("r", folds...); err != nil {
+		return fr.Element{}, err
+	}
+
+	for i := 0; i < len(dataTranscript); i++ {
+		if err := fs.AppendMessage("r", dataTranscript[i]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+
+	return fs.ChallengeScalar("r")
+}
@@ -28,6 +28,8 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bls24-317"
 	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/transcript"
+	"github.com/consensys/gnark-crypto/fiat-shamir"
 
 	"github.com/consensys/gnark-crypto/utils/testutils"
 )
@@ -113,6 +115,61 @@ func TestCommitLagrange(t *testing.T) {
 	assert.True(digestCanonical.Equal(&digestLagrange), "error CommitLagrange")
 }
 
+func TestProvingKeyLagrange(t *testing.T) {
+
+	assert := require.New(t)
+
+	const size = 32
+	domain := fft.NewDomain(size)
+
+	for _, bitReversed := range []bool{false, true} {
+
+		pk, err := NewProvingKeyLagrange(testSrs.Pk, domain, bitReversed)
+		assert.NoError(err)
+		t.Run("serialization", testutils.SerializationRoundTrip(&pk))
+		t.Run("raw serialization", testutils.SerializationRoundTripRaw(&pk))
+
+		// random polynomial, in canonical form and in evaluation form in the order of pk
+		canonical := randomPolynomial(size)
+		evaluations := make([]fr.Element, size)
+		copy(evaluations, canonical)
+		domain.FFT(evaluations, fft.DIF)
+		if !bitReversed {
+			fft.BitReverse(evaluations)
+		}
+
+		// the commitments match
+		digest, err := CommitLagrange(evaluations, pk)
+		assert.NoError(err)
+		digestCanonical, err := Commit(canonical, testSrs.Pk)
+		assert.NoError(err)
+		assert.True(digest.Equal(&digestCanonical), "error CommitLagrange")
+
+		// open outside of the domain and at a point of the domain
+		var outside fr.Element
+		outside.SetRandom()
+		for _, point := range []fr.Element{outside, domain.Generator} {
+			proof, err := OpenLagrange(evaluations, point, pk)
+			assert.NoError(err)
+			expected := eval(canonical, point)
+			assert.True(proof.ClaimedValue.Equal(&expected), "wrong claimed value")
+
+			proofCanonical, err := Open(canonical, point, testSrs.Pk)
+			assert.NoError(err)
+			assert.True(proof.H.Equal(&proofCanonical.H), "wrong quotient")
+			assert.NoError(Verify(&digest, &proof, point, testSrs.Vk))
+		}
+
+		_, err = CommitLagrange(evaluations[:size-1], pk)
+		assert.ErrorIs(err, ErrInvalidPolynomialSize)
+		_, err = OpenLagrange(evaluations[:size-1], outside, pk)
+		assert.ErrorIs(err, ErrInvalidPolynomialSize)
+	}
+
+	_, err := NewProvingKeyLagrange(testSrs.Pk, fft.NewDomain(uint64(2*len(testSrs.Pk.G1))), false)
+	assert.ErrorIs(err, ErrInvalidDomainSize)
+}
+
 func TestDividePolyByXminusA(t *testing.T) {
 
 	const pSize = 230
@@ -365,6 +422,49 @@ func TestBatchVerifySinglePoint(t *testing.T) {
 	}
 }
 
+func TestBatchVerifySinglePointWithTranscript(t *testing.T) {
+	assert := require.New(t)
+
+	// create polynomials
+	f := make([][]fr.Element, 5)
+	for i := range f {
+		f[i] = randomPolynomial(40)
+	}
+
+	// commit the polynomials
+	digests := make([]Digest, len(f))
+	for i := range f {
+		digests[i], _ = Commit(f[i], testSrs.Pk)
+	}
+
+	var point fr.Element
+	point.SetRandom()
+
+	newTranscripts := map[string]func() transcript.Transcript{
+		"merlin": func() transcript.Transcript {
+			return transcript.NewLegacy(fiatshamir.NewMerlinTranscript("kzg test", "gamma"))
+		},
+		"streaming": func() transcript.Transcript {
+			return transcript.NewStreaming(sha256.New(), "kzg test")
+		},
+	}
+	for name, newTranscript := range newTranscripts {
+		t.Run(name, func(t *testing.T) {
+			proof, err := BatchOpenSinglePointWithTranscript(f, digests, point, newTranscript(), testSrs.Pk)
+			assert.NoError(err)
+			assert.NoError(BatchVerifySinglePointWithTranscript(digests, &proof, point, newTranscript(), testSrs.Vk))
+
+			// the proof is bound to the transcript
+			fs := newTranscript()
+			assert.NoError(fs.AppendMessage("gamma", []byte("salt")))
+			assert.Error(BatchVerifySinglePointWithTranscript(digests, &proof, point, fs, testSrs.Vk))
+
+			proof.ClaimedValues[0].Double(&proof.ClaimedValues[0])
+			assert.Error(BatchVerifySinglePointWithTranscript(digests, &proof, point, newTranscript(), testSrs.Vk))
+		})
+	}
+}
+
 func TestBatchVerifyMultiPoints(t *testing.T) {
 
 	// create polynomials
@@ -471,8 +571,209 @@ func TestUnsafeToBytesTruncating(t *testing.T) {
 	assert.Equal(srs.Pk.G1[:1<<8], newSRSPartial.Pk.G1)
 }
 
+func TestFK20SinglePoint(t *testing.T) {
+	assert := require.New(t)
+
+	f := randomPolynomial(60)
+	digest, err := Commit(f, testSrs.Pk)
+	assert.NoError(err)
+
+	for _, domainSize := range []uint64{64, 128} {
+		fk, err := NewFK20(testSrs.Pk, uint64(len(f)), domainSize, 1)
+		assert.NoError(err)
+		proofs, err := fk.OpenAll(f)
+		assert.NoError(err)
+		assert.Equal(int(domainSize), len(proofs))
+
+		// compare with the proofs computed one by one
+		w, err := fr.Generator(domainSize)
+		assert.NoError(err)
+		var x fr.Element
+		x.SetOne()
+		for i := range proofs {
+			expected, err := Open(f, x, testSrs.Pk)
+			assert.NoError(err)
+			assert.True(expected.H.Equal(&proofs[i].H), "wrong proof at ω^%d", i)
+			assert.True(expected.ClaimedValue.Equal(&proofs[i].ClaimedValues[0]), "wrong value at ω^%d", i)
+			x.Mul(&x, &w)
+		}
+
+		proof := OpeningProof{H: proofs[3].H, ClaimedValue: proofs[3].ClaimedValues[0]}
+		x.Exp(w, big.NewInt(3))
+		assert.NoError(Verify(&digest, &proof, x, testSrs.Vk))
+
+		// cosets of size 1 are verified with the G₂ points of the verifying key
+		vk, err := NewCosetVerifyingKey(testSrs.Pk, testSrs.Vk.G2[:], 1)
+		assert.NoError(err)
+		assert.NoError(VerifyCosetProof(&digest, &proofs[3], x, vk))
+	}
+
+	_, err = NewFK20(testSrs.Pk, 60, 48, 1)
+	assert.ErrorIs(err, ErrInvalidFK20Sizes)
+}
+
+func TestFK20Cosets(t *testing.T) {
+	assert := require.New(t)
+
+	const (
+		polySize   = 64
+		domainSize = 128
+		cosetSize  = 8
+	)
+
+	f := randomPolynomial(polySize)
+	digest, err := Commit(f, testSrs.Pk)
+	assert.NoError(err)
+
+	fk, err := NewFK20(testSrs.Pk, polySize, domainSize, cosetSize)
+	assert.NoError(err)
+	proofs, err := fk.OpenAll(f)
+	assert.NoError(err)
+	assert.Equal(domainSize/cosetSize, len(proofs))
+
+	// the verifying key needs [αˡ]G₂, from the powers of α in G₂ published by
+	// the ceremony
+	g2Powers := ceremonyG2Powers(cosetSize + 2)
+	vk, err := NewCosetVerifyingKey(testSrs.Pk, g2Powers, cosetSize)
+	assert.NoError(err)
+
+	// the powers must match the proving key
+	_, err = NewCosetVerifyingKey(testSrs.Pk, g2Powers[:cosetSize], cosetSize)
+	assert.ErrorIs(err, ErrInvalidCosetVerifyingKey)
+	shifted := append([]bls24317.G2Affine{g2Powers[0]}, g2Powers[2:]...)
+	_, err = NewCosetVerifyingKey(testSrs.Pk, shifted, cosetSize)
+	assert.ErrorIs(err, ErrInvalidCosetVerifyingKey)
+	_, err = NewCosetVerifyingKey(testSrs.Pk, g2Powers, cosetSize-1)
+	assert.ErrorIs(err, ErrInvalidCosetSize)
+
+	w, err := fr.Generator(domainSize)
+	assert.NoError(err)
+	var c fr.Element
+	c.SetOne()
+	for k := range proofs {
+		assert.NoError(VerifyCosetProof(&digest, &proofs[k], c, vk), "coset %d", k)
+		c.Mul(&c, &w)
+	}
+
+	t.Run("serialization", testutils.SerializationRoundTrip(&proofs[0]))
+
+	// verify at the wrong coset
+	assert.Error(VerifyCosetProof(&digest, &proofs[1], c, vk))
+
+	// verify wrong values
+	c.SetOne()
+	proofs[0].ClaimedValues[5].Double(&proofs[0].ClaimedValues[5])
+	assert.Error(VerifyCosetProof(&digest, &proofs[0], c, vk))
+	proofs[0].ClaimedValues = proofs[0].ClaimedValues[1:]
+	assert.ErrorIs(VerifyCosetProof(&digest, &proofs[0], c, vk), ErrInvalidCosetSize)
+}
+
+// ceremonyG2Powers returns [G₂, [α]G₂, ..., [αⁿ⁻¹]G₂] for the α of testSrs,
+// standing for the powers in G₂ published by a Powers of Tau ceremony
+func ceremonyG2Powers(n int) []bls24317.G2Affine {
+	res := make([]bls24317.G2Affine, n)
+	res[0] = testSrs.Vk.G2[0]
+	for i := 1; i < n; i++ {
+		res[i].ScalarMultiplication(&res[i-1], bAlpha)
+	}
+	return res
+}
+
 const benchSize = 1 << 16
 
+func TestVerifyHiding(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := NewHidingSRS(64, big.NewInt(42), big.NewInt(43))
+	assert.NoError(err)
+	t.Run("SRS serialization", testutils.SerializationRoundTrip(srs))
+
+	f := randomPolynomial(60)
+	digest, blinding, err := CommitHiding(f, srs.Pk)
+	assert.NoError(err)
+	assert.Equal(len(f), len(blinding))
+
+	// the commitment is blinded
+	digestNonHiding, err := Commit(f, ProvingKey{G1: srs.Pk.G1})
+	assert.NoError(err)
+	assert.False(digest.Equal(&digestNonHiding), "commitment not blinded")
+	digest2, _, err := CommitHiding(f, srs.Pk)
+	assert.NoError(err)
+	assert.False(digest.Equal(&digest2), "commitments of the same polynomial should differ")
+
+	var point fr.Element
+	point.SetRandom()
+	proof, err := OpenHiding(f, blinding, point, srs.Pk)
+	assert.NoError(err)
+	expected := eval(f, point)
+	assert.True(proof.ClaimedValue.Equal(&expected), "wrong claimed value")
+	t.Run("proof serialization", testutils.SerializationRoundTrip(&proof))
+
+	// verify correct proof
+	assert.NoError(VerifyHiding(&digest, &proof, point, srs.Vk))
+
+	// verify wrong point
+	var wrongPoint fr.Element
+	wrongPoint.Double(&point)
+	assert.ErrorIs(VerifyHiding(&digest, &proof, wrongPoint, srs.Vk), ErrVerifyOpeningProof)
+
+	// verify wrong claimed and blinding values
+	proof.ClaimedValue.Double(&proof.ClaimedValue)
+	assert.ErrorIs(VerifyHiding(&digest, &proof, point, srs.Vk), ErrVerifyOpeningProof)
+	proof.ClaimedValue = expected
+	proof.BlindingValue.Double(&proof.BlindingValue)
+	assert.ErrorIs(VerifyHiding(&digest, &proof, point, srs.Vk), ErrVerifyOpeningProof)
+
+	// wrong blinding polynomial
+	_, err = OpenHiding(f, blinding[1:], point, srs.Pk)
+	assert.ErrorIs(err, ErrInvalidBlindingSize)
+	_, _, err = CommitHiding(randomPolynomial(65), srs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+func TestBatchVerifyHidingSinglePoint(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := NewHidingSRS(64, big.NewInt(42), big.NewInt(43))
+	assert.NoError(err)
+
+	// polynomials of different sizes
+	sizes := []int{60, 32, 1, 64}
+	f := make([][]fr.Element, len(sizes))
+	blindings := make([][]fr.Element, len(sizes))
+	digests := make([]Digest, len(sizes))
+	for i, size := range sizes {
+		f[i] = randomPolynomial(size)
+		digests[i], blindings[i], err = CommitHiding(f[i], srs.Pk)
+		assert.NoError(err)
+	}
+
+	hf := sha256.New()
+	var point fr.Element
+	point.SetRandom()
+	proof, err := BatchOpenHidingSinglePoint(f, blindings, digests, point, hf, srs.Pk, []byte("data"))
+	assert.NoError(err)
+	for i := range f {
+		expected := eval(f[i], point)
+		assert.True(proof.ClaimedValues[i].Equal(&expected), "wrong claimed value")
+	}
+	t.Run("serialization", testutils.SerializationRoundTrip(&proof))
+
+	// verify correct proof
+	assert.NoError(BatchVerifyHidingSinglePoint(digests, &proof, point, hf, srs.Vk, []byte("data")))
+
+	// verify with different transcript data
+	assert.Error(BatchVerifyHidingSinglePoint(digests, &proof, point, hf, srs.Vk, []byte("other data")))
+
+	// verify wrong blinding value
+	proof.BlindingValues[1].Double(&proof.BlindingValues[1])
+	assert.ErrorIs(BatchVerifyHidingSinglePoint(digests, &proof, point, hf, srs.Vk, []byte("data")), ErrVerifyOpeningProof)
+
+	// wrong number of blinding polynomials
+	_, err = BatchOpenHidingSinglePoint(f, blindings[1:], digests, point, hf, srs.Pk)
+	assert.ErrorIs(err, ErrInvalidNbDigests)
+}
+
 func BenchmarkSRSGen(b *testing.B) {
 
 	b.Run("real SRS", func(b *testing.B) {
@@ -637,6 +938,23 @@ func BenchmarkKZGBatchVerify10(b *testing.B) {
 	}
 }
 
+func BenchmarkFK20(b *testing.B) {
+	const polySize = 1 << 8
+	srs, err := NewSRS(polySize, new(big.Int).SetInt64(42))
+	if err != nil {
+		b.Fatal(err)
+	}
+	fk, err := NewFK20(srs.Pk, polySize, 2*polySize, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	f := randomPolynomial(polySize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = fk.ComputeQuotients(f)
+	}
+}
+
 func randomPolynomial(size int) []fr.Element {
 	f := make([]fr.Element, size)
 	for i := 0; i < size; i++ {
@@ -0,0 +1,559 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package mlkzg
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/polynomial"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/transcript"
+	"github.com/consensys/gnark-crypto/fiat-shamir"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidNbDigests      = errors.New("number of digests is not the same as the number of polynomials")
+	ErrZeroNbDigests         = errors.New("number of digests is zero")
+	ErrInvalidPolynomialSize = errors.New("invalid polynomial size (not a power of 2, larger than SRS or == 0)")
+	ErrInvalidPointSize      = errors.New("the number of coordinates of the point is not the number of variables")
+	ErrVerifyOpeningProof    = errors.New("can't verify opening proof")
+	ErrMinSRSSize            = errors.New("minimum number of variables is 1")
+)
+
+// Digest commitment of a multilinear polynomial.
+type Digest = bls24317.G1Affine
+
+// ProvingKey used to create or open commitments
+type ProvingKey struct {
+	// G1[k] is the Lagrange basis of {0,1}ⁿ⁻ᵏ at (τₖ₊₁, ..., τₙ):
+	// G1[k][b] = [Eq(b, (τₖ₊₁, ..., τₙ))]G₁, where b is read as in polynomial.MultiLin.
+	// G1[n] = [G₁]
+	G1 [][]bls24317.G1Affine
+}
+
+// VerifyingKey used to verify opening proofs
+type VerifyingKey struct {
+	G1    bls24317.G1Affine
+	G2    bls24317.G2Affine
+	G2Tau []bls24317.G2Affine // [ [τ₁]G₂, ..., [τₙ]G₂ ]
+}
+
+// SRS must be computed through MPC and comprises the ProvingKey and the VerifyingKey
+type SRS struct {
+	Pk ProvingKey
+	Vk VerifyingKey
+}
+
+// NbVars returns the largest number of variables of a polynomial that can be
+// committed with pk
+func (pk *ProvingKey) NbVars() int {
+	return len(pk.G1) - 1
+}
+
+// NewSRS returns a new SRS for multilinear polynomials in len(bTau) variables,
+// using bTau as randomness source.
+//
+// In production, a SRS generated through MPC should be used.
+//
+// implements io.ReaderFrom and io.WriterTo
+func NewSRS(bTau []*big.Int) (*SRS, error) {
+
+	nbVars := len(bTau)
+	if nbVars < 1 {
+		return nil, ErrMinSRSSize
+	}
+
+	var srs SRS
+	_, _, gen1Aff, gen2Aff := bls24317.Generators()
+
+	srs.Vk.G1 = gen1Aff
+	srs.Vk.G2 = gen2Aff
+	srs.Vk.G2Tau = make([]bls24317.G2Affine, nbVars)
+	tau := make([]fr.Element, nbVars)
+	for i := range bTau {
+		tau[i].SetBigInt(bTau[i])
+		srs.Vk.G2Tau[i].ScalarMultiplication(&gen2Aff, bTau[i])
+	}
+
+	// G1[0] = [Eq(., τ)]G₁
+	srs.Pk.G1 = make([][]bls24317.G1Affine, nbVars+1)
+	eq := make(polynomial.MultiLin, 1<<nbVars)
+	eq[0].SetOne()
+	eq.Eq(tau)
+	srs.Pk.G1[0] = bls24317.BatchScalarMultiplicationG1(&gen1Aff, eq)
+
+	// Eq(b₁ ∥ b, (τₖ, ..., τₙ)) = Eq(b₁, τₖ) Eq(b, (τₖ₊₁, ..., τₙ)), and summing over b₁
+	// gives G1[k][b] = G1[k-1][0 ∥ b] + G1[k-1][1 ∥ b]
+	for k := 1; k <= nbVars; k++ {
+		prev := srs.Pk.G1[k-1]
+		mid := len(prev) / 2
+		level := make([]bls24317.G1Jac, mid)
+		parallel.Execute(mid, func(start, end int) {
+			for j := start; j < end; j++ {
+				level[j].FromAffine(&prev[j])
+				level[j].AddMixed(&prev[j+mid])
+			}
+		})
+		srs.Pk.G1[k] = bls24317.BatchJacobianToAffineG1(level)
+	}
+
+	return &srs, nil
+}
+
+// OpeningProof multilinear KZG proof for opening at a single point.
+//
+// implements io.ReaderFrom and io.WriterTo
+type OpeningProof struct {
+	// Quotients commitments to the quotients qᵢ(Xᵢ₊₁, ..., Xₙ) such that
+	// f - f(z) = ∑ᵢ (Xᵢ - zᵢ) qᵢ
+	Quotients []bls24317.G1Affine
+
+	// ClaimedValue purported value
+	ClaimedValue fr.Element
+}
+
+// BatchOpeningProof opening proof for many polynomials at the same point
+//
+// implements io.ReaderFrom and io.WriterTo
+type BatchOpeningProof struct {
+	// Quotients commitments to the quotients of ∑ᵢγⁱfᵢ
+	Quotients []bls24317.G1Affine
+
+	// ClaimedValues purported values
+	ClaimedValues []fr.Element
+}
+
+// nbVarsOf returns the number of variables of p, or an error if p can't be
+// committed with pk
+func nbVarsOf(p polynomial.MultiLin, pk *ProvingKey) (int, error) {
+	if len(p) == 0 || len(p)&(len(p)-1) != 0 {
+		return 0, ErrInvalidPolynomialSize
+	}
+	n := bits.TrailingZeros(uint(len(p)))
+	if n > pk.NbVars() {
+		return 0, ErrInvalidPolynomialSize
+	}
+	return n, nil
+}
+
+// Commit commits to a multilinear polynomial, given by its evaluations on the
+// hypercube, using a multi exponentiation with the SRS. A polynomial in m ≤ n
+// variables is committed as [p(τₙ₋ₘ₊₁, ..., τₙ)]G₁.
+func Commit(p polynomial.MultiLin, pk ProvingKey, nbTasks ...int) (Digest, error) {
+
+	nbVars, err := nbVarsOf(p, &pk)
+	if err != nil {
+		return Digest{}, err
+	}
+
+	var res bls24317.G1Affine
+
+	config := ecc.MultiExpConfig{}
+	if len(nbTasks) > 0 {
+		config.NbTasks = nbTasks[0]
+	}
+	if _, err := res.MultiExp(pk.G1[pk.NbVars()-nbVars], p, config); err != nil {
+		return Digest{}, err
+	}
+
+	return res, nil
+}
+
+// Open computes an opening proof of p at point. point must have as many
+// coordinates as p has variables.
+func Open(p polynomial.MultiLin, point []fr.Element, pk ProvingKey) (OpeningProof, error) {
+
+	nbVars, err := nbVarsOf(p, &pk)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+	if len(point) != nbVars {
+		return OpeningProof{}, ErrInvalidPointSize
+	}
+	offset := pk.NbVars() - nbVars
+
+	res := OpeningProof{
+		Quotients: make([]bls24317.G1Affine, nbVars),
+	}
+
+	// writing f = (1-X₁)f(0, .) + X₁f(1, .), we have
+	// f - f(z₁, .) = (X₁ - z₁)(f(1, .) - f(0, .)) and we carry on with f(z₁, .)
+	f := p.Clone()
+	q := make(polynomial.MultiLin, len(p)/2)
+	for i := range point {
+		mid := len(f) / 2
+		q = q[:mid]
+		parallel.Execute(mid, func(start, end int) {
+			for j := start; j < end; j++ {
+				q[j].Sub(&f[mid+j], &f[j])
+			}
+		})
+		if _, err := res.Quotients[i].MultiExp(pk.G1[offset+i+1], q, ecc.MultiExpConfig{}); err != nil {
+			return OpeningProof{}, err
+		}
+		f.Fold(point[i])
+	}
+	res.ClaimedValue = f[0]
+
+	return res, nil
+}
+
+// Verify verifies a multilinear KZG opening proof at a single point
+func Verify(commitment *Digest, proof *OpeningProof, point []fr.Element, vk VerifyingKey) error {
+
+	nbVars := len(proof.Quotients)
+	if len(point) != nbVars {
+		return ErrInvalidPointSize
+	}
+	if nbVars > len(vk.G2Tau) {
+		return ErrInvalidPolynomialSize
+	}
+	offset := len(vk.G2Tau) - nbVars
+
+	// [f(τ) - f(z) + ∑ᵢzᵢqᵢ(τ)]G₁, computed with a single multi exponentiation
+	points := make([]bls24317.G1Affine, 0, nbVars+2)
+	scalars := make([]fr.Element, nbVars+2)
+	points = append(points, *commitment, vk.G1)
+	points = append(points, proof.Quotients...)
+	scalars[0].SetOne()
+	scalars[1].Neg(&proof.ClaimedValue)
+	copy(scalars[2:], point)
+	var totalG1 bls24317.G1Affine
+	if _, err := totalG1.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+
+	// e([f(τ) - f(z) + ∑ᵢzᵢqᵢ(τ)]G₁, G₂) ∏ᵢ e([-qᵢ(τ)]G₁, [τᵢ]G₂) == 1
+	P := make([]bls24317.G1Affine, nbVars+1)
+	Q := make([]bls24317.G2Affine, nbVars+1)
+	P[0] = totalG1
+	Q[0] = vk.G2
+	for i := 0; i < nbVars; i++ {
+		P[i+1].Neg(&proof.Quotients[i])
+		Q[i+1] = vk.G2Tau[offset+i]
+	}
+	check, err := bls24317.PairingCheck(P, Q)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// BatchOpenSinglePoint creates a batch opening proof at point of a list of
+// multilinear polynomials, all in the same number of variables.
+// It's an interactive protocol, made non-interactive using Fiat Shamir.
+//
+// * point is the point at which the polynomials are opened.
+// * digests is the list of committed polynomials to open, need to derive the challenge using Fiat Shamir.
+// * polynomials is the list of polynomials to open.
+// * dataTranscript extra data that might be needed to derive the challenge used for folding
+func BatchOpenSinglePoint(polynomials []polynomial.MultiLin, digests []Digest, point []fr.Element, hf hash.Hash, pk ProvingKey, dataTranscript ...[]byte) (BatchOpeningProof, error) {
+
+	nbDigests := len(digests)
+	if nbDigests != len(polynomials) {
+		return BatchOpeningProof{}, ErrInvalidNbDigests
+	}
+	if nbDigests == 0 {
+		return BatchOpeningProof{}, ErrZeroNbDigests
+	}
+	for _, p := range polynomials {
+		if _, err := nbVarsOf(p, &pk); err != nil {
+			return BatchOpeningProof{}, err
+		}
+		if len(p) != len(polynomials[0]) {
+			return BatchOpeningProof{}, ErrInvalidPolynomialSize
+		}
+	}
+	if 1<<len(point) != len(polynomials[0]) {
+		return BatchOpeningProof{}, ErrInvalidPointSize
+	}
+
+	var res BatchOpeningProof
+
+	// compute the purported values
+	res.ClaimedValues = make([]fr.Element, nbDigests)
+	parallel.Execute(nbDigests, func(start, end int) {
+		for i := start; i < end; i++ {
+			res.ClaimedValues[i] = polynomials[i].Evaluate(point, nil)
+		}
+	})
+
+	// derive the challenge γ, binded to the point and the commitments
+	gamma, err := deriveGamma(newGammaTranscript(hf), point, digests, res.ClaimedValues, dataTranscript...)
+	if err != nil {
+		return BatchOpeningProof{}, err
+	}
+
+	// compute ∑ᵢγⁱfᵢ
+	folded := polynomials[0].Clone()
+	gammas := make([]fr.Element, nbDigests)
+	gammas[0].SetOne()
+	for i := 1; i < nbDigests; i++ {
+		gammas[i].Mul(&gammas[i-1], &gamma)
+	}
+	parallel.Execute(len(folded), func(start, end int) {
+		var t fr.Element
+		for i := 1; i < nbDigests; i++ {
+			for j := start; j < end; j++ {
+				t.Mul(&polynomials[i][j], &gammas[i])
+				folded[j].Add(&folded[j], &t)
+			}
+		}
+	})
+
+	proof, err := Open(folded, point, pk)
+	if err != nil {
+		return BatchOpeningProof{}, err
+	}
+	res.Quotients = proof.Quotients
+
+	return res, nil
+}
+
+// FoldProof fold the digests and the proofs in batchOpeningProof using Fiat Shamir
+// to obtain an opening proof at a single point.
+//
+// * digests list of digests on which batchOpeningProof is based
+// * batchOpeningProof opening proof of digests
+// * transcript extra data needed to derive the challenge used for folding.
+// * returns the folded version of batchOpeningProof, Digest, the folded version of digests
+func FoldProof(digests []Digest, batchOpeningProof *BatchOpeningProof, point []fr.Element, hf hash.Hash, dataTranscript ...[]byte) (OpeningProof, Digest, error) {
+
+	nbDigests := len(digests)
+
+	// check consistency between numbers of claims vs number of digests
+	if nbDigests != len(batchOpeningProof.ClaimedValues) {
+		return OpeningProof{}, Digest{}, ErrInvalidNbDigests
+	}
+	if nbDigests == 0 {
+		return OpeningProof{}, Digest{}, ErrZeroNbDigests
+	}
+
+	// derive the challenge γ, binded to the point and the commitments
+	gamma, err := deriveGamma(newGammaTranscript(hf), point, digests, batchOpeningProof.ClaimedValues, dataTranscript...)
+	if err != nil {
+		return OpeningProof{}, Digest{}, err
+	}
+
+	// fold the claimed values and digests
+	// gammai = [1,γ,γ²,..,γⁿ⁻¹]
+	gammai := make([]fr.Element, nbDigests)
+	gammai[0].SetOne()
+	for i := 1; i < nbDigests; i++ {
+		gammai[i].Mul(&gammai[i-1], &gamma)
+	}
+
+	foldedDigests, foldedEvaluations, err := fold(digests, batchOpeningProof.ClaimedValues, gammai)
+	if err != nil {
+		return OpeningProof{}, Digest{}, err
+	}
+
+	// create the folded opening proof
+	res := OpeningProof{
+		Quotients:    batchOpeningProof.Quotients,
+		ClaimedValue: foldedEvaluations,
+	}
+
+	return res, foldedDigests, nil
+}
+
+// BatchVerifySinglePoint verifies a batched opening proof at a single point of a list of polynomials.
+//
+// * digests list of digests on which opening proof is done
+// * batchOpeningProof proof of correct opening on the digests
+// * dataTranscript extra data that might be needed to derive the challenge used for the folding
+func BatchVerifySinglePoint(digests []Digest, batchOpeningProof *BatchOpeningProof, point []fr.Element, hf hash.Hash, vk VerifyingKey, dataTranscript ...[]byte) error {
+
+	// fold the proof
+	foldedProof, foldedDigest, err := FoldProof(digests, batchOpeningProof, point, hf, dataTranscript...)
+	if err != nil {
+		return err
+	}
+
+	// verify the foldedProof against the foldedDigest
+	return Verify(&foldedDigest, &foldedProof, point, vk)
+}
+
+// BatchVerifyMultiPoints batch verifies a list of opening proofs at different points.
+// The purpose of the batching is to have only one multi-pairing, with n+1 pairs,
+// for verifying several proofs. The polynomials may have different numbers of
+// variables.
+//
+// * digests list of committed polynomials
+// * proofs list of opening proofs, one for each digest
+// * points the list of points at which the opening are done
+func BatchVerifyMultiPoints(digests []Digest, proofs []OpeningProof, points [][]fr.Element, vk VerifyingKey) error {
+
+	// check consistency nb proofs vs nb digests
+	if len(digests) != len(proofs) || len(digests) != len(points) {
+		return ErrInvalidNbDigests
+	}
+
+	// len(digests) should be nonzero because of randomNumbers
+	if len(digests) == 0 {
+		return ErrZeroNbDigests
+	}
+
+	// if only one digest, call Verify
+	if len(digests) == 1 {
+		return Verify(&digests[0], &proofs[0], points[0], vk)
+	}
+
+	nbVars := len(vk.G2Tau)
+	for i := range proofs {
+		if len(points[i]) != len(proofs[i].Quotients) {
+			return ErrInvalidPointSize
+		}
+		if len(points[i]) > nbVars {
+			return ErrInvalidPolynomialSize
+		}
+	}
+
+	// sample random numbers λⱼ for sampling
+	randomNumbers := make([]fr.Element, len(digests))
+	randomNumbers[0].SetOne()
+	for i := 1; i < len(randomNumbers); i++ {
+		if _, err := randomNumbers[i].SetRandom(); err != nil {
+			return err
+		}
+	}
+
+	// ∑ⱼλⱼ[fⱼ(τ) - fⱼ(zⱼ) + ∑ᵢzⱼᵢqⱼᵢ(τ)]G₁
+	// and, for each k, ∑ⱼλⱼ[qⱼᵢ(τ)]G₁ over the quotients qⱼᵢ paired with [τₖ]G₂
+	g1Points := make([]bls24317.G1Affine, 0, len(digests)+1)
+	g1Scalars := make([]fr.Element, 0, len(digests)+1)
+	g1Points = append(g1Points, digests...)
+	g1Scalars = append(g1Scalars, randomNumbers...)
+	var foldedEvals, t fr.Element
+	for j := range proofs {
+		t.Mul(&randomNumbers[j], &proofs[j].ClaimedValue)
+		foldedEvals.Sub(&foldedEvals, &t)
+	}
+	g1Points = append(g1Points, vk.G1)
+	g1Scalars = append(g1Scalars, foldedEvals)
+
+	tauPoints := make([][]bls24317.G1Affine, nbVars)
+	tauScalars := make([][]fr.Element, nbVars)
+	for j := range proofs {
+		offset := nbVars - len(points[j])
+		for i := range proofs[j].Quotients {
+			g1Points = append(g1Points, proofs[j].Quotients[i])
+			g1Scalars = append(g1Scalars, *t.Mul(&randomNumbers[j], &points[j][i]))
+			tauPoints[offset+i] = append(tauPoints[offset+i], proofs[j].Quotients[i])
+			tauScalars[offset+i] = append(tauScalars[offset+i], randomNumbers[j])
+		}
+	}
+
+	config := ecc.MultiExpConfig{}
+	P := make([]bls24317.G1Affine, 1, nbVars+1)
+	Q := make([]bls24317.G2Affine, 1, nbVars+1)
+	if _, err := P[0].MultiExp(g1Points, g1Scalars, config); err != nil {
+		return err
+	}
+	Q[0] = vk.G2
+	for k := range tauPoints {
+		if len(tauPoints[k]) == 0 {
+			continue
+		}
+		var folded bls24317.G1Affine
+		if _, err := folded.MultiExp(tauPoints[k], tauScalars[k], config); err != nil {
+			return err
+		}
+		folded.Neg(&folded)
+		P = append(P, folded)
+		Q = append(Q, vk.G2Tau[k])
+	}
+
+	check, err := bls24317.PairingCheck(P, Q)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// fold folds digests and evaluations using the list of factors as random numbers.
+//
+// * digests list of digests to fold
+// * evaluations list of evaluations to fold
+// * factors list of multiplicative factors used for the folding (in Montgomery form)
+//
+// * Returns ∑ᵢcᵢdᵢ, ∑ᵢcᵢf(aᵢ)
+func fold(di []Digest, fai []fr.Element, ci []fr.Element) (Digest, fr.Element, error) {
+
+	// length inconsistency between digests and evaluations should have been done before calling this function
+	nbDigests := len(di)
+
+	// fold the claimed values ∑ᵢcᵢf(aᵢ)
+	var foldedEvaluations, tmp fr.Element
+	for i := 0; i < nbDigests; i++ {
+		tmp.Mul(&fai[i], &ci[i])
+		foldedEvaluations.Add(&foldedEvaluations, &tmp)
+	}
+
+	// fold the digests ∑ᵢ[cᵢ]([fᵢ(τ)]G₁)
+	var foldedDigests Digest
+	_, err := foldedDigests.MultiExp(di, ci, ecc.MultiExpConfig{})
+	if err != nil {
+		return foldedDigests, foldedEvaluations, err
+	}
+
+	// folding done
+	return foldedDigests, foldedEvaluations, nil
+
+}
+
+// deriveGamma derives a challenge using Fiat Shamir to fold proofs.
+func deriveGamma(fs transcript.Transcript, point []fr.Element, digests []Digest, claimedValues []fr.Element, dataTranscript ...[]byte) (fr.Element, error) {
+
+	// derive the challenge gamma, binded to the point and the commitments
+	if err := fs.AppendScalar("gamma", point...); err != nil {
+		return fr.Element{}, err
+	}
+	if err := fs.AppendPoint("gamma", digests...); err != nil {
+		return fr.Element{}, err
+	}
+	if err := fs.AppendScalar("gamma", claimedValues...); err != nil {
+		return fr.Element{}, err
+	}
+
+	for i := 0; i < len(dataTranscript); i++ {
+		if err := fs.AppendMessage("gamma", dataTranscript[i]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+
+	return fs.ChallengeScalar("gamma")
+}
+
+// newGammaTranscript returns the transcript deriving γ from hf in BatchOpenSinglePoint
+// and FoldProof
+func newGammaTranscript(hf hash.Hash) transcript.Transcript {
+	return transcript.NewLegacy(fiatshamir.NewTranscript(hf, "gamma"))
+}
@@ -0,0 +1,166 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package twistededwards
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestMultiExp(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	params := GetEdwardsCurve()
+
+	// naiveMultiExp computes ∑ scalars[i]⋅points[i] with scalar multiplications
+	naiveMultiExp := func(points []PointAffine, scalars []fr.Element) PointExtended {
+		var res, tmp PointExtended
+		res.setInfinity()
+		var s big.Int
+		for i := range points {
+			if points[i].IsZero() {
+				// the neutral element doesn't contribute to the sum
+				continue
+			}
+			tmp.FromAffine(&points[i])
+			tmp.ScalarMultiplication(&tmp, scalars[i].BigInt(&s))
+			res.Add(&res, &tmp)
+		}
+		return res
+	}
+
+	// randomPoints returns multiples of the base point and random scalars
+	randomPoints := func(n int, seed *big.Int) ([]PointAffine, []fr.Element) {
+		points := make([]PointAffine, n)
+		scalars := make([]fr.Element, n)
+		var s big.Int
+		s.Set(seed)
+		for i := range points {
+			points[i].ScalarMultiplication(&params.Base, &s)
+			s.Add(&s, big.NewInt(int64(i+1)))
+			scalars[i].SetRandom()
+		}
+		return points, scalars
+	}
+
+	properties.Property("[BLS24-317] MultiExp should match the sum of scalar multiplications", prop.ForAll(
+		func(n int, seed big.Int) bool {
+			points, scalars := randomPoints(n, &seed)
+
+			var res PointExtended
+			if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+				return false
+			}
+			expected := naiveMultiExp(points, scalars)
+			return res.Equal(&expected)
+		},
+		gopter.Gen(func(genParams *gopter.GenParameters) *gopter.GenResult {
+			return gopter.NewGenResult(1+int(genParams.Rng.Int63n(200)), gopter.NoShrinker)
+		}),
+		GenBigInt(),
+	))
+
+	properties.Property("[BLS24-317] MultiExp should not depend on the number of tasks", prop.ForAll(
+		func(seed big.Int) bool {
+			points, scalars := randomPoints(73, &seed)
+
+			var res1, res2 PointExtended
+			if _, err := res1.MultiExp(points, scalars, ecc.MultiExpConfig{NbTasks: 1}); err != nil {
+				return false
+			}
+			if _, err := res2.MultiExp(points, scalars, ecc.MultiExpConfig{NbTasks: 7}); err != nil {
+				return false
+			}
+			return res1.Equal(&res2)
+		},
+		GenBigInt(),
+	))
+
+	properties.Property("[BLS24-317] MultiExp with small scalars and the neutral element", prop.ForAll(
+		func(seed big.Int) bool {
+			points, scalars := randomPoints(10, &seed)
+			scalars[0].SetZero()
+			scalars[1].SetOne()
+			scalars[2].SetOne().Neg(&scalars[2])
+			scalars[3].SetUint64(1 << 40)
+			points[4].X.SetZero()
+			points[4].Y.SetOne()
+
+			var res PointExtended
+			if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+				return false
+			}
+			expected := naiveMultiExp(points, scalars)
+			return res.Equal(&expected)
+		},
+		GenBigInt(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// edge cases
+	var res PointExtended
+	if _, err := res.MultiExp(nil, nil, ecc.MultiExpConfig{}); err != nil || !res.IsZero() {
+		t.Fatal("MultiExp of no points should be the neutral element")
+	}
+	if _, err := res.MultiExp(make([]PointAffine, 2), make([]fr.Element, 1), ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("MultiExp should fail if len(points) != len(scalars)")
+	}
+	zeros := make([]fr.Element, 3)
+	points := []PointAffine{params.Base, params.Base, params.Base}
+	if _, err := res.MultiExp(points, zeros, ecc.MultiExpConfig{}); err != nil || !res.IsZero() {
+		t.Fatal("MultiExp with zero scalars should be the neutral element")
+	}
+}
+
+func BenchmarkMultiExp(b *testing.B) {
+	params := GetEdwardsCurve()
+
+	const nbPoints = 1 << 12
+	points := make([]PointAffine, nbPoints)
+	scalars := make([]fr.Element, nbPoints)
+	var s big.Int
+	for i := range points {
+		s.SetUint64(uint64(i + 1))
+		points[i].ScalarMultiplication(&params.Base, &s)
+		scalars[i].SetRandom()
+	}
+
+	var res PointExtended
+	for _, n := range []int{1 << 6, 1 << 9, 1 << 12} {
+		b.Run(fmt.Sprintf("%d points", n), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				res.MultiExp(points[:n], scalars[:n], ecc.MultiExpConfig{})
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+)
+
+var errWrongSize = errors.New("wrong size buffer")
+
+func (pk *PublicKey) size() int {
+	if pk.Suite.isMinPk() {
+		return sizeG1
+	}
+	return sizeG2
+}
+
+// Bytes returns the binary representation of the public key, that is the
+// compressed encoding of the point in G1 (min-pk) or G2 (min-sig).
+func (pk *PublicKey) Bytes() []byte {
+	if pk.Suite.isMinPk() {
+		res := pk.A.Bytes()
+		return res[:]
+	}
+	res := pk.B.Bytes()
+	return res[:]
+}
+
+// SetBytes sets pk from its compressed binary representation in buf.
+// The suite of pk must be set beforehand, as it determines the group the
+// key is read in. It returns the number of bytes read from the buffer.
+func (pk *PublicKey) SetBytes(buf []byte) (int, error) {
+	size := pk.size()
+	if len(buf) < size {
+		return 0, io.ErrShortBuffer
+	}
+	var err error
+	if pk.Suite.isMinPk() {
+		_, err = pk.A.SetBytes(buf[:size])
+	} else {
+		_, err = pk.B.SetBytes(buf[:size])
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !pk.isValid() {
+		return 0, errInvalidPublicKey
+	}
+	return size, nil
+}
+
+// Bytes returns the binary representation of pk,
+// as byte array publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+func (privKey *PrivateKey) Bytes() []byte {
+	pubkBin := privKey.PublicKey.Bytes()
+	res := make([]byte, len(pubkBin)+sizeFr)
+	subtle.ConstantTimeCopy(1, res[:len(pubkBin)], pubkBin)
+	subtle.ConstantTimeCopy(1, res[len(pubkBin):], privKey.scalar[:])
+	return res
+}
+
+// SetBytes sets pk from buf, where buf is interpreted
+// as  publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+// The suite of privKey.PublicKey must be set beforehand.
+// It returns the number byte read.
+func (privKey *PrivateKey) SetBytes(buf []byte) (int, error) {
+	size := privKey.PublicKey.size()
+	if len(buf) < size+sizeFr {
+		return 0, io.ErrShortBuffer
+	}
+	n, err := privKey.PublicKey.SetBytes(buf[:size])
+	if err != nil {
+		return 0, err
+	}
+	subtle.ConstantTimeCopy(1, privKey.scalar[:], buf[n:n+sizeFr])
+	n += sizeFr
+	return n, nil
+}
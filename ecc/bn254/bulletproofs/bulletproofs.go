@@ -0,0 +1,557 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bulletproofs
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/big"
+	"math/bits"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidNbBits    = errors.New("the number of bits must be 8, 16, 32 or 64")
+	ErrInvalidNbValues  = errors.New("the number of values must be a power of two, at most the one of the public parameters")
+	ErrLengthMismatch   = errors.New("the number of values and of blinding factors differ")
+	ErrValueOutOfRange  = errors.New("value out of range")
+	ErrVerifyRangeProof = errors.New("can't verify range proof")
+)
+
+// domain separation tag used to hash the generators to the curve
+const generatorsDST = "BULLETPROOFS-GENERATORS-BN254"
+
+// PublicParameters of the range proofs: the Pedersen bases of the
+// commitments, and the bases of the vector commitments of the bits.
+type PublicParameters struct {
+	// NbBits is the size of the range [0, 2^NbBits)
+	NbBits int
+
+	// G, H bases of the Pedersen commitments v⋅G + γ⋅H
+	G, H curve.G1Affine
+
+	// Gs, Hs bases of the vector commitments, of size NbBits times the
+	// maximal number of aggregated values
+	Gs, Hs []curve.G1Affine
+}
+
+// Proof is an aggregated range proof
+type Proof struct {
+	// A, S commitments to the bits of the values and to the blinding vectors
+	A, S curve.G1Affine
+
+	// T1, T2 commitments to the coefficients of t(X) = <l(X), r(X)>
+	T1, T2 curve.G1Affine
+
+	// TauX blinding factor of t(x), Mu blinding factor of A + x⋅S
+	TauX, Mu fr.Element
+
+	// THat evaluation t(x)
+	THat fr.Element
+
+	// IPA proves that THat = <l(x), r(x)>
+	IPA InnerProductProof
+}
+
+// Setup returns the public parameters to prove that up to maxNbValues
+// values lie in [0, 2^nbBits). All the generators are derived by hashing to
+// the curve, so that their discrete logarithms are unknown: no trusted setup
+// is needed.
+func Setup(nbBits, maxNbValues int) (PublicParameters, error) {
+	if nbBits != 8 && nbBits != 16 && nbBits != 32 && nbBits != 64 {
+		return PublicParameters{}, ErrInvalidNbBits
+	}
+	if maxNbValues < 1 || bits.OnesCount(uint(maxNbValues)) != 1 {
+		return PublicParameters{}, ErrInvalidNbValues
+	}
+
+	pp := PublicParameters{NbBits: nbBits}
+	var err error
+	if pp.G, err = hashToGenerator("G", 0); err != nil {
+		return PublicParameters{}, err
+	}
+	if pp.H, err = hashToGenerator("H", 0); err != nil {
+		return PublicParameters{}, err
+	}
+
+	n := nbBits * maxNbValues
+	pp.Gs = make([]curve.G1Affine, n)
+	pp.Hs = make([]curve.G1Affine, n)
+	chErr := make(chan error, 1)
+	parallel.Execute(n, func(start, end int) {
+		var err error
+		for i := start; i < end; i++ {
+			if pp.Gs[i], err = hashToGenerator("G", uint32(i+1)); err != nil {
+				break
+			}
+			if pp.Hs[i], err = hashToGenerator("H", uint32(i+1)); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			select {
+			case chErr <- err:
+			default:
+			}
+		}
+	})
+	close(chErr)
+	if err := <-chErr; err != nil {
+		return PublicParameters{}, err
+	}
+
+	return pp, nil
+}
+
+// hashToGenerator hashes label ∥ index to the curve
+func hashToGenerator(label string, index uint32) (curve.G1Affine, error) {
+	msg := make([]byte, len(label)+4)
+	copy(msg, label)
+	binary.BigEndian.PutUint32(msg[len(label):], index)
+	return curve.HashToG1(msg, []byte(generatorsDST))
+}
+
+// Commit returns the Pedersen commitment value⋅G + blinding⋅H
+func (pp *PublicParameters) Commit(value uint64, blinding fr.Element) curve.G1Affine {
+	var v, b big.Int
+	v.SetUint64(value)
+	blinding.BigInt(&b)
+
+	var res curve.G1Jac
+	res.JointScalarMultiplication(&pp.G, &pp.H, &v, &b)
+
+	var commitment curve.G1Affine
+	commitment.FromJacobian(&res)
+	return commitment
+}
+
+// Prove returns a proof that the values lie in [0, 2^pp.NbBits), along with
+// the commitments pp.Commit(values[j], blindings[j]) it is a proof for. The
+// number of values must be a power of two.
+func Prove(pp *PublicParameters, values []uint64, blindings []fr.Element, hf hash.Hash) (Proof, []curve.G1Affine, error) {
+	m := len(values)
+	if len(blindings) != m {
+		return Proof{}, nil, ErrLengthMismatch
+	}
+	if err := pp.checkNbValues(m); err != nil {
+		return Proof{}, nil, err
+	}
+	n := pp.NbBits
+	N := n * m
+	for _, v := range values {
+		if n < 64 && v>>n != 0 {
+			return Proof{}, nil, ErrValueOutOfRange
+		}
+	}
+
+	commitments := make([]curve.G1Affine, m)
+	for j := range values {
+		commitments[j] = pp.Commit(values[j], blindings[j])
+	}
+
+	var proof Proof
+	fs := newTranscript(hf, N)
+
+	// aL = bits of the values, aR = aL - 1
+	aL := make([]fr.Element, N)
+	aR := make([]fr.Element, N)
+	var one fr.Element
+	one.SetOne()
+	for j := range values {
+		for i := 0; i < n; i++ {
+			if (values[j]>>i)&1 == 1 {
+				aL[j*n+i].SetOne()
+			} else {
+				aR[j*n+i].Neg(&one)
+			}
+		}
+	}
+
+	// blinding vectors and factors
+	sL := make([]fr.Element, N)
+	sR := make([]fr.Element, N)
+	for i := 0; i < N; i++ {
+		if _, err := sL[i].SetRandom(); err != nil {
+			return Proof{}, nil, err
+		}
+		if _, err := sR[i].SetRandom(); err != nil {
+			return Proof{}, nil, err
+		}
+	}
+	var alpha, rho, tau1, tau2 fr.Element
+	for _, r := range []*fr.Element{&alpha, &rho, &tau1, &tau2} {
+		if _, err := r.SetRandom(); err != nil {
+			return Proof{}, nil, err
+		}
+	}
+
+	// A = α⋅H + <aL, Gs> + <aR, Hs>, S = ρ⋅H + <sL, Gs> + <sR, Hs>
+	var err error
+	if proof.A, err = pp.vectorCommit(&alpha, aL, aR); err != nil {
+		return Proof{}, nil, err
+	}
+	if proof.S, err = pp.vectorCommit(&rho, sL, sR); err != nil {
+		return Proof{}, nil, err
+	}
+
+	y, z, err := deriveYZ(fs, n, commitments, &proof.A, &proof.S)
+	if err != nil {
+		return Proof{}, nil, err
+	}
+
+	// l(X) = (aL - z⋅1) + sL⋅X
+	// r(X) = yᴺ ∘ (aR + z⋅1 + sR⋅X) + ∑ⱼ z²⁺ʲ⋅(0ʲⁿ ∥ 2ⁿ ∥ 0⁽ᵐ⁻ʲ⁻¹⁾ⁿ)
+	l0 := make([]fr.Element, N)
+	r0 := make([]fr.Element, N)
+	r1 := make([]fr.Element, N)
+	var yi, zj, twoI, tmp fr.Element
+	yi.SetOne()
+	zj.Square(&z)
+	for j := 0; j < m; j++ {
+		twoI.SetOne()
+		for i := 0; i < n; i++ {
+			k := j*n + i
+			l0[k].Sub(&aL[k], &z)
+			r0[k].Add(&aR[k], &z).Mul(&r0[k], &yi)
+			tmp.Mul(&zj, &twoI)
+			r0[k].Add(&r0[k], &tmp)
+			r1[k].Mul(&sR[k], &yi)
+
+			yi.Mul(&yi, &y)
+			twoI.Double(&twoI)
+		}
+		zj.Mul(&zj, &z)
+	}
+
+	// t(X) = <l(X), r(X)> = t0 + t1⋅X + t2⋅X²
+	var t1, t2 fr.Element
+	t1 = innerProduct(l0, r1)
+	tmp = innerProduct(sL, r0)
+	t1.Add(&t1, &tmp)
+	t2 = innerProduct(sL, r1)
+
+	// T1 = t1⋅G + τ₁⋅H, T2 = t2⋅G + τ₂⋅H
+	var t1BigInt, t2BigInt, tau1BigInt, tau2BigInt big.Int
+	t1.BigInt(&t1BigInt)
+	t2.BigInt(&t2BigInt)
+	tau1.BigInt(&tau1BigInt)
+	tau2.BigInt(&tau2BigInt)
+	var T curve.G1Jac
+	T.JointScalarMultiplication(&pp.G, &pp.H, &t1BigInt, &tau1BigInt)
+	proof.T1.FromJacobian(&T)
+	T.JointScalarMultiplication(&pp.G, &pp.H, &t2BigInt, &tau2BigInt)
+	proof.T2.FromJacobian(&T)
+
+	x, err := deriveChallenge(fs, "x", pointBytes(&proof.T1), pointBytes(&proof.T2))
+	if err != nil {
+		return Proof{}, nil, err
+	}
+
+	// l = l(x), r = r(x), THat = <l, r>
+	l := make([]fr.Element, N)
+	r := make([]fr.Element, N)
+	for i := 0; i < N; i++ {
+		l[i].Mul(&sL[i], &x).Add(&l[i], &l0[i])
+		r[i].Mul(&r1[i], &x).Add(&r[i], &r0[i])
+	}
+	proof.THat = innerProduct(l, r)
+
+	// TauX = τ₂⋅x² + τ₁⋅x + ∑ⱼ z²⁺ʲ⋅γⱼ, Mu = α + ρ⋅x
+	proof.TauX.Mul(&tau2, &x).Add(&proof.TauX, &tau1).Mul(&proof.TauX, &x)
+	zj.Square(&z)
+	for j := 0; j < m; j++ {
+		tmp.Mul(&zj, &blindings[j])
+		proof.TauX.Add(&proof.TauX, &tmp)
+		zj.Mul(&zj, &z)
+	}
+	proof.Mu.Mul(&rho, &x).Add(&proof.Mu, &alpha)
+
+	w, err := deriveChallenge(fs, "w", proof.TauX.Marshal(), proof.Mu.Marshal(), proof.THat.Marshal())
+	if err != nil {
+		return Proof{}, nil, err
+	}
+
+	// the inner product argument is run with the bases Gs and H' = y⁻ⁱ⋅Hs,
+	// and Q = w⋅G
+	var Q curve.G1Affine
+	var wBigInt big.Int
+	Q.ScalarMultiplication(&pp.G, w.BigInt(&wBigInt))
+
+	yInv := make([]fr.Element, N)
+	yInv[0].SetOne()
+	if N > 1 {
+		yInv[1].Inverse(&y)
+	}
+	for i := 2; i < N; i++ {
+		yInv[i].Mul(&yInv[i-1], &yInv[1])
+	}
+	HPrime := scalePoints(pp.Hs[:N], yInv)
+
+	proof.IPA, err = proveInnerProduct(fs, pp.Gs[:N], HPrime, &Q, l, r)
+	if err != nil {
+		return Proof{}, nil, err
+	}
+
+	return proof, commitments, nil
+}
+
+// Verify verifies that proof proves that the values committed to in
+// commitments lie in [0, 2^pp.NbBits).
+func Verify(pp *PublicParameters, proof *Proof, commitments []curve.G1Affine, hf hash.Hash) error {
+	m := len(commitments)
+	if err := pp.checkNbValues(m); err != nil {
+		return err
+	}
+	n := pp.NbBits
+	N := n * m
+	nbRounds := bits.TrailingZeros(uint(N))
+	if len(proof.IPA.L) != nbRounds || len(proof.IPA.R) != nbRounds {
+		return ErrVerifyRangeProof
+	}
+
+	fs := newTranscript(hf, N)
+	y, z, err := deriveYZ(fs, n, commitments, &proof.A, &proof.S)
+	if err != nil {
+		return err
+	}
+	x, err := deriveChallenge(fs, "x", pointBytes(&proof.T1), pointBytes(&proof.T2))
+	if err != nil {
+		return err
+	}
+	w, err := deriveChallenge(fs, "w", proof.TauX.Marshal(), proof.Mu.Marshal(), proof.THat.Marshal())
+	if err != nil {
+		return err
+	}
+	u, err := deriveRoundChallenges(fs, &proof.IPA)
+	if err != nil {
+		return err
+	}
+
+	// powers of y and z
+	yPowers := make([]fr.Element, N)
+	yPowers[0].SetOne()
+	for i := 1; i < N; i++ {
+		yPowers[i].Mul(&yPowers[i-1], &y)
+	}
+	zPowers := make([]fr.Element, m+3)
+	zPowers[0].SetOne()
+	for j := 1; j < len(zPowers); j++ {
+		zPowers[j].Mul(&zPowers[j-1], &z)
+	}
+
+	// first check: THat⋅G + TauX⋅H = ∑ⱼ z²⁺ʲ⋅Vⱼ + δ(y, z)⋅G + x⋅T1 + x²⋅T2
+	// where δ(y, z) = (z - z²)⋅<1, yᴺ> - ∑ⱼ z³⁺ʲ⋅<1, 2ⁿ>
+	var delta, sumY, sumTwo, tmp fr.Element
+	for i := range yPowers {
+		sumY.Add(&sumY, &yPowers[i])
+	}
+	// <1, 2ⁿ> = 2ⁿ - 1
+	sumTwo.SetOne()
+	for i := 0; i < n; i++ {
+		sumTwo.Double(&sumTwo)
+	}
+	sumTwo.Sub(&sumTwo, &zPowers[0])
+	delta.Sub(&z, &zPowers[2]).Mul(&delta, &sumY)
+	for j := 0; j < m; j++ {
+		tmp.Mul(&zPowers[3+j], &sumTwo)
+		delta.Sub(&delta, &tmp)
+	}
+
+	points := make([]curve.G1Affine, 0, m+4)
+	scalars := make([]fr.Element, 0, m+4)
+	points = append(points, commitments...)
+	for j := 0; j < m; j++ {
+		scalars = append(scalars, zPowers[2+j])
+	}
+	var x2, gCoeff, hCoeff fr.Element
+	x2.Square(&x)
+	gCoeff.Sub(&delta, &proof.THat)
+	hCoeff.Neg(&proof.TauX)
+	points = append(points, proof.T1, proof.T2, pp.G, pp.H)
+	scalars = append(scalars, x, x2, gCoeff, hCoeff)
+	ok, err := isZero(points, scalars)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVerifyRangeProof
+	}
+
+	// second check, the inner product argument on
+	// P = A + x⋅S - z⋅<1, Gs> + <z⋅yᴺ + ∑ⱼ z²⁺ʲ⋅(0ʲⁿ ∥ 2ⁿ ∥ 0⁽ᵐ⁻ʲ⁻¹⁾ⁿ), H'> - Mu⋅H
+	// with H' = y⁻ⁱ⋅Hs, and Q = w⋅G. The folded bases are <s, Gs> and
+	// <s⁻¹, H'>, so that it amounts to
+	// P + THat⋅Q + ∑ₖ (uₖ²⋅Lₖ + uₖ⁻²⋅Rₖ) - a⋅<s, Gs> - b⋅<s⁻¹, H'> - a⋅b⋅Q = 0
+	s := foldingCoefficients(u)
+	sInv := fr.BatchInvert(s)
+	yInv := fr.BatchInvert(yPowers)
+
+	points = make([]curve.G1Affine, 0, 2*N+2*nbRounds+4)
+	scalars = make([]fr.Element, 0, 2*N+2*nbRounds+4)
+
+	points = append(points, pp.Gs[:N]...)
+	for i := 0; i < N; i++ {
+		tmp.Mul(&proof.IPA.A, &s[i]).Add(&tmp, &z).Neg(&tmp)
+		scalars = append(scalars, tmp)
+	}
+	points = append(points, pp.Hs[:N]...)
+	var twoI fr.Element
+	for j := 0; j < m; j++ {
+		twoI.SetOne()
+		for i := 0; i < n; i++ {
+			k := j*n + i
+			tmp.Mul(&zPowers[2+j], &twoI)
+			var bs fr.Element
+			bs.Mul(&proof.IPA.B, &sInv[k])
+			tmp.Sub(&tmp, &bs).Mul(&tmp, &yInv[k]).Add(&tmp, &z)
+			scalars = append(scalars, tmp)
+			twoI.Double(&twoI)
+		}
+	}
+	for k := 0; k < nbRounds; k++ {
+		var uSquare, uInvSquare fr.Element
+		uSquare.Square(&u[k])
+		uInvSquare.Inverse(&uSquare)
+		points = append(points, proof.IPA.L[k], proof.IPA.R[k])
+		scalars = append(scalars, uSquare, uInvSquare)
+	}
+	var one, muNeg fr.Element
+	one.SetOne()
+	muNeg.Neg(&proof.Mu)
+	// THat⋅Q - a⋅b⋅Q = w⋅(THat - a⋅b)⋅G
+	gCoeff.Mul(&proof.IPA.A, &proof.IPA.B).Sub(&proof.THat, &gCoeff).Mul(&gCoeff, &w)
+	points = append(points, proof.A, proof.S, pp.H, pp.G)
+	scalars = append(scalars, one, x, muNeg, gCoeff)
+
+	if ok, err = isZero(points, scalars); err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVerifyRangeProof
+	}
+
+	return nil
+}
+
+// checkNbValues checks that m values can be aggregated with pp
+func (pp *PublicParameters) checkNbValues(m int) error {
+	if m < 1 || bits.OnesCount(uint(m)) != 1 || m*pp.NbBits > len(pp.Gs) || len(pp.Hs) != len(pp.Gs) {
+		return ErrInvalidNbValues
+	}
+	return nil
+}
+
+// vectorCommit returns blinding⋅H + <a, Gs> + <b, Hs>
+func (pp *PublicParameters) vectorCommit(blinding *fr.Element, a, b []fr.Element) (curve.G1Affine, error) {
+	N := len(a)
+	points := make([]curve.G1Affine, 0, 2*N+1)
+	points = append(points, pp.Gs[:N]...)
+	points = append(points, pp.Hs[:N]...)
+	points = append(points, pp.H)
+	scalars := make([]fr.Element, 0, 2*N+1)
+	scalars = append(scalars, a...)
+	scalars = append(scalars, b...)
+	scalars = append(scalars, *blinding)
+
+	var res curve.G1Affine
+	_, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{})
+	return res, err
+}
+
+// isZero returns true if ∑ scalars[i]⋅points[i] is the point at infinity
+func isZero(points []curve.G1Affine, scalars []fr.Element) (bool, error) {
+	var res curve.G1Jac
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false, err
+	}
+	return res.Z.IsZero(), nil
+}
+
+// newTranscript returns the Fiat-Shamir transcript of a proof for N bits in
+// total
+func newTranscript(hf hash.Hash, N int) *fiatshamir.Transcript {
+	nbRounds := bits.TrailingZeros(uint(N))
+	challenges := make([]string, 0, 4+nbRounds)
+	challenges = append(challenges, "y", "z", "x", "w")
+	for k := 0; k < nbRounds; k++ {
+		challenges = append(challenges, roundChallengeID(k))
+	}
+	return fiatshamir.NewTranscript(hf, challenges...)
+}
+
+func roundChallengeID(round int) string {
+	return "u" + strconv.Itoa(round)
+}
+
+// deriveYZ derives the challenges y and z, binded to the statement and to A
+// and S
+func deriveYZ(fs *fiatshamir.Transcript, nbBits int, commitments []curve.G1Affine, A, S *curve.G1Affine) (y, z fr.Element, err error) {
+	var sizes [16]byte
+	binary.BigEndian.PutUint64(sizes[:8], uint64(nbBits))
+	binary.BigEndian.PutUint64(sizes[8:], uint64(len(commitments)))
+	if err = fs.Bind("y", sizes[:]); err != nil {
+		return
+	}
+	for i := range commitments {
+		if err = fs.Bind("y", pointBytes(&commitments[i])); err != nil {
+			return
+		}
+	}
+	if y, err = deriveChallenge(fs, "y", pointBytes(A), pointBytes(S)); err != nil {
+		return
+	}
+	z, err = deriveChallenge(fs, "z")
+	return
+}
+
+// deriveChallenge binds the values to the challenge and computes it
+func deriveChallenge(fs *fiatshamir.Transcript, challengeID string, bindings ...[]byte) (fr.Element, error) {
+	for i := range bindings {
+		if err := fs.Bind(challengeID, bindings[i]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	bChallenge, err := fs.ComputeChallenge(challengeID)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var res fr.Element
+	res.SetBytes(bChallenge)
+	return res, nil
+}
+
+// pointBytes returns the uncompressed encoding of p, binded to the transcript
+func pointBytes(p *curve.G1Affine) []byte {
+	b := p.RawBytes()
+	return b[:]
+}
+
+func innerProduct(a, b []fr.Element) fr.Element {
+	var res, tmp fr.Element
+	for i := range a {
+		tmp.Mul(&a[i], &b[i])
+		res.Add(&res, &tmp)
+	}
+	return res
+}
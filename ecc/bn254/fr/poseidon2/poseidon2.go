@@ -0,0 +1,315 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon2
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	fieldhash "github.com/consensys/gnark-crypto/field/hash"
+)
+
+const (
+	// DefaultWidth is the width of the permutation used by the hash function
+	DefaultWidth = 3
+	// DefaultNbFullRounds is the number of full rounds for 128 bits of security.
+	DefaultNbFullRounds = 8
+	// DefaultNbPartialRounds is the number of partial rounds for 128 bits of security.
+	DefaultNbPartialRounds = 56
+	// SBoxDegree is the degree α of the S-box x ↦ x^α, the smallest integer
+	// such that gcd(α, r-1) = 1.
+	SBoxDegree = 5
+	// BlockSize is the size in bytes of the blocks consumed by the compression function.
+	BlockSize = fr.Bytes
+)
+
+var (
+	ErrInvalidSizebuffer = errors.New("the size of the input should match the size of the hash buffer")
+	errInvalidWidth      = errors.New("the width should be 2, 3 or a multiple of 4")
+)
+
+// Parameters describe the Poseidon2 permutation
+type Parameters struct {
+	// Width is the number of field elements of the state, 2, 3 or a
+	// multiple of 4
+	Width int
+
+	// NbFullRounds is the number of full rounds, split evenly at the
+	// beginning and at the end of the permutation
+	NbFullRounds int
+
+	// NbPartialRounds is the number of partial rounds, where the S-box is
+	// only applied to the first element of the state
+	NbPartialRounds int
+
+	// RoundKeys are the round constants: Width of them for full rounds, a
+	// single one for partial rounds
+	RoundKeys [][]fr.Element
+
+	// DiagInternal is the diagonal D of the internal matrix J + D, where J
+	// is the all-ones matrix, used in the partial rounds
+	DiagInternal []fr.Element
+}
+
+// NewParameters returns the parameters of the Poseidon2 permutation of the
+// given width and number of rounds, or an error if the width is not supported.
+//
+// The round constants are derived from the Grain LFSR as in the reference
+// implementation. For widths 2 and 3, the internal matrices are those of the
+// reference implementation ([[2,1],[1,3]] and [[2,1,1],[1,2,1],[1,1,3]]). For
+// larger widths, the diagonal of the internal matrix is sampled from the
+// Grain LFSR after the round constants; applications targeting an existing
+// instance should set DiagInternal to the reference values.
+func NewParameters(width, nbFullRounds, nbPartialRounds int) (*Parameters, error) {
+	if width != 2 && width != 3 && (width == 0 || width%4 != 0) {
+		return nil, errInvalidWidth
+	}
+	p := &Parameters{
+		Width:           width,
+		NbFullRounds:    nbFullRounds,
+		NbPartialRounds: nbPartialRounds,
+	}
+	grain := fieldhash.NewGrainLFSR(fr.Modulus(), width, nbFullRounds, nbPartialRounds)
+
+	rf := nbFullRounds / 2
+	p.RoundKeys = make([][]fr.Element, nbFullRounds+nbPartialRounds)
+	for i := range p.RoundKeys {
+		if i >= rf && i < rf+nbPartialRounds {
+			p.RoundKeys[i] = make([]fr.Element, 1)
+		} else {
+			p.RoundKeys[i] = make([]fr.Element, width)
+		}
+		for j := range p.RoundKeys[i] {
+			p.RoundKeys[i][j].SetBigInt(grain.NextFieldElement())
+		}
+	}
+
+	p.DiagInternal = make([]fr.Element, width)
+	switch width {
+	case 2:
+		p.DiagInternal[0].SetOne()
+		p.DiagInternal[1].SetUint64(2)
+	case 3:
+		p.DiagInternal[0].SetOne()
+		p.DiagInternal[1].SetOne()
+		p.DiagInternal[2].SetUint64(2)
+	default:
+		for i := range p.DiagInternal {
+			for p.DiagInternal[i].IsZero() {
+				p.DiagInternal[i].SetBigInt(grain.NextFieldElement())
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// String returns a string representation of the parameters
+func (p *Parameters) String() string {
+	return fmt.Sprintf("Poseidon2-BN254[t=%d,rF=%d,rP=%d,d=%d]", p.Width, p.NbFullRounds, p.NbPartialRounds, SBoxDegree)
+}
+
+var (
+	defaultParameters     *Parameters
+	defaultParametersOnce sync.Once
+)
+
+// GetDefaultParameters returns the parameters of width DefaultWidth and
+// default number of rounds, computed once.
+func GetDefaultParameters() *Parameters {
+	defaultParametersOnce.Do(func() {
+		// the default width is supported
+		defaultParameters, _ = NewParameters(DefaultWidth, DefaultNbFullRounds, DefaultNbPartialRounds)
+	})
+	return defaultParameters
+}
+
+// Permutation stores the buffer of the Poseidon2 permutation and provides
+// Poseidon2 permutation methods on the buffer
+type Permutation struct {
+	params *Parameters
+}
+
+// NewPermutation returns a new Poseidon2 permutation instance.
+func NewPermutation(width, nbFullRounds, nbPartialRounds int) (*Permutation, error) {
+	params, err := NewParameters(width, nbFullRounds, nbPartialRounds)
+	if err != nil {
+		return nil, err
+	}
+	return NewPermutationWithParameters(params), nil
+}
+
+// NewPermutationWithParameters returns a new Poseidon2 permutation instance
+// with the given parameters.
+func NewPermutationWithParameters(params *Parameters) *Permutation {
+	return &Permutation{params: params}
+}
+
+// Parameters returns the parameters of the permutation
+func (h *Permutation) Parameters() *Parameters {
+	return h.params
+}
+
+// sBox applies the S-box x ↦ x^α to x
+func (h *Permutation) sBox(x *fr.Element) {
+	var tmp fr.Element
+	tmp.Set(x)
+	x.Square(x).
+		Square(x).
+		Mul(x, &tmp)
+}
+
+// matMulM4InPlace computes
+// s <- M4*s
+// where M4=
+// (5 7 1 3)
+// (4 6 1 1)
+// (1 3 5 7)
+// (1 1 4 6)
+// on chunks of 4 elements on each part of the buffer
+// see https://eprint.iacr.org/2023/323.pdf appendix B for the addition chain
+func (h *Permutation) matMulM4InPlace(s []fr.Element) {
+	c := len(s) / 4
+	for i := 0; i < c; i++ {
+		var t0, t1, t2, t3, t4, t5, t6, t7 fr.Element
+		t0.Add(&s[4*i], &s[4*i+1])               // s0+s1
+		t1.Add(&s[4*i+2], &s[4*i+3])             // s2+s3
+		t2.Double(&s[4*i+1]).Add(&t2, &t1)       // 2s1+t1
+		t3.Double(&s[4*i+3]).Add(&t3, &t0)       // 2s3+t0
+		t4.Double(&t1).Double(&t4).Add(&t4, &t3) // 4t1+t3
+		t5.Double(&t0).Double(&t5).Add(&t5, &t2) // 4t0+t2
+		t6.Add(&t3, &t5)                         // t3+t5
+		t7.Add(&t2, &t4)                         // t2+t4
+		s[4*i].Set(&t6)
+		s[4*i+1].Set(&t5)
+		s[4*i+2].Set(&t7)
+		s[4*i+3].Set(&t4)
+	}
+}
+
+// matMulExternalInPlace applies the external matrix: circ(2,1) or circ(2,1,1)
+// for widths 2 and 3, M4 for width 4, and circ(2M4, M4, …, M4) for larger
+// multiples of 4.
+func (h *Permutation) matMulExternalInPlace(input []fr.Element) {
+	switch h.params.Width {
+	case 2, 3:
+		var sum fr.Element
+		for i := range input {
+			sum.Add(&sum, &input[i])
+		}
+		for i := range input {
+			input[i].Add(&input[i], &sum)
+		}
+	case 4:
+		h.matMulM4InPlace(input)
+	default:
+		h.matMulM4InPlace(input)
+		var sums [4]fr.Element
+		for i := range input {
+			sums[i%4].Add(&sums[i%4], &input[i])
+		}
+		for i := range input {
+			input[i].Add(&input[i], &sums[i%4])
+		}
+	}
+}
+
+// matMulInternalInPlace applies the internal matrix J + D
+func (h *Permutation) matMulInternalInPlace(input []fr.Element) {
+	var sum, tmp fr.Element
+	for i := range input {
+		sum.Add(&sum, &input[i])
+	}
+	for i := range input {
+		tmp.Mul(&input[i], &h.params.DiagInternal[i])
+		input[i].Add(&sum, &tmp)
+	}
+}
+
+func (h *Permutation) addRoundKeyInPlace(round int, input []fr.Element) {
+	for i := range h.params.RoundKeys[round] {
+		input[i].Add(&input[i], &h.params.RoundKeys[round][i])
+	}
+}
+
+// Permutation applies the permutation on input, and stores the result in input.
+func (h *Permutation) Permutation(input []fr.Element) error {
+	if len(input) != h.params.Width {
+		return ErrInvalidSizebuffer
+	}
+
+	// external matrix multiplication, aka initial linear layer
+	h.matMulExternalInPlace(input)
+
+	rf := h.params.NbFullRounds / 2
+	round := 0
+	for i := 0; i < rf; i++ {
+		h.addRoundKeyInPlace(round, input)
+		for j := range input {
+			h.sBox(&input[j])
+		}
+		h.matMulExternalInPlace(input)
+		round++
+	}
+	for i := 0; i < h.params.NbPartialRounds; i++ {
+		h.addRoundKeyInPlace(round, input)
+		h.sBox(&input[0])
+		h.matMulInternalInPlace(input)
+		round++
+	}
+	for i := 0; i < rf; i++ {
+		h.addRoundKeyInPlace(round, input)
+		for j := range input {
+			h.sBox(&input[j])
+		}
+		h.matMulExternalInPlace(input)
+		round++
+	}
+
+	return nil
+}
+
+// Compress is used in context of Merkle trees: it hashes the two children
+// left and right into their parent, using the compression mode of Poseidon2
+//
+//	Compress(left, right) = (P(left, right, 0, …) + (left, right, 0, …))[0]
+func (h *Permutation) Compress(left []byte, right []byte) ([]byte, error) {
+	if len(left) != BlockSize || len(right) != BlockSize {
+		return nil, ErrInvalidSizebuffer
+	}
+	x := make([]fr.Element, h.params.Width)
+	if err := x[0].SetBytesCanonical(left); err != nil {
+		return nil, err
+	}
+	if err := x[1].SetBytesCanonical(right); err != nil {
+		return nil, err
+	}
+	l := x[0]
+	if err := h.Permutation(x); err != nil {
+		return nil, err
+	}
+	x[0].Add(&x[0], &l)
+	res := x[0].Bytes()
+	return res[:], nil
+}
+
+// BlockSize returns the size in bytes of the inputs of Compress
+func (h *Permutation) BlockSize() int {
+	return BlockSize
+}
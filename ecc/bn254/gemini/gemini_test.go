@@ -0,0 +1,113 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package gemini
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/polynomial"
+	"github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+
+	"github.com/consensys/gnark-crypto/utils/testutils"
+)
+
+// Test SRS re-used across tests
+var testSrs *kzg.SRS
+
+func init() {
+	testSrs, _ = kzg.NewSRS(1<<8, big.NewInt(42))
+}
+
+func randomMultiLin(nbVars int) polynomial.MultiLin {
+	res := make(polynomial.MultiLin, 1<<nbVars)
+	for i := range res {
+		res[i].SetRandom()
+	}
+	return res
+}
+
+func randomPoint(nbVars int) []fr.Element {
+	res := make([]fr.Element, nbVars)
+	for i := range res {
+		res[i].SetRandom()
+	}
+	return res
+}
+
+func TestOpen(t *testing.T) {
+	assert := require.New(t)
+
+	hf := sha256.New()
+	for _, nbVars := range []int{1, 2, 5, 7} {
+
+		f := randomMultiLin(nbVars)
+		digest, err := Commit(f, testSrs.Pk)
+		assert.NoError(err)
+
+		point := randomPoint(nbVars)
+		value := f.Evaluate(point, nil)
+		proof, err := Open(f, point, value, digest, hf, testSrs.Pk, []byte("data"))
+		assert.NoError(err)
+
+		// verify correct proof
+		assert.NoError(Verify(&digest, &proof, point, value, hf, testSrs.Vk, []byte("data")))
+
+		t.Run("serialization", testutils.SerializationRoundTrip(&proof))
+
+		// verify with different transcript data
+		assert.Error(Verify(&digest, &proof, point, value, hf, testSrs.Vk, []byte("other data")))
+
+		// verify wrong claimed value
+		var wrongValue fr.Element
+		wrongValue.Double(&value)
+		assert.Error(Verify(&digest, &proof, point, wrongValue, hf, testSrs.Vk, []byte("data")))
+
+		// verify wrong point
+		wrongPoint := make([]fr.Element, nbVars)
+		copy(wrongPoint, point)
+		wrongPoint[0].Double(&wrongPoint[0])
+		assert.Error(Verify(&digest, &proof, wrongPoint, value, hf, testSrs.Vk, []byte("data")))
+
+		// verify wrong opened value
+		proof.Opening.ClaimedValues[0][0].Double(&proof.Opening.ClaimedValues[0][0])
+		assert.Error(Verify(&digest, &proof, point, value, hf, testSrs.Vk, []byte("data")))
+
+		// proving a wrong claimed value fails
+		_, err = Open(f, point, wrongValue, digest, hf, testSrs.Pk)
+		assert.ErrorIs(err, ErrClaimedValue)
+	}
+}
+
+func TestOpenInvalidSize(t *testing.T) {
+	assert := require.New(t)
+
+	hf := sha256.New()
+	var value fr.Element
+	digest := testSrs.Vk.G1
+
+	_, err := Open(randomMultiLin(8), randomPoint(8), value, digest, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+	_, err = Open(make(polynomial.MultiLin, 3), randomPoint(2), value, digest, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+	_, err = Open(randomMultiLin(3), randomPoint(2), value, digest, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPointSize)
+}
@@ -0,0 +1,196 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"golang.org/x/crypto/blake2b"
+)
+
+var (
+	ErrInvalidIgnitionTranscript = errors.New("invalid ignition transcript")
+	ErrIgnitionChecksum          = errors.New("the checksum of the ignition transcript does not match its content")
+	ErrIgnitionTooSmall          = errors.New("the ignition transcripts have less powers than the requested SRS size")
+)
+
+// ignitionManifest header of an Aztec Ignition transcript
+type ignitionManifest struct {
+	TranscriptNumber uint32
+	TotalTranscripts uint32
+	TotalG1Points    uint32
+	TotalG2Points    uint32
+	NumG1Points      uint32
+	NumG2Points      uint32
+	StartFrom        uint32
+}
+
+// ImportIgnition returns the SRS of size size made of the powers of τ of the
+// Aztec Ignition ceremony, read from the consecutive transcripts (transcript00.dat,
+// transcript01.dat, ...), after checking it with VerifySRS. Only the transcripts
+// needed for size powers must be given; the checksum of each of them is
+// verified, but not the contributions.
+//
+// A transcript is made of a manifest, its points of G₁ [τⁱ]G₁ for
+// i ∈ [startFrom+1, startFrom+numG1Points], its points of G₂ ([τ]G₂ for the
+// first transcript) and a BLAKE2b checksum of all of the above. The integers of
+// the manifest are big endian, the coordinates of the points are in Montgomery
+// form, stored as big endian 64 bits limbs, least significant limb first.
+func ImportIgnition(size uint64, transcripts ...io.Reader) (*SRS, error) {
+
+	if size < 2 {
+		return nil, ErrMinSRSSize
+	}
+
+	_, _, g1Gen, g2Gen := curve.Generators()
+	var srs SRS
+	srs.Pk.G1 = make([]curve.G1Affine, 1, size)
+	srs.Pk.G1[0] = g1Gen
+	srs.Vk.G1 = g1Gen
+	srs.Vk.G2[0] = g2Gen
+
+	for i, r := range transcripts {
+		if uint64(len(srs.Pk.G1)) == size {
+			break
+		}
+		g1, g2, err := readIgnitionTranscript(r, uint32(i), uint32(len(srs.Pk.G1)-1), size-uint64(len(srs.Pk.G1)))
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			if len(g2) == 0 {
+				return nil, ErrInvalidIgnitionTranscript
+			}
+			srs.Vk.G2[1] = g2[0]
+		}
+		srs.Pk.G1 = append(srs.Pk.G1, g1...)
+	}
+	if uint64(len(srs.Pk.G1)) != size {
+		return nil, ErrIgnitionTooSmall
+	}
+	srs.Vk.Lines[0] = curve.PrecomputeLines(srs.Vk.G2[0])
+	srs.Vk.Lines[1] = curve.PrecomputeLines(srs.Vk.G2[1])
+
+	if err := VerifySRS(&srs); err != nil {
+		return nil, err
+	}
+	return &srs, nil
+}
+
+// readIgnitionTranscript reads the transcript number, which must start at the
+// power startFrom+1, and returns at most maxG1 of its points of G₁ and all of
+// its points of G₂.
+func readIgnitionTranscript(r io.Reader, number, startFrom uint32, maxG1 uint64) ([]curve.G1Affine, []curve.G2Affine, error) {
+
+	const (
+		g1Size = 2 * fp.Bytes
+		g2Size = 4 * fp.Bytes
+	)
+
+	hasher, err := blake2b.New512(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	br := bufio.NewReader(r)
+	tr := io.TeeReader(br, hasher)
+
+	var manifest ignitionManifest
+	if err := binary.Read(tr, binary.BigEndian, &manifest); err != nil {
+		return nil, nil, err
+	}
+	if manifest.TranscriptNumber != number || manifest.StartFrom != startFrom {
+		return nil, nil, ErrInvalidIgnitionTranscript
+	}
+
+	nbG1 := uint64(manifest.NumG1Points)
+	if nbG1 > maxG1 {
+		nbG1 = maxG1
+	}
+	bufG1 := make([]byte, nbG1*g1Size)
+	if _, err := io.ReadFull(tr, bufG1); err != nil {
+		return nil, nil, err
+	}
+	if _, err := io.CopyN(io.Discard, tr, int64(uint64(manifest.NumG1Points)-nbG1)*g1Size); err != nil {
+		return nil, nil, err
+	}
+	bufG2 := make([]byte, uint64(manifest.NumG2Points)*g2Size)
+	if _, err := io.ReadFull(tr, bufG2); err != nil {
+		return nil, nil, err
+	}
+
+	var checksum [blake2b.Size]byte
+	if _, err := io.ReadFull(br, checksum[:]); err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(checksum[:], hasher.Sum(nil)) {
+		return nil, nil, ErrIgnitionChecksum
+	}
+
+	g1 := make([]curve.G1Affine, nbG1)
+	err = decodePoints(len(g1), func(i int) error {
+		b := bufG1[i*g1Size:]
+		if err := setIgnitionElement(&g1[i].X, b); err != nil {
+			return err
+		}
+		if err := setIgnitionElement(&g1[i].Y, b[fp.Bytes:]); err != nil {
+			return err
+		}
+		if !g1[i].IsOnCurve() || !g1[i].IsInSubGroup() {
+			return ErrInvalidSRSPoint
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	g2 := make([]curve.G2Affine, manifest.NumG2Points)
+	err = decodePoints(len(g2), func(i int) error {
+		b := bufG2[i*g2Size:]
+		coordinates := []*fp.Element{&g2[i].X.A0, &g2[i].X.A1, &g2[i].Y.A0, &g2[i].Y.A1}
+		for j, c := range coordinates {
+			if err := setIgnitionElement(c, b[j*fp.Bytes:]); err != nil {
+				return err
+			}
+		}
+		if !g2[i].IsOnCurve() || !g2[i].IsInSubGroup() {
+			return ErrInvalidSRSPoint
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return g1, g2, nil
+}
+
+// setIgnitionElement sets z to the element whose Montgomery form is given by
+// the fp.Limbs big endian limbs of b, least significant limb first
+func setIgnitionElement(z *fp.Element, b []byte) error {
+	var buf [fp.Bytes]byte
+	for i := 0; i < fp.Limbs; i++ {
+		binary.LittleEndian.PutUint64(buf[8*i:], binary.BigEndian.Uint64(b[8*i:]))
+	}
+	return setMontgomery(z, buf[:])
+}
@@ -0,0 +1,29 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package mlkzg provides a multilinear KZG commitment scheme, following
+// Papamanthou, Shi and Tamassia [PST13].
+//
+// A multilinear polynomial f in n variables, given by its evaluations on the
+// hypercube {0,1}ⁿ (see polynomial.MultiLin), is committed as [f(τ₁, ..., τₙ)]G₁.
+// An opening at z ∈ Fⁿ is made of the commitments to the quotients qᵢ such that
+//
+//	f(X) - f(z) = ∑ᵢ (Xᵢ - zᵢ) qᵢ(Xᵢ₊₁, ..., Xₙ)
+//
+// and is verified with a single multi-pairing.
+//
+// [PST13]: https://eprint.iacr.org/2011/587
+package mlkzg
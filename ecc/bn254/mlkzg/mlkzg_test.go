@@ -0,0 +1,236 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package mlkzg
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/polynomial"
+
+	"github.com/consensys/gnark-crypto/utils/testutils"
+)
+
+// Test SRS re-used across tests of the multilinear KZG scheme
+var testSrs *SRS
+var testTau []fr.Element
+
+func init() {
+	const nbVars = 6
+	bTau := make([]*big.Int, nbVars)
+	testTau = make([]fr.Element, nbVars)
+	for i := range bTau {
+		bTau[i] = big.NewInt(int64(42 + i))
+		testTau[i].SetBigInt(bTau[i])
+	}
+	testSrs, _ = NewSRS(bTau)
+}
+
+func randomMultiLin(nbVars int) polynomial.MultiLin {
+	res := make(polynomial.MultiLin, 1<<nbVars)
+	for i := range res {
+		res[i].SetRandom()
+	}
+	return res
+}
+
+func randomPoint(nbVars int) []fr.Element {
+	res := make([]fr.Element, nbVars)
+	for i := range res {
+		res[i].SetRandom()
+	}
+	return res
+}
+
+func TestSerializationSRS(t *testing.T) {
+	t.Run("proving key round-trip", testutils.SerializationRoundTrip(&testSrs.Pk))
+	t.Run("proving key raw round-trip", testutils.SerializationRoundTripRaw(&testSrs.Pk))
+	t.Run("verifying key round-trip", testutils.SerializationRoundTrip(&testSrs.Vk))
+	t.Run("verifying key raw round-trip", testutils.SerializationRoundTripRaw(&testSrs.Vk))
+	t.Run("whole SRS round-trip", testutils.SerializationRoundTrip(testSrs))
+}
+
+func TestCommit(t *testing.T) {
+	assert := require.New(t)
+
+	nbVars := testSrs.Pk.NbVars()
+	for _, n := range []int{nbVars, nbVars - 2, 0} {
+
+		f := randomMultiLin(n)
+		digest, err := Commit(f, testSrs.Pk)
+		assert.NoError(err)
+
+		// the polynomial is evaluated at the last n coordinates of τ
+		fTau := f.Evaluate(testTau[nbVars-n:], nil)
+		var bFTau big.Int
+		fTau.BigInt(&bFTau)
+		var expected bn254.G1Affine
+		expected.ScalarMultiplication(&testSrs.Vk.G1, &bFTau)
+		assert.True(expected.Equal(&digest), "wrong commitment for %d variables", n)
+	}
+
+	_, err := Commit(randomMultiLin(testSrs.Pk.NbVars()+1), testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+	_, err = Commit(make(polynomial.MultiLin, 3), testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+func TestVerifySinglePoint(t *testing.T) {
+	assert := require.New(t)
+
+	for _, n := range []int{testSrs.Pk.NbVars(), 3} {
+
+		f := randomMultiLin(n)
+		digest, err := Commit(f, testSrs.Pk)
+		assert.NoError(err)
+
+		point := randomPoint(n)
+		proof, err := Open(f, point, testSrs.Pk)
+		assert.NoError(err)
+
+		// verify the claimed value
+		expected := f.Evaluate(point, nil)
+		assert.True(proof.ClaimedValue.Equal(&expected), "inconsistent claimed value")
+
+		// verify correct proof
+		assert.NoError(Verify(&digest, &proof, point, testSrs.Vk))
+
+		t.Run("serialization", testutils.SerializationRoundTrip(&proof))
+
+		// verify wrong point
+		wrongPoint := make([]fr.Element, n)
+		copy(wrongPoint, point)
+		wrongPoint[0].Double(&wrongPoint[0])
+		assert.Error(Verify(&digest, &proof, wrongPoint, testSrs.Vk))
+
+		// verify wrong claimed value
+		proof.ClaimedValue.Double(&proof.ClaimedValue)
+		assert.Error(Verify(&digest, &proof, point, testSrs.Vk))
+
+		// verify wrong proof with quotients set to zero
+		proof.ClaimedValue = expected
+		for i := range proof.Quotients {
+			proof.Quotients[i].X.SetZero()
+			proof.Quotients[i].Y.SetZero()
+		}
+		assert.Error(Verify(&digest, &proof, point, testSrs.Vk))
+
+		// verify proof with the wrong number of quotients
+		proof.Quotients = proof.Quotients[1:]
+		assert.ErrorIs(Verify(&digest, &proof, point, testSrs.Vk), ErrInvalidPointSize)
+	}
+}
+
+func TestBatchVerifySinglePoint(t *testing.T) {
+	assert := require.New(t)
+
+	const nbPolys = 5
+	n := testSrs.Pk.NbVars() - 1
+
+	f := make([]polynomial.MultiLin, nbPolys)
+	digests := make([]Digest, nbPolys)
+	for i := range f {
+		f[i] = randomMultiLin(n)
+		var err error
+		digests[i], err = Commit(f[i], testSrs.Pk)
+		assert.NoError(err)
+	}
+
+	hf := sha256.New()
+	point := randomPoint(n)
+	proof, err := BatchOpenSinglePoint(f, digests, point, hf, testSrs.Pk, []byte("data"))
+	assert.NoError(err)
+
+	// verify the claimed values
+	for i := range f {
+		expected := f[i].Evaluate(point, nil)
+		assert.True(proof.ClaimedValues[i].Equal(&expected), "inconsistent claimed value")
+	}
+
+	// verify correct proof
+	assert.NoError(BatchVerifySinglePoint(digests, &proof, point, hf, testSrs.Vk, []byte("data")))
+
+	t.Run("serialization", testutils.SerializationRoundTrip(&proof))
+
+	// verify with different transcript data
+	assert.Error(BatchVerifySinglePoint(digests, &proof, point, hf, testSrs.Vk, []byte("other data")))
+
+	// verify wrong proof
+	proof.ClaimedValues[0].Double(&proof.ClaimedValues[0])
+	assert.Error(BatchVerifySinglePoint(digests, &proof, point, hf, testSrs.Vk, []byte("data")))
+}
+
+func TestBatchVerifyMultiPoints(t *testing.T) {
+	assert := require.New(t)
+
+	// polynomials with different numbers of variables
+	nbVars := []int{testSrs.Pk.NbVars(), 4, 4, 1}
+	digests := make([]Digest, len(nbVars))
+	proofs := make([]OpeningProof, len(nbVars))
+	points := make([][]fr.Element, len(nbVars))
+	for i, n := range nbVars {
+		f := randomMultiLin(n)
+		var err error
+		digests[i], err = Commit(f, testSrs.Pk)
+		assert.NoError(err)
+		points[i] = randomPoint(n)
+		proofs[i], err = Open(f, points[i], testSrs.Pk)
+		assert.NoError(err)
+	}
+
+	// batch verify correct proofs
+	assert.NoError(BatchVerifyMultiPoints(digests, proofs, points, testSrs.Vk))
+
+	// batch verify tampered proofs
+	proofs[2].ClaimedValue.Double(&proofs[2].ClaimedValue)
+	assert.Error(BatchVerifyMultiPoints(digests, proofs, points, testSrs.Vk))
+}
+
+const benchNbVars = 16
+
+func BenchmarkSRSGen(b *testing.B) {
+	bTau := make([]*big.Int, benchNbVars)
+	for i := range bTau {
+		bTau[i] = big.NewInt(int64(42 + i))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = NewSRS(bTau)
+	}
+}
+
+func BenchmarkOpen(b *testing.B) {
+	bTau := make([]*big.Int, benchNbVars)
+	for i := range bTau {
+		bTau[i] = big.NewInt(int64(42 + i))
+	}
+	srs, err := NewSRS(bTau)
+	if err != nil {
+		b.Fatal(err)
+	}
+	f := randomMultiLin(benchNbVars)
+	point := randomPoint(benchNbVars)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Open(f, point, srs.Pk)
+	}
+}
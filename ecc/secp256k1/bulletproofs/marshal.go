@@ -0,0 +1,143 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bulletproofs
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	curve "github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+// maxNbRounds bounds the number of rounds of a decoded inner product argument
+const maxNbRounds = 32
+
+var errInvalidNbRounds = errors.New("invalid number of rounds")
+
+// WriteTo writes the binary encoding of the proof to w: the points
+// uncompressed, the number of rounds of the inner product argument on 4 bytes,
+// and the field elements in big endian.
+// It returns the number of bytes written.
+func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	write := func(b []byte) error {
+		m, err := w.Write(b)
+		n += int64(m)
+		return err
+	}
+	writePoint := func(p *curve.G1Affine) error {
+		b := p.RawBytes()
+		return write(b[:])
+	}
+	writeElement := func(e *fr.Element) error {
+		b := e.Bytes()
+		return write(b[:])
+	}
+
+	for _, p := range []*curve.G1Affine{&proof.A, &proof.S, &proof.T1, &proof.T2} {
+		if err := writePoint(p); err != nil {
+			return n, err
+		}
+	}
+	for _, e := range []*fr.Element{&proof.TauX, &proof.Mu, &proof.THat} {
+		if err := writeElement(e); err != nil {
+			return n, err
+		}
+	}
+	var nbRounds [4]byte
+	binary.BigEndian.PutUint32(nbRounds[:], uint32(len(proof.IPA.L)))
+	if err := write(nbRounds[:]); err != nil {
+		return n, err
+	}
+	for _, points := range [][]curve.G1Affine{proof.IPA.L, proof.IPA.R} {
+		for i := range points {
+			if err := writePoint(&points[i]); err != nil {
+				return n, err
+			}
+		}
+	}
+	for _, e := range []*fr.Element{&proof.IPA.A, &proof.IPA.B} {
+		if err := writeElement(e); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// ReadFrom decodes a proof from r.
+// It returns the number of bytes read.
+func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	read := func(b []byte) error {
+		m, err := io.ReadFull(r, b)
+		n += int64(m)
+		return err
+	}
+	readPoint := func(p *curve.G1Affine) error {
+		var b [curve.SizeOfG1AffineUncompressed]byte
+		if err := read(b[:]); err != nil {
+			return err
+		}
+		_, err := p.SetBytes(b[:])
+		return err
+	}
+	readElement := func(e *fr.Element) error {
+		var b [fr.Bytes]byte
+		if err := read(b[:]); err != nil {
+			return err
+		}
+		return e.SetBytesCanonical(b[:])
+	}
+
+	for _, p := range []*curve.G1Affine{&proof.A, &proof.S, &proof.T1, &proof.T2} {
+		if err := readPoint(p); err != nil {
+			return n, err
+		}
+	}
+	for _, e := range []*fr.Element{&proof.TauX, &proof.Mu, &proof.THat} {
+		if err := readElement(e); err != nil {
+			return n, err
+		}
+	}
+	var bNbRounds [4]byte
+	if err := read(bNbRounds[:]); err != nil {
+		return n, err
+	}
+	nbRounds := binary.BigEndian.Uint32(bNbRounds[:])
+	if nbRounds > maxNbRounds {
+		return n, errInvalidNbRounds
+	}
+	proof.IPA.L = make([]curve.G1Affine, nbRounds)
+	proof.IPA.R = make([]curve.G1Affine, nbRounds)
+	for _, points := range [][]curve.G1Affine{proof.IPA.L, proof.IPA.R} {
+		for i := range points {
+			if err := readPoint(&points[i]); err != nil {
+				return n, err
+			}
+		}
+	}
+	for _, e := range []*fr.Element{&proof.IPA.A, &proof.IPA.B} {
+		if err := readElement(e); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
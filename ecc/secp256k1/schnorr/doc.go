@@ -0,0 +1,31 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package schnorr provides the Schnorr signature scheme of BIP-340 on the
+// secp256k1 curve.
+//
+// Public keys are x-only: a public key is the x-coordinate of the point with
+// an even y-coordinate, and signatures are the x-coordinate of the nonce
+// commitment R followed by the scalar s. Nonces are derived deterministically
+// from the secret key, the message and auxiliary randomness, and challenges
+// are tagged hashes, as specified by the BIP.
+//
+// Signatures can be verified one by one, or in batch with a random linear
+// combination and a single multi-scalar multiplication.
+//
+// Documentation:
+// - BIP-340: https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+package schnorr
@@ -0,0 +1,342 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package schnorr
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/consensys/gnark-crypto/signature"
+)
+
+const (
+	sizeFr         = fr.Bytes
+	sizeFp         = fp.Bytes
+	sizePublicKey  = sizeFp
+	sizePrivateKey = sizeFr + sizePublicKey
+	sizeSignature  = sizeFp + sizeFr
+)
+
+// tags of the tagged hashes of BIP-340
+const (
+	tagAux       = "BIP0340/aux"
+	tagNonce     = "BIP0340/nonce"
+	tagChallenge = "BIP0340/challenge"
+)
+
+var (
+	// ErrNotOnCurve is returned when an x-coordinate is not that of a point
+	// on the curve.
+	ErrNotOnCurve = errors.New("x is not the coordinate of a point on the curve")
+	// ErrLengthMismatch is returned when the inputs of BatchVerify don't have
+	// the same length.
+	ErrLengthMismatch = errors.New("public keys, messages and signatures don't have the same length")
+)
+
+// PublicKey represents a BIP-340 public key. A is the point with an even
+// y-coordinate whose x-coordinate is the x-only public key.
+type PublicKey struct {
+	A secp256k1.G1Affine
+}
+
+// PrivateKey represents a BIP-340 private key
+type PrivateKey struct {
+	PublicKey PublicKey
+	scalar    [sizeFr]byte // secret scalar, in big Endian
+}
+
+// Signature represents a BIP-340 signature: R is the x-coordinate of the
+// nonce commitment and S the response.
+type Signature struct {
+	R [sizeFp]byte
+	S [sizeFr]byte
+}
+
+// GenerateKey generates a public and private key pair.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	b := make([]byte, fr.Bits/8+8)
+	if _, err := io.ReadFull(rand, b); err != nil {
+		return nil, err
+	}
+	k := new(big.Int).SetBytes(b)
+	n := new(big.Int).Sub(fr.Modulus(), big.NewInt(1))
+	k.Mod(k, n).Add(k, big.NewInt(1))
+
+	privateKey := new(PrivateKey)
+	k.FillBytes(privateKey.scalar[:])
+	privateKey.PublicKey.A.ScalarMultiplicationBase(k)
+	if !isEven(&privateKey.PublicKey.A.Y) {
+		privateKey.PublicKey.A.Y.Neg(&privateKey.PublicKey.A.Y)
+	}
+	return privateKey, nil
+}
+
+// taggedHash returns SHA256(SHA256(tag) ∥ SHA256(tag) ∥ data[0] ∥ data[1] ∥ …)
+func taggedHash(tag string, data ...[]byte) [sha256.Size]byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for i := range data {
+		h.Write(data[i])
+	}
+	var res [sha256.Size]byte
+	h.Sum(res[:0])
+	return res
+}
+
+// isEven returns true if the canonical representative of y is even
+func isEven(y *fp.Element) bool {
+	b := y.Bytes()
+	return b[sizeFp-1]&1 == 0
+}
+
+// liftX returns the point with an even y-coordinate whose x-coordinate is
+// encoded in buf, as the lift_x function of BIP-340.
+func liftX(buf []byte) (secp256k1.G1Affine, error) {
+	var p secp256k1.G1Affine
+	if err := p.X.SetBytesCanonical(buf); err != nil {
+		return p, err
+	}
+	// y² = x³ + 7
+	var seven fp.Element
+	seven.SetUint64(7)
+	p.Y.Square(&p.X).Mul(&p.Y, &p.X).Add(&p.Y, &seven)
+	if p.Y.Sqrt(&p.Y) == nil {
+		return p, ErrNotOnCurve
+	}
+	if !isEven(&p.Y) {
+		p.Y.Neg(&p.Y)
+	}
+	return p, nil
+}
+
+// challenge returns e = int(hash_BIP0340/challenge(r ∥ P ∥ m)) mod n
+func challenge(r []byte, publicKey *PublicKey, message []byte) *big.Int {
+	px := publicKey.A.X.Bytes()
+	e := taggedHash(tagChallenge, r, px[:], message)
+	var res fr.Element
+	res.SetBytes(e[:])
+	return res.BigInt(new(big.Int))
+}
+
+// hashMessage returns the message to sign or verify: the message itself if
+// hFunc is nil, its hash otherwise.
+func hashMessage(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	hFunc.Reset()
+	if _, err := hFunc.Write(message); err != nil {
+		return nil, err
+	}
+	return hFunc.Sum(nil), nil
+}
+
+// Equal compares 2 public keys
+func (pub *PublicKey) Equal(x signature.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	bpk := pub.Bytes()
+	bxx := xx.Bytes()
+	return subtle.ConstantTimeCompare(bpk, bxx) == 1
+}
+
+// Public returns the public key associated to the private key.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	var pub PublicKey
+	pub.A.Set(&privKey.PublicKey.A)
+	return &pub
+}
+
+// Sign performs the BIP-340 signature of the message, or of its hash if hFunc
+// is not nil, with auxiliary randomness read from crypto/rand.
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	var auxRand [32]byte
+	if _, err := io.ReadFull(rand.Reader, auxRand[:]); err != nil {
+		return nil, err
+	}
+	m, err := hashMessage(message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+	return privKey.SignWithAuxRand(m, auxRand)
+}
+
+// SignWithAuxRand performs the BIP-340 signature of the message with the
+// given auxiliary randomness
+//
+// d = d' if P = d'⋅G has an even y-coordinate, n-d' otherwise
+// t = d xor hash_BIP0340/aux(a)
+// k' = hash_BIP0340/nonce(t ∥ P ∥ m) mod n
+// R = k'⋅G, k = k' if R has an even y-coordinate, n-k' otherwise
+// e = hash_BIP0340/challenge(R ∥ P ∥ m) mod n
+// signature = R ∥ (k + e⋅d) mod n
+func (privKey *PrivateKey) SignWithAuxRand(message []byte, auxRand [32]byte) ([]byte, error) {
+	order := fr.Modulus()
+
+	d := new(big.Int).SetBytes(privKey.scalar[:])
+	if d.Sign() == 0 || d.Cmp(order) >= 0 {
+		return nil, errors.New("invalid private key")
+	}
+	var publicKey PublicKey
+	publicKey.A.ScalarMultiplicationBase(d)
+	if !isEven(&publicKey.A.Y) {
+		d.Sub(order, d)
+		publicKey.A.Y.Neg(&publicKey.A.Y)
+	}
+
+	// nonce
+	var t [sizeFr]byte
+	d.FillBytes(t[:])
+	aux := taggedHash(tagAux, auxRand[:])
+	for i := range t {
+		t[i] ^= aux[i]
+	}
+	px := publicKey.A.X.Bytes()
+	kBin := taggedHash(tagNonce, t[:], px[:], message)
+	k := new(big.Int).SetBytes(kBin[:])
+	k.Mod(k, order)
+	if k.Sign() == 0 {
+		return nil, errors.New("zero nonce")
+	}
+	var R secp256k1.G1Affine
+	R.ScalarMultiplicationBase(k)
+	if !isEven(&R.Y) {
+		k.Sub(order, k)
+	}
+
+	var sig Signature
+	sig.R = R.X.Bytes()
+	e := challenge(sig.R[:], &publicKey, message)
+	s := new(big.Int).Mul(e, d)
+	s.Add(s, k).Mod(s, order)
+	s.FillBytes(sig.S[:])
+
+	return sig.Bytes(), nil
+}
+
+// Verify validates the BIP-340 signature of the message, or of its hash if
+// hFunc is not nil
+//
+// R = s⋅G - e⋅P
+// R is not the infinity point, has an even y-coordinate and x(R) = r
+func (publicKey *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return false, err
+	}
+	m, err := hashMessage(message, hFunc)
+	if err != nil {
+		return false, err
+	}
+
+	e := challenge(sig.R[:], publicKey, m)
+	e.Sub(fr.Modulus(), e)
+	s := new(big.Int).SetBytes(sig.S[:])
+
+	var _R secp256k1.G1Jac
+	_R.JointScalarMultiplicationBase(&publicKey.A, s, e)
+	if _R.Z.IsZero() {
+		return false, nil
+	}
+	var R secp256k1.G1Affine
+	R.FromJacobian(&_R)
+	if !isEven(&R.Y) {
+		return false, nil
+	}
+	rx := R.X.Bytes()
+	return subtle.ConstantTimeCompare(rx[:], sig.R[:]) == 1, nil
+}
+
+// BatchVerify validates the BIP-340 signatures of the messages, or of their
+// hashes if hFunc is not nil. It returns true if all the signatures are
+// valid.
+//
+// With random a₀ = 1, a₁, …, aₙ₋₁, it checks with a single multi-scalar
+// multiplication that
+//
+// (∑ aᵢ⋅sᵢ)⋅G = ∑ aᵢ⋅Rᵢ + ∑ (aᵢ⋅eᵢ)⋅Pᵢ
+//
+// where Rᵢ is the point with an even y-coordinate whose x-coordinate is rᵢ.
+func BatchVerify(publicKeys []PublicKey, messages [][]byte, signatures [][]byte, hFunc hash.Hash) (bool, error) {
+	n := len(publicKeys)
+	if len(messages) != n || len(signatures) != n {
+		return false, ErrLengthMismatch
+	}
+	if n == 0 {
+		return true, nil
+	}
+
+	// points: G, R₀, P₀, R₁, P₁, …
+	points := make([]secp256k1.G1Affine, 2*n+1)
+	scalars := make([]fr.Element, 2*n+1)
+	_, points[0] = secp256k1.Generators()
+
+	var a, as, tmp fr.Element
+	var sig Signature
+	for i := 0; i < n; i++ {
+		if _, err := sig.SetBytes(signatures[i]); err != nil {
+			return false, err
+		}
+		m, err := hashMessage(messages[i], hFunc)
+		if err != nil {
+			return false, err
+		}
+		R, err := liftX(sig.R[:])
+		if err != nil {
+			return false, nil
+		}
+
+		if i == 0 {
+			a.SetOne()
+		} else if _, err = a.SetRandom(); err != nil {
+			return false, err
+		}
+
+		// - ∑ aᵢ⋅sᵢ
+		tmp.SetBytes(sig.S[:])
+		tmp.Mul(&tmp, &a)
+		as.Add(&as, &tmp)
+
+		var e fr.Element
+		e.SetBigInt(challenge(sig.R[:], &publicKeys[i], m))
+
+		points[2*i+1] = R
+		scalars[2*i+1] = a
+		points[2*i+2] = publicKeys[i].A
+		scalars[2*i+2].Mul(&a, &e)
+	}
+	scalars[0].Neg(&as)
+
+	var res secp256k1.G1Jac
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false, err
+	}
+	return res.Z.IsZero(), nil
+}
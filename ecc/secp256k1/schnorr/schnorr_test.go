@@ -0,0 +1,217 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package schnorr
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchnorr(t *testing.T) {
+
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[SECP256K1] test the signing and verification", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing Schnorr")
+			hFunc := sha256.New()
+			sig, _ := privKey.Sign(msg, hFunc)
+			flag, _ := publicKey.Verify(sig, msg, hFunc)
+
+			return flag
+		},
+	))
+
+	properties.Property("[SECP256K1] test the signing and verification (pre-hashed)", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing Schnorr")
+			sig, _ := privKey.Sign(msg, nil)
+			flag, _ := publicKey.Verify(sig, msg, nil)
+
+			return flag
+		},
+	))
+
+	properties.Property("[SECP256K1] a signature should not verify another message", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			sig, _ := privKey.Sign([]byte("testing Schnorr"), nil)
+			flag, _ := publicKey.Verify(sig, []byte("testing ECDSA"), nil)
+
+			return !flag
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestBatchVerify(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+
+	const n = 8
+	publicKeys := make([]PublicKey, n)
+	messages := make([][]byte, n)
+	signatures := make([][]byte, n)
+	hFunc := sha256.New()
+	for i := 0; i < n; i++ {
+		privKey, err := GenerateKey(rand.Reader)
+		assert.NoError(err)
+		publicKeys[i] = privKey.PublicKey
+		messages[i] = []byte{byte(i), 's', 'c', 'h', 'n', 'o', 'r', 'r'}
+		signatures[i], err = privKey.Sign(messages[i], hFunc)
+		assert.NoError(err)
+	}
+
+	ok, err := BatchVerify(publicKeys, messages, signatures, hFunc)
+	assert.NoError(err)
+	assert.True(ok)
+
+	ok, err = BatchVerify(nil, nil, nil, hFunc)
+	assert.NoError(err)
+	assert.True(ok)
+
+	_, err = BatchVerify(publicKeys, messages[1:], signatures, hFunc)
+	assert.Equal(ErrLengthMismatch, err)
+
+	// swapping two messages must fail
+	messages[2], messages[5] = messages[5], messages[2]
+	ok, err = BatchVerify(publicKeys, messages, signatures, hFunc)
+	assert.NoError(err)
+	assert.False(ok)
+}
+
+// TestVectors runs the test vectors of BIP-340, from
+// https://github.com/bitcoin/bips/blob/master/bip-0340/test-vectors.csv
+func TestVectors(t *testing.T) {
+	t.Parallel()
+	assert := require.New(t)
+
+	f, err := os.Open(filepath.Join("testdata", "test-vectors.csv"))
+	assert.NoError(err)
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	assert.NoError(err)
+	assert.Greater(len(records), 1)
+
+	decode := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		assert.NoError(err)
+		return b
+	}
+
+	// index,secret key,public key,aux_rand,message,signature,verification result,comment
+	for _, record := range records[1:] {
+		t.Run(record[0], func(t *testing.T) {
+			assert := require.New(t)
+			publicKeyBin, message, sigBin := decode(record[2]), decode(record[4]), decode(record[5])
+			expected := record[6] == "TRUE"
+
+			if record[1] != "" {
+				var privKey PrivateKey
+				assert.NoError(privKey.SetScalar(decode(record[1])))
+				assert.Equal(publicKeyBin, privKey.PublicKey.Bytes())
+
+				var auxRand [32]byte
+				copy(auxRand[:], decode(record[3]))
+				sig, err := privKey.SignWithAuxRand(message, auxRand)
+				assert.NoError(err)
+				assert.Equal(sigBin, sig)
+			}
+
+			// invalid encodings are rejected
+			var publicKey PublicKey
+			if _, err := publicKey.SetBytes(publicKeyBin); err != nil {
+				assert.False(expected)
+				return
+			}
+			ok, err := publicKey.Verify(sigBin, message, nil)
+			if err != nil {
+				assert.False(expected)
+				return
+			}
+			assert.Equal(expected, ok)
+
+			ok, err = BatchVerify([]PublicKey{publicKey}, [][]byte{message}, [][]byte{sigBin}, nil)
+			assert.NoError(err)
+			assert.Equal(expected, ok)
+		})
+	}
+}
+
+func BenchmarkSignSchnorr(b *testing.B) {
+
+	privKey, _ := GenerateKey(rand.Reader)
+
+	msg := []byte("benchmarking Schnorr sign()")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.Sign(msg, nil)
+	}
+}
+
+func BenchmarkVerifySchnorr(b *testing.B) {
+
+	privKey, _ := GenerateKey(rand.Reader)
+	msg := []byte("benchmarking Schnorr sign()")
+	sig, _ := privKey.Sign(msg, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.PublicKey.Verify(sig, msg, nil)
+	}
+}
+
+func BenchmarkBatchVerifySchnorr(b *testing.B) {
+	const n = 64
+	publicKeys := make([]PublicKey, n)
+	messages := make([][]byte, n)
+	signatures := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, _ := GenerateKey(rand.Reader)
+		publicKeys[i] = privKey.PublicKey
+		messages[i] = []byte{byte(i)}
+		signatures[i], _ = privKey.Sign(messages[i], nil)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchVerify(publicKeys, messages, signatures, nil)
+	}
+}
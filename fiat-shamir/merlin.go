@@ -0,0 +1,136 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiatshamir
+
+import "encoding/binary"
+
+// merlinChallengeSize is the size of the challenges computed by
+// MerlinTranscript.ComputeChallenge: as Merlin's challenge_scalar, 64 bytes, so
+// that their reduction into a field is unbiased.
+const merlinChallengeSize = 64
+
+// MerlinTranscript is a Merlin transcript (https://merlin.cool), built on
+// STROBE-128 over Keccak-f[1600], interoperable with the Rust implementation.
+//
+// It exposes the Merlin API (AppendMessage, AppendUint64, ChallengeBytes) and
+// the API of Transcript (Bind, ComputeChallenge), so that the protocols using
+// the latter can be driven by a Merlin transcript. The challenges must then be
+// declared up front and computed in order; the values binded to a challenge are
+// appended as messages labeled by the challenge name right before it is computed.
+type MerlinTranscript struct {
+	s *strobe128
+
+	challenges map[string]challenge
+	previous   *challenge
+}
+
+// NewMerlinTranscript returns a new Merlin transcript, separated from the other
+// ones by label. challengesID are the names of the challenges which can be
+// computed with ComputeChallenge, in order.
+func NewMerlinTranscript(label string, challengesID ...string) *MerlinTranscript {
+	t := &MerlinTranscript{
+		s:          newStrobe128([]byte("Merlin v1.0")),
+		challenges: make(map[string]challenge, len(challengesID)),
+	}
+	for i := range challengesID {
+		t.challenges[challengesID[i]] = challenge{position: i}
+	}
+	t.AppendMessage("dom-sep", []byte(label))
+	return t
+}
+
+// AppendMessage appends message, with its label, to the transcript
+func (t *MerlinTranscript) AppendMessage(label string, message []byte) {
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(message)))
+	t.s.metaAD([]byte(label), false)
+	t.s.metaAD(size[:], true)
+	t.s.ad(message, false)
+}
+
+// AppendUint64 appends the little-endian encoding of x, with its label, to the
+// transcript
+func (t *MerlinTranscript) AppendUint64(label string, x uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], x)
+	t.AppendMessage(label, b[:])
+}
+
+// ChallengeBytes fills dest with a challenge bound to label and to the
+// transcript so far
+func (t *MerlinTranscript) ChallengeBytes(label string, dest []byte) {
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(dest)))
+	t.s.metaAD([]byte(label), false)
+	t.s.metaAD(size[:], true)
+	t.s.prf(dest, false)
+}
+
+// Bind binds the challenge to value. A challenge can be binded to an
+// arbitrary number of values, but the order in which the binded values
+// are added is important. Once a challenge is computed, it cannot be
+// binded to other values.
+func (t *MerlinTranscript) Bind(challengeID string, bValue []byte) error {
+	currentChallenge, ok := t.challenges[challengeID]
+	if !ok {
+		return errChallengeNotFound
+	}
+
+	if currentChallenge.isComputed {
+		return errChallengeAlreadyComputed
+	}
+
+	bCopy := make([]byte, len(bValue))
+	copy(bCopy, bValue)
+	currentChallenge.bindings = append(currentChallenge.bindings, bCopy)
+	t.challenges[challengeID] = currentChallenge
+
+	return nil
+}
+
+// ComputeChallenge computes the challenge corresponding to the given name: the
+// binded values are appended to the transcript, labeled by the name, then 64
+// bytes are squeezed with the same label. The challenges being computed in
+// order, each one is bound to the previous ones.
+func (t *MerlinTranscript) ComputeChallenge(challengeID string) ([]byte, error) {
+	challenge, ok := t.challenges[challengeID]
+	if !ok {
+		return nil, errChallengeNotFound
+	}
+
+	// if the challenge was already computed we return it
+	if challenge.isComputed {
+		return challenge.value, nil
+	}
+
+	if challenge.position != 0 && (t.previous == nil || t.previous.position != challenge.position-1) {
+		return nil, errPreviousChallengeNotComputed
+	}
+
+	for _, b := range challenge.bindings {
+		t.AppendMessage(challengeID, b)
+	}
+
+	challenge.value = make([]byte, merlinChallengeSize)
+	t.ChallengeBytes(challengeID, challenge.value)
+	challenge.isComputed = true
+
+	t.challenges[challengeID] = challenge
+	t.previous = &challenge
+
+	res := make([]byte, merlinChallengeSize)
+	copy(res, challenge.value)
+	return res, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiatshamir
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+)
+
+// sha3Sum256 computes SHA3-256 on top of keccakF1600
+func sha3Sum256(data []byte) []byte {
+	const rate = 136
+	padded := append(append([]byte{}, data...), 0x06)
+	for len(padded)%rate != 0 {
+		padded = append(padded, 0)
+	}
+	padded[len(padded)-1] |= 0x80
+
+	var a [25]uint64
+	for ; len(padded) > 0; padded = padded[rate:] {
+		for i := 0; i < rate/8; i++ {
+			a[i] ^= binary.LittleEndian.Uint64(padded[8*i:])
+		}
+		keccakF1600(&a)
+	}
+	res := make([]byte, 32)
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint64(res[8*i:], a[i])
+	}
+	return res
+}
+
+func TestKeccakF1600(t *testing.T) {
+	assert := require.New(t)
+	for _, size := range []int{0, 1, 135, 136, 137, 500} {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i)
+		}
+		expected := sha3.Sum256(data)
+		assert.Equal(expected[:], sha3Sum256(data), "size %d", size)
+	}
+}
+
+// test vectors of the reference implementation
+// (https://github.com/zkcrypto/merlin)
+
+func TestMerlinSimple(t *testing.T) {
+	tr := NewMerlinTranscript("test protocol")
+	tr.AppendMessage("some label", []byte("some data"))
+
+	challenge := make([]byte, 32)
+	tr.ChallengeBytes("challenge", challenge)
+	require.Equal(t, "d5a21972d0d5fe320c0d263fac7fffb8145aa640af6e9bca177c03c7efcf0615", hex.EncodeToString(challenge))
+}
+
+func TestMerlinComplex(t *testing.T) {
+	tr := NewMerlinTranscript("test protocol")
+	tr.AppendMessage("step1", []byte("some data"))
+
+	data := bytes.Repeat([]byte{99}, 1024)
+	challenge := make([]byte, 32)
+	for i := 0; i < 32; i++ {
+		tr.ChallengeBytes("challenge", challenge)
+		tr.AppendMessage("bigdata", data)
+		tr.AppendMessage("challengedata", challenge)
+	}
+	require.Equal(t, "a8c933f54fae76e3f9bea93648c1308e7dfa2152dd51674ff3ca438351cf003c", hex.EncodeToString(challenge))
+}
+
+func TestMerlinTranscript(t *testing.T) {
+	assert := require.New(t)
+
+	var fs Challenger = NewMerlinTranscript("test protocol", "alpha", "beta")
+	assert.NoError(fs.Bind("alpha", []byte("v1")))
+	assert.NoError(fs.Bind("beta", []byte("v2")))
+	assert.ErrorIs(fs.Bind("gamma", []byte("v3")), errChallengeNotFound)
+
+	_, err := fs.ComputeChallenge("beta")
+	assert.ErrorIs(err, errPreviousChallengeNotComputed)
+
+	alpha, err := fs.ComputeChallenge("alpha")
+	assert.NoError(err)
+	assert.Len(alpha, merlinChallengeSize)
+	assert.ErrorIs(fs.Bind("alpha", []byte("v3")), errChallengeAlreadyComputed)
+	beta, err := fs.ComputeChallenge("beta")
+	assert.NoError(err)
+
+	// computing a challenge twice returns the same value
+	alphaBis, err := fs.ComputeChallenge("alpha")
+	assert.NoError(err)
+	assert.Equal(alpha, alphaBis)
+
+	// the bindings are appended as messages labeled by the challenge name
+	tr := NewMerlinTranscript("test protocol")
+	tr.AppendMessage("alpha", []byte("v1"))
+	expected := make([]byte, merlinChallengeSize)
+	tr.ChallengeBytes("alpha", expected)
+	assert.Equal(expected, alpha)
+	tr.AppendMessage("beta", []byte("v2"))
+	tr.ChallengeBytes("beta", expected)
+	assert.Equal(expected, beta)
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiatshamir
+
+import (
+	"encoding/binary"
+	"hash"
+)
+
+// operations absorbed in a StreamingTranscript
+const (
+	opDomain byte = iota
+	opMessage
+	opChallenge
+	opSqueeze
+)
+
+// chunkSize is the size of the pieces in which the data is written to the
+// hash function. It is smaller than the size of the elements of the fields
+// of the algebraic hashes, whose Write left-pads short inputs, so that any
+// data is a valid input.
+const chunkSize = 31
+
+// StreamingTranscript is a Fiat-Shamir transcript whose messages are absorbed
+// as they come, and whose challenges are squeezed at any time, without being
+// declared up front.
+//
+// Each message is absorbed along with a label and its length, and each
+// challenge is bound to all the previous messages and challenges. Works with
+// byte oriented hash functions (SHA-256, Keccak, ...) as well as with
+// algebraic ones (MiMC, ...): the data is written in chunks of 31 bytes at
+// most.
+type StreamingTranscript struct {
+	h   hash.Hash
+	err error
+}
+
+// NewStreamingTranscript returns a new transcript using h, separated from
+// other uses of h by domain.
+func NewStreamingTranscript(h hash.Hash, domain string) *StreamingTranscript {
+	h.Reset()
+	t := &StreamingTranscript{h: h}
+	t.absorb(opDomain, domain, nil)
+	return t
+}
+
+// AppendMessage absorbs message, with its label
+func (t *StreamingTranscript) AppendMessage(label string, message []byte) error {
+	t.absorb(opMessage, label, message)
+	return t.err
+}
+
+// ChallengeBytes returns n challenges of size bytes each, bound to the label
+// and to the transcript so far.
+//
+// The challenges are derived in counter mode from a seed, the digest of the
+// transcript: challenge i is H(seed, i, 0) ∥ H(seed, i, 1) ∥ ..., truncated.
+// The transcript then restarts from the seed.
+func (t *StreamingTranscript) ChallengeBytes(label string, n, size int) ([][]byte, error) {
+	t.absorb(opChallenge, label, nil)
+	if t.err != nil {
+		return nil, t.err
+	}
+	seed := t.h.Sum(nil)
+
+	res := make([][]byte, n)
+	for i := range res {
+		for j := 0; len(res[i]) < size; j++ {
+			t.h.Reset()
+			t.writeChunks(seed)
+			t.write([]byte{opSqueeze})
+			t.writeUint64(uint64(i))
+			t.writeUint64(uint64(j))
+			res[i] = t.h.Sum(res[i])
+		}
+		res[i] = res[i][:size]
+	}
+
+	t.h.Reset()
+	t.writeChunks(seed)
+
+	if t.err != nil {
+		return nil, t.err
+	}
+	return res, nil
+}
+
+// absorb writes op, the length of label, label, the length of message and
+// message to the hash function
+func (t *StreamingTranscript) absorb(op byte, label string, message []byte) {
+	t.write([]byte{op})
+	t.writeUint64(uint64(len(label)))
+	t.writeChunks([]byte(label))
+	t.writeUint64(uint64(len(message)))
+	t.writeChunks(message)
+}
+
+func (t *StreamingTranscript) writeChunks(data []byte) {
+	for len(data) > 0 {
+		n := min(len(data), chunkSize)
+		t.write(data[:n])
+		data = data[n:]
+	}
+}
+
+func (t *StreamingTranscript) writeUint64(v uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	t.write(buf[:])
+}
+
+// write writes data to the hash function, the first error being kept
+func (t *StreamingTranscript) write(data []byte) {
+	if t.err != nil {
+		return
+	}
+	_, t.err = t.h.Write(data)
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiatshamir
+
+import (
+	"crypto/sha256"
+	"hash"
+	"testing"
+
+	gchash "github.com/consensys/gnark-crypto/hash"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestStreamingTranscript(t *testing.T) {
+	t.Parallel()
+
+	for _, newHash := range []func() hash.Hash{sha256.New, sha3.NewLegacyKeccak256, gchash.MIMC_BN254.New, gchash.MIMC_BW6_761.New} {
+		assert := require.New(t)
+
+		challenge := func(domain string, messages ...[2]string) [][]byte {
+			ts := NewStreamingTranscript(newHash(), domain)
+			for _, m := range messages {
+				assert.NoError(ts.AppendMessage(m[0], []byte(m[1])))
+			}
+			res, err := ts.ChallengeBytes("c", 2, 48)
+			assert.NoError(err)
+			return res
+		}
+
+		reference := challenge("test", [2]string{"a", "some message longer than a chunk of 31 bytes"})
+		assert.Len(reference, 2)
+		assert.Len(reference[0], 48)
+		assert.NotEqual(reference[0], reference[1])
+
+		// deterministic
+		assert.Equal(reference, challenge("test", [2]string{"a", "some message longer than a chunk of 31 bytes"}))
+
+		// bound to the domain, the labels, the messages and their boundaries
+		assert.NotEqual(reference, challenge("other", [2]string{"a", "some message longer than a chunk of 31 bytes"}))
+		assert.NotEqual(reference, challenge("test", [2]string{"b", "some message longer than a chunk of 31 bytes"}))
+		assert.NotEqual(reference, challenge("test", [2]string{"a", "some message longer than a chunk of 31 byte"}))
+		assert.NotEqual(reference, challenge("test", [2]string{"a", "some message longer than a chunk"}, [2]string{"", " of 31 bytes"}))
+		assert.NotEqual(reference, challenge("test", [2]string{"a", "some message longer than a chunk of 31 bytes"}, [2]string{"", ""}))
+
+		// successive challenges differ and are bound to the previous ones
+		ts := NewStreamingTranscript(newHash(), "test")
+		c1, err := ts.ChallengeBytes("c", 1, 32)
+		assert.NoError(err)
+		c2, err := ts.ChallengeBytes("c", 1, 32)
+		assert.NoError(err)
+		assert.NotEqual(c1, c2)
+	}
+}
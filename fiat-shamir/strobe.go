@@ -0,0 +1,129 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiatshamir
+
+import "encoding/binary"
+
+// strobeR is the rate of STROBE-128 (in bytes), minus the 2 bytes of padding
+const strobeR = 166
+
+// STROBE operation flags
+const (
+	strobeFlagI byte = 1 << iota
+	strobeFlagA
+	strobeFlagC
+	strobeFlagT
+	strobeFlagM
+	strobeFlagK
+)
+
+// strobe128 is the subset of STROBE-128 (over Keccak-f[1600]) needed by the
+// Merlin transcripts: the meta-AD, AD and PRF operations, on a transcript that
+// is never sent, so that the T flag is never set.
+type strobe128 struct {
+	state    [200]byte
+	pos      byte
+	posBegin byte
+	curFlags byte
+}
+
+// newStrobe128 returns a STROBE-128 instance, initialized with the protocol label
+func newStrobe128(protocolLabel []byte) *strobe128 {
+	s := new(strobe128)
+	copy(s.state[:6], []byte{1, strobeR + 2, 1, 0, 1, 96})
+	copy(s.state[6:], "STROBEv1.0.2")
+	s.permute()
+	s.metaAD(protocolLabel, false)
+	return s
+}
+
+// metaAD absorbs framing data
+func (s *strobe128) metaAD(data []byte, more bool) {
+	s.beginOp(strobeFlagM|strobeFlagA, more)
+	s.absorb(data)
+}
+
+// ad absorbs associated data
+func (s *strobe128) ad(data []byte, more bool) {
+	s.beginOp(strobeFlagA, more)
+	s.absorb(data)
+}
+
+// prf squeezes pseudo-random bytes in data
+func (s *strobe128) prf(data []byte, more bool) {
+	s.beginOp(strobeFlagI|strobeFlagA|strobeFlagC, more)
+	s.squeeze(data)
+}
+
+func (s *strobe128) beginOp(flags byte, more bool) {
+	if more {
+		if s.curFlags != flags {
+			panic("strobe: continued operation with different flags")
+		}
+		return
+	}
+	oldBegin := s.posBegin
+	s.posBegin = s.pos + 1
+	s.curFlags = flags
+	s.absorb([]byte{oldBegin, flags})
+
+	// the cipher and key operations start on a fresh block
+	if flags&(strobeFlagC|strobeFlagK) != 0 && s.pos != 0 {
+		s.runF()
+	}
+}
+
+func (s *strobe128) absorb(data []byte) {
+	for _, b := range data {
+		s.state[s.pos] ^= b
+		s.pos++
+		if s.pos == strobeR {
+			s.runF()
+		}
+	}
+}
+
+func (s *strobe128) squeeze(data []byte) {
+	for i := range data {
+		data[i] = s.state[s.pos]
+		s.state[s.pos] = 0
+		s.pos++
+		if s.pos == strobeR {
+			s.runF()
+		}
+	}
+}
+
+// runF pads the current block and permutes the state
+func (s *strobe128) runF() {
+	s.state[s.pos] ^= s.posBegin
+	s.state[s.pos+1] ^= 0x04
+	s.state[strobeR+1] ^= 0x80
+	s.permute()
+	s.pos = 0
+	s.posBegin = 0
+}
+
+// permute applies Keccak-f[1600] to the state, whose lanes are little-endian
+func (s *strobe128) permute() {
+	var a [25]uint64
+	for i := range a {
+		a[i] = binary.LittleEndian.Uint64(s.state[8*i:])
+	}
+	keccakF1600(&a)
+	for i := range a {
+		binary.LittleEndian.PutUint64(s.state[8*i:], a[i])
+	}
+}
@@ -27,6 +27,13 @@ var (
 	errPreviousChallengeNotComputed = errors.New("the previous challenge is needed and has not been computed")
 )
 
+// Challenger is the API of the Fiat-Shamir transcripts whose challenges are
+// declared up front, implemented by Transcript and MerlinTranscript.
+type Challenger interface {
+	Bind(challengeID string, bValue []byte) error
+	ComputeChallenge(challengeID string) ([]byte, error)
+}
+
 // Transcript handles the creation of challenges for Fiat Shamir.
 type Transcript struct {
 	// hash function that is used.
@@ -0,0 +1,364 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldilocks
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/field/pool"
+)
+
+// SizeOfE2 is the number of bytes of a serialized E2 element
+const SizeOfE2 = 2 * Bytes
+
+// e2NonResidue is the quadratic non-residue 7, such that E2 = 𝔽ₚ[u]/(u²-7)
+const e2NonResidue = 7
+
+// E2 is the degree two extension 𝔽ₚ[u]/(u²-7) of the Goldilocks field.
+// An element is A0 + A1⋅u.
+type E2 struct {
+	A0, A1 Element
+}
+
+// Equal returns true if z equals x, false otherwise
+func (z *E2) Equal(x *E2) bool {
+	return z.A0.Equal(&x.A0) && z.A1.Equal(&x.A1)
+}
+
+// Cmp compares (lexicographic order) z and x and returns:
+//
+//	-1 if z <  x
+//	 0 if z == x
+//	+1 if z >  x
+func (z *E2) Cmp(x *E2) int {
+	if a1 := z.A1.Cmp(&x.A1); a1 != 0 {
+		return a1
+	}
+	return z.A0.Cmp(&x.A0)
+}
+
+// SetString sets a E2 element from strings
+func (z *E2) SetString(s1, s2 string) *E2 {
+	z.A0.SetString(s1)
+	z.A1.SetString(s2)
+	return z
+}
+
+// SetZero sets an E2 elmt to zero
+func (z *E2) SetZero() *E2 {
+	z.A0.SetZero()
+	z.A1.SetZero()
+	return z
+}
+
+// Set sets an E2 from x
+func (z *E2) Set(x *E2) *E2 {
+	z.A0 = x.A0
+	z.A1 = x.A1
+	return z
+}
+
+// SetOne sets z to 1 in Montgomery form and returns z
+func (z *E2) SetOne() *E2 {
+	z.A0.SetOne()
+	z.A1.SetZero()
+	return z
+}
+
+// SetElement sets z to the base field element x
+func (z *E2) SetElement(x *Element) *E2 {
+	z.A0.Set(x)
+	z.A1.SetZero()
+	return z
+}
+
+// SetRandom sets a0 and a1 to random values
+func (z *E2) SetRandom() (*E2, error) {
+	if _, err := z.A0.SetRandom(); err != nil {
+		return nil, err
+	}
+	if _, err := z.A1.SetRandom(); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// IsZero returns true if z is equal to 0
+func (z *E2) IsZero() bool {
+	return z.A0.IsZero() && z.A1.IsZero()
+}
+
+// IsOne returns true if z is equal to 1
+func (z *E2) IsOne() bool {
+	return z.A0.IsOne() && z.A1.IsZero()
+}
+
+// Add adds two elements of E2
+func (z *E2) Add(x, y *E2) *E2 {
+	z.A0.Add(&x.A0, &y.A0)
+	z.A1.Add(&x.A1, &y.A1)
+	return z
+}
+
+// Sub subtracts two elements of E2
+func (z *E2) Sub(x, y *E2) *E2 {
+	z.A0.Sub(&x.A0, &y.A0)
+	z.A1.Sub(&x.A1, &y.A1)
+	return z
+}
+
+// Double doubles an E2 element
+func (z *E2) Double(x *E2) *E2 {
+	z.A0.Double(&x.A0)
+	z.A1.Double(&x.A1)
+	return z
+}
+
+// Neg negates an E2 element
+func (z *E2) Neg(x *E2) *E2 {
+	z.A0.Neg(&x.A0)
+	z.A1.Neg(&x.A1)
+	return z
+}
+
+// String implements Stringer interface for fancy printing
+func (z *E2) String() string {
+	return z.A0.String() + "+" + z.A1.String() + "*u"
+}
+
+// Mul sets z to the E2-product of x,y, returns z
+func (z *E2) Mul(x, y *E2) *E2 {
+	var a, b, c Element
+	a.Add(&x.A0, &x.A1)
+	b.Add(&y.A0, &y.A1)
+	a.Mul(&a, &b)
+	b.Mul(&x.A0, &y.A0)
+	c.Mul(&x.A1, &y.A1)
+	z.A1.Sub(&a, &b).Sub(&z.A1, &c)
+	mulByE2NonResidue(&c, &c)
+	z.A0.Add(&b, &c)
+	return z
+}
+
+// Square sets z to the E2-product of x,x returns z
+func (z *E2) Square(x *E2) *E2 {
+	// (a0 + a1⋅u)² = a0² + 7⋅a1² + 2⋅a0⋅a1⋅u
+	var a, b Element
+	a.Square(&x.A0)
+	b.Square(&x.A1)
+	mulByE2NonResidue(&b, &b)
+	z.A1.Mul(&x.A0, &x.A1).Double(&z.A1)
+	z.A0.Add(&a, &b)
+	return z
+}
+
+// MulByElement multiplies an element in E2 by an element in the base field
+func (z *E2) MulByElement(x *E2, y *Element) *E2 {
+	var yCopy Element
+	yCopy.Set(y)
+	z.A0.Mul(&x.A0, &yCopy)
+	z.A1.Mul(&x.A1, &yCopy)
+	return z
+}
+
+// Conjugate conjugates an element in E2
+func (z *E2) Conjugate(x *E2) *E2 {
+	z.A0 = x.A0
+	z.A1.Neg(&x.A1)
+	return z
+}
+
+// Frobenius sets z to x^p. Since 7 is a non-residue, u^p = -u and the
+// Frobenius map is the conjugation.
+func (z *E2) Frobenius(x *E2) *E2 {
+	return z.Conjugate(x)
+}
+
+// Norm sets x to the norm of z, z⋅z^p = a0² - 7⋅a1²
+func (z *E2) Norm(x *Element) {
+	var tmp Element
+	x.Square(&z.A0)
+	tmp.Square(&z.A1)
+	mulByE2NonResidue(&tmp, &tmp)
+	x.Sub(x, &tmp)
+}
+
+// Inverse sets z to the E2-inverse of x, returns z
+//
+// if x == 0, sets and returns z = x
+func (z *E2) Inverse(x *E2) *E2 {
+	// 1/(a0 + a1⋅u) = (a0 - a1⋅u)/(a0² - 7⋅a1²)
+	var n Element
+	x.Norm(&n)
+	n.Inverse(&n)
+	z.A0.Mul(&x.A0, &n)
+	z.A1.Mul(&x.A1, &n).Neg(&z.A1)
+	return z
+}
+
+// Exp sets z=xᵏ (mod q²) and returns it
+func (z *E2) Exp(x E2, k *big.Int) *E2 {
+	if k.IsUint64() && k.Uint64() == 0 {
+		return z.SetOne()
+	}
+
+	e := k
+	if k.Sign() == -1 {
+		// negative k, we invert
+		// if k < 0: xᵏ (mod q²) == (x⁻¹)ᵏ (mod q²)
+		x.Inverse(&x)
+
+		// we negate k in a temp big.Int since
+		// Int.Bit(_) of k and -k is different
+		e = pool.BigInt.Get()
+		defer pool.BigInt.Put(e)
+		e.Neg(k)
+	}
+
+	z.Set(&x)
+
+	for i := e.BitLen() - 2; i >= 0; i-- {
+		z.Square(z)
+		if e.Bit(i) == 1 {
+			z.Mul(z, &x)
+		}
+	}
+
+	return z
+}
+
+// Legendre returns the Legendre symbol of z, that of its norm in the base field
+func (z *E2) Legendre() int {
+	var n Element
+	z.Norm(&n)
+	return n.Legendre()
+}
+
+// Sqrt sets z to the square root of x and returns z. If x is not a square,
+// Sqrt returns nil and z is not modified.
+func (z *E2) Sqrt(x *E2) *E2 {
+	// complex method: if x = a0 + a1⋅u = (b0 + b1⋅u)², then
+	// b0² = (a0 ± √(a0² - 7⋅a1²))/2 and b1 = a1/(2⋅b0)
+	if x.A1.IsZero() {
+		var res E2
+		if res.A0.Sqrt(&x.A0) == nil {
+			// a0 is not a square in 𝔽ₚ, so a0/7 is and √a0 = √(a0/7)⋅u
+			var sevenInv Element
+			sevenInv.SetUint64(e2NonResidue).Inverse(&sevenInv)
+			res.A1.Mul(&x.A0, &sevenInv)
+			if res.A1.Sqrt(&res.A1) == nil {
+				return nil
+			}
+			res.A0.SetZero()
+		}
+		return z.Set(&res)
+	}
+
+	var n, twoInv, b0, b1 Element
+	x.Norm(&n)
+	if n.Sqrt(&n) == nil {
+		return nil
+	}
+	twoInv.SetUint64(2).Inverse(&twoInv)
+	b0.Add(&x.A0, &n).Mul(&b0, &twoInv)
+	if b0.Legendre() != 1 {
+		b0.Sub(&x.A0, &n).Mul(&b0, &twoInv)
+	}
+	if b0.Sqrt(&b0) == nil {
+		return nil
+	}
+	b1.Double(&b0).Inverse(&b1).Mul(&b1, &x.A1)
+	z.A0 = b0
+	z.A1 = b1
+	return z
+}
+
+// Bytes returns the big endian encoding of A0 followed by that of A1
+func (z *E2) Bytes() (res [SizeOfE2]byte) {
+	BigEndian.PutElement((*[Bytes]byte)(res[:Bytes]), z.A0)
+	BigEndian.PutElement((*[Bytes]byte)(res[Bytes:]), z.A1)
+	return
+}
+
+// SetBytes sets z from the big endian encoding of A0 followed by that of A1.
+// It returns an error if the coordinates are not canonical.
+func (z *E2) SetBytes(e []byte) error {
+	if len(e) != SizeOfE2 {
+		return errors.New("invalid E2 encoding length")
+	}
+	var err error
+	if z.A0, err = BigEndian.Element((*[Bytes]byte)(e[:Bytes])); err != nil {
+		return err
+	}
+	z.A1, err = BigEndian.Element((*[Bytes]byte)(e[Bytes:]))
+	return err
+}
+
+// SetChallenge sets z to the element derived from a Fiat-Shamir challenge,
+// such as the output of fiatshamir.Transcript.ComputeChallenge. The challenge
+// is expanded with Hash, so that the coordinates are statistically close to
+// uniform whatever the size of the challenge.
+func (z *E2) SetChallenge(challenge []byte) (*E2, error) {
+	coordinates, err := Hash(challenge, []byte(challengeDST), 2)
+	if err != nil {
+		return nil, err
+	}
+	z.A0, z.A1 = coordinates[0], coordinates[1]
+	return z, nil
+}
+
+// BatchInvertE2 returns a new slice with every element in a inverted.
+// It uses Montgomery batch inversion trick.
+//
+// if a[i] == 0, returns result[i] = a[i]
+func BatchInvertE2(a []E2) []E2 {
+	res := make([]E2, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	zeroes := make([]bool, len(a))
+	var accumulator E2
+	accumulator.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		res[i].Set(&accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	accumulator.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			continue
+		}
+		res[i].Mul(&res[i], &accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	return res
+}
+
+// mulByE2NonResidue sets z to 7⋅x
+func mulByE2NonResidue(z, x *Element) {
+	var tmp Element
+	tmp.Double(x).Double(&tmp).Double(&tmp)
+	z.Sub(&tmp, x)
+}
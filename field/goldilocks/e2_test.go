@@ -0,0 +1,271 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldilocks
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+func genE2() gopter.Gen {
+	return func(genParams *gopter.GenParameters) *gopter.GenResult {
+		a := E2{genRandomFq(genParams), genRandomFq(genParams)}
+		return gopter.NewGenResult(&a, gopter.NoShrinker)
+	}
+}
+
+func TestE2Ops(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	genA := genE2()
+	genB := genE2()
+	genC := genE2()
+
+	properties.Property("[GOLDILOCKS] mul should match the schoolbook product modulo u²-7", prop.ForAll(
+		func(a, b *E2) bool {
+			var c, expected E2
+			var tmp Element
+			c.Mul(a, b)
+			expected.A0.Mul(&a.A0, &b.A0)
+			tmp.Mul(&a.A1, &b.A1).Mul(&tmp, new(Element).SetUint64(7))
+			expected.A0.Add(&expected.A0, &tmp)
+			expected.A1.Mul(&a.A0, &b.A1)
+			tmp.Mul(&a.A1, &b.A0)
+			expected.A1.Add(&expected.A1, &tmp)
+			return c.Equal(&expected)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("[GOLDILOCKS] mul should be distributive and commutative", prop.ForAll(
+		func(a, b, c *E2) bool {
+			var s, l, r, tmp E2
+			s.Add(b, c)
+			l.Mul(a, &s)
+			r.Mul(a, b)
+			tmp.Mul(c, a)
+			r.Add(&r, &tmp)
+			return l.Equal(&r)
+		},
+		genA,
+		genB,
+		genC,
+	))
+
+	properties.Property("[GOLDILOCKS] having the receiver as operand (mul) should output the same result", prop.ForAll(
+		func(a, b *E2) bool {
+			var c, d E2
+			d.Set(a)
+			c.Mul(a, b)
+			a.Mul(a, b)
+			b.Mul(&d, b)
+			return a.Equal(b) && a.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("[GOLDILOCKS] square(x) == x * x", prop.ForAll(
+		func(a *E2) bool {
+			var b, c E2
+			b.Square(a)
+			c.Mul(a, a)
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] inverse(x) * x == 1", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			b.Inverse(a).Mul(&b, a)
+			return b.IsOne()
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] BatchInvertE2 should output the same result as Inverse", prop.ForAll(
+		func(a, b, c *E2) bool {
+			batch := BatchInvertE2([]E2{*a, *b, {}, *c})
+			var ia, ib, ic E2
+			ia.Inverse(a)
+			ib.Inverse(b)
+			ic.Inverse(c)
+			return batch[0].Equal(&ia) && batch[1].Equal(&ib) && batch[2].IsZero() && batch[3].Equal(&ic)
+		},
+		genA,
+		genB,
+		genC,
+	))
+
+	properties.Property("[GOLDILOCKS] Frobenius(x) == x^p", prop.ForAll(
+		func(a *E2) bool {
+			var b, c E2
+			b.Frobenius(a)
+			c.Exp(*a, Modulus())
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] Norm(x) == x * Frobenius(x)", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			var n Element
+			b.Frobenius(a).Mul(&b, a)
+			a.Norm(&n)
+			return b.A1.IsZero() && b.A0.Equal(&n)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] Exp should match repeated multiplications and handle negative exponents", prop.ForAll(
+		func(a *E2) bool {
+			var b, c, d E2
+			b.Exp(*a, big.NewInt(5))
+			c.Square(a).Square(&c).Mul(&c, a)
+			d.Exp(*a, big.NewInt(-5)).Mul(&d, &b)
+			return b.Equal(&c) && d.IsOne()
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] sqrt(x²) == ±x and the Legendre symbol of a square is 1", prop.ForAll(
+		func(a *E2) bool {
+			var b, c, negA E2
+			b.Square(a)
+			if b.Legendre() != 1 || c.Sqrt(&b) == nil {
+				return false
+			}
+			negA.Neg(a)
+			return c.Equal(a) || c.Equal(&negA)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] sqrt of a base field element should be in E2", prop.ForAll(
+		func(a *E2) bool {
+			var b, c E2
+			b.SetElement(&a.A0)
+			if c.Sqrt(&b) == nil {
+				return false
+			}
+			c.Square(&c)
+			return c.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] non-squares should have no square root", prop.ForAll(
+		func(a *E2) bool {
+			var b, c E2
+			b.Square(a).A0.Add(&b.A0, new(Element).SetOne())
+			if b.Legendre() != -1 {
+				return true
+			}
+			return c.Sqrt(&b) == nil
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] SetBytes(Bytes(x)) == x", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			buf := a.Bytes()
+			if err := b.SetBytes(buf[:]); err != nil {
+				return false
+			}
+			return b.Equal(a) && b.SetBytes(buf[1:]) != nil
+		},
+		genA,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestE2SetChallenge(t *testing.T) {
+	fs := fiatshamir.NewTranscript(sha256.New(), "alpha", "beta")
+
+	var a E2
+	if _, err := a.SetRandom(); err != nil {
+		t.Fatal(err)
+	}
+	buf := a.Bytes()
+	if err := fs.Bind("alpha", buf[:]); err != nil {
+		t.Fatal(err)
+	}
+	alphaBytes, err := fs.ComputeChallenge("alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var alpha, alphaBis E2
+	if _, err := alpha.SetChallenge(alphaBytes); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := alphaBis.SetChallenge(alphaBytes); err != nil {
+		t.Fatal(err)
+	}
+	if !alpha.Equal(&alphaBis) {
+		t.Fatal("the challenge derivation should be deterministic")
+	}
+	if alpha.A0.IsZero() || alpha.A1.IsZero() {
+		t.Fatal("both coordinates should be derived from the challenge")
+	}
+
+	betaBytes, err := fs.ComputeChallenge("beta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var beta E2
+	if _, err := beta.SetChallenge(betaBytes); err != nil {
+		t.Fatal(err)
+	}
+	if beta.Equal(&alpha) {
+		t.Fatal("successive challenges should differ")
+	}
+}
+
+func BenchmarkE2Mul(b *testing.B) {
+	var x, y E2
+	x.SetRandom()
+	y.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Mul(&x, &y)
+	}
+}
+
+func BenchmarkE2Inverse(b *testing.B) {
+	var x E2
+	x.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Inverse(&x)
+	}
+}
@@ -0,0 +1,438 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldilocks
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/field/pool"
+)
+
+// SizeOfE3 is the number of bytes of a serialized E3 element
+const SizeOfE3 = 3 * Bytes
+
+// challengeDST is the domain separation tag used to derive extension field
+// elements from Fiat-Shamir challenges
+const challengeDST = "GOLDILOCKS-EXTENSION-CHALLENGE"
+
+// E3 is the degree three extension 𝔽ₚ[v]/(v³-2) of the Goldilocks field.
+// An element is A0 + A1⋅v + A2⋅v².
+type E3 struct {
+	A0, A1, A2 Element
+}
+
+var (
+	// e3FrobeniusCoeff is 2^((p-1)/3), such that v^p = e3FrobeniusCoeff⋅v
+	e3FrobeniusCoeff = NewElement(4294967295)
+	// e3FrobeniusCoeffSquare is 2^(2(p-1)/3)
+	e3FrobeniusCoeffSquare = NewElement(18446744065119617025)
+
+	// p³-1 = 2³²⋅e3SqrtT with e3SqrtT odd
+	e3SqrtT, _            = new(big.Int).SetString("1461501636310055817916238417282618014431694553085", 10)
+	e3SqrtTMinusOneBy2, _ = new(big.Int).SetString("730750818155027908958119208641309007215847276542", 10)
+)
+
+const e3SqrtTwoAdicity = 32
+
+// Equal returns true if z equals x, false otherwise
+func (z *E3) Equal(x *E3) bool {
+	return z.A0.Equal(&x.A0) && z.A1.Equal(&x.A1) && z.A2.Equal(&x.A2)
+}
+
+// Cmp compares (lexicographic order) z and x and returns:
+//
+//	-1 if z <  x
+//	 0 if z == x
+//	+1 if z >  x
+func (z *E3) Cmp(x *E3) int {
+	if a2 := z.A2.Cmp(&x.A2); a2 != 0 {
+		return a2
+	}
+	if a1 := z.A1.Cmp(&x.A1); a1 != 0 {
+		return a1
+	}
+	return z.A0.Cmp(&x.A0)
+}
+
+// SetString sets a E3 element from strings
+func (z *E3) SetString(s1, s2, s3 string) *E3 {
+	z.A0.SetString(s1)
+	z.A1.SetString(s2)
+	z.A2.SetString(s3)
+	return z
+}
+
+// SetZero sets an E3 elmt to zero
+func (z *E3) SetZero() *E3 {
+	z.A0.SetZero()
+	z.A1.SetZero()
+	z.A2.SetZero()
+	return z
+}
+
+// Set sets an E3 from x
+func (z *E3) Set(x *E3) *E3 {
+	z.A0 = x.A0
+	z.A1 = x.A1
+	z.A2 = x.A2
+	return z
+}
+
+// SetOne sets z to 1 in Montgomery form and returns z
+func (z *E3) SetOne() *E3 {
+	z.A0.SetOne()
+	z.A1.SetZero()
+	z.A2.SetZero()
+	return z
+}
+
+// SetElement sets z to the base field element x
+func (z *E3) SetElement(x *Element) *E3 {
+	z.A0.Set(x)
+	z.A1.SetZero()
+	z.A2.SetZero()
+	return z
+}
+
+// SetRandom sets a0, a1 and a2 to random values
+func (z *E3) SetRandom() (*E3, error) {
+	if _, err := z.A0.SetRandom(); err != nil {
+		return nil, err
+	}
+	if _, err := z.A1.SetRandom(); err != nil {
+		return nil, err
+	}
+	if _, err := z.A2.SetRandom(); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// IsZero returns true if z is equal to 0
+func (z *E3) IsZero() bool {
+	return z.A0.IsZero() && z.A1.IsZero() && z.A2.IsZero()
+}
+
+// IsOne returns true if z is equal to 1
+func (z *E3) IsOne() bool {
+	return z.A0.IsOne() && z.A1.IsZero() && z.A2.IsZero()
+}
+
+// Add adds two elements of E3
+func (z *E3) Add(x, y *E3) *E3 {
+	z.A0.Add(&x.A0, &y.A0)
+	z.A1.Add(&x.A1, &y.A1)
+	z.A2.Add(&x.A2, &y.A2)
+	return z
+}
+
+// Sub subtracts two elements of E3
+func (z *E3) Sub(x, y *E3) *E3 {
+	z.A0.Sub(&x.A0, &y.A0)
+	z.A1.Sub(&x.A1, &y.A1)
+	z.A2.Sub(&x.A2, &y.A2)
+	return z
+}
+
+// Double doubles an E3 element
+func (z *E3) Double(x *E3) *E3 {
+	z.A0.Double(&x.A0)
+	z.A1.Double(&x.A1)
+	z.A2.Double(&x.A2)
+	return z
+}
+
+// Neg negates an E3 element
+func (z *E3) Neg(x *E3) *E3 {
+	z.A0.Neg(&x.A0)
+	z.A1.Neg(&x.A1)
+	z.A2.Neg(&x.A2)
+	return z
+}
+
+// String implements Stringer interface for fancy printing
+func (z *E3) String() string {
+	return z.A0.String() + "+(" + z.A1.String() + ")*v+(" + z.A2.String() + ")*v**2"
+}
+
+// Mul sets z to the E3-product of x,y, returns z
+func (z *E3) Mul(x, y *E3) *E3 {
+	// Karatsuba, with v³ = 2
+	var t0, t1, t2, c0, c1, c2, tmp Element
+	t0.Mul(&x.A0, &y.A0)
+	t1.Mul(&x.A1, &y.A1)
+	t2.Mul(&x.A2, &y.A2)
+
+	// c0 = t0 + 2⋅((a1+a2)(b1+b2) - t1 - t2)
+	c0.Add(&x.A1, &x.A2)
+	tmp.Add(&y.A1, &y.A2)
+	c0.Mul(&c0, &tmp).Sub(&c0, &t1).Sub(&c0, &t2).Double(&c0).Add(&c0, &t0)
+
+	// c1 = (a0+a1)(b0+b1) - t0 - t1 + 2⋅t2
+	c1.Add(&x.A0, &x.A1)
+	tmp.Add(&y.A0, &y.A1)
+	c1.Mul(&c1, &tmp).Sub(&c1, &t0).Sub(&c1, &t1)
+	tmp.Double(&t2)
+	c1.Add(&c1, &tmp)
+
+	// c2 = (a0+a2)(b0+b2) - t0 - t2 + t1
+	c2.Add(&x.A0, &x.A2)
+	tmp.Add(&y.A0, &y.A2)
+	c2.Mul(&c2, &tmp).Sub(&c2, &t0).Sub(&c2, &t2).Add(&c2, &t1)
+
+	z.A0, z.A1, z.A2 = c0, c1, c2
+	return z
+}
+
+// Square sets z to the E3-product of x,x returns z
+func (z *E3) Square(x *E3) *E3 {
+	// (a0 + a1⋅v + a2⋅v²)² = a0² + 4⋅a1⋅a2 + (2⋅a0⋅a1 + 2⋅a2²)⋅v + (a1² + 2⋅a0⋅a2)⋅v²
+	var s0, s1, s2, c0, c1, c2 Element
+	s0.Square(&x.A0)
+	s1.Square(&x.A1)
+	s2.Square(&x.A2)
+
+	c0.Mul(&x.A1, &x.A2).Double(&c0).Double(&c0).Add(&c0, &s0)
+	c1.Mul(&x.A0, &x.A1).Add(&c1, &s2).Double(&c1)
+	c2.Mul(&x.A0, &x.A2).Double(&c2).Add(&c2, &s1)
+
+	z.A0, z.A1, z.A2 = c0, c1, c2
+	return z
+}
+
+// MulByElement multiplies an element in E3 by an element in the base field
+func (z *E3) MulByElement(x *E3, y *Element) *E3 {
+	var yCopy Element
+	yCopy.Set(y)
+	z.A0.Mul(&x.A0, &yCopy)
+	z.A1.Mul(&x.A1, &yCopy)
+	z.A2.Mul(&x.A2, &yCopy)
+	return z
+}
+
+// Frobenius sets z to x^p and returns z
+func (z *E3) Frobenius(x *E3) *E3 {
+	z.A0 = x.A0
+	z.A1.Mul(&x.A1, &e3FrobeniusCoeff)
+	z.A2.Mul(&x.A2, &e3FrobeniusCoeffSquare)
+	return z
+}
+
+// FrobeniusSquare sets z to x^(p²) and returns z
+func (z *E3) FrobeniusSquare(x *E3) *E3 {
+	z.A0 = x.A0
+	z.A1.Mul(&x.A1, &e3FrobeniusCoeffSquare)
+	z.A2.Mul(&x.A2, &e3FrobeniusCoeff)
+	return z
+}
+
+// adjugate sets c to the coefficients of x^p⋅x^(p²) and returns the norm of x
+func (z *E3) adjugate(c *E3) Element {
+	var tmp, n Element
+
+	// c0 = a0² - 2⋅a1⋅a2
+	c.A0.Square(&z.A0)
+	tmp.Mul(&z.A1, &z.A2).Double(&tmp)
+	c.A0.Sub(&c.A0, &tmp)
+
+	// c1 = 2⋅a2² - a0⋅a1
+	c.A1.Square(&z.A2).Double(&c.A1)
+	tmp.Mul(&z.A0, &z.A1)
+	c.A1.Sub(&c.A1, &tmp)
+
+	// c2 = a1² - a0⋅a2
+	c.A2.Square(&z.A1)
+	tmp.Mul(&z.A0, &z.A2)
+	c.A2.Sub(&c.A2, &tmp)
+
+	// n = a0⋅c0 + 2⋅(a2⋅c1 + a1⋅c2)
+	n.Mul(&z.A2, &c.A1)
+	tmp.Mul(&z.A1, &c.A2)
+	n.Add(&n, &tmp).Double(&n)
+	tmp.Mul(&z.A0, &c.A0)
+	n.Add(&n, &tmp)
+
+	return n
+}
+
+// Norm sets x to the norm of z, z⋅z^p⋅z^(p²)
+func (z *E3) Norm(x *Element) {
+	var c E3
+	*x = z.adjugate(&c)
+}
+
+// Inverse sets z to the E3-inverse of x, returns z
+//
+// if x == 0, sets and returns z = x
+func (z *E3) Inverse(x *E3) *E3 {
+	var c E3
+	n := x.adjugate(&c)
+	n.Inverse(&n)
+	return z.MulByElement(&c, &n)
+}
+
+// Exp sets z=xᵏ (mod q³) and returns it
+func (z *E3) Exp(x E3, k *big.Int) *E3 {
+	if k.IsUint64() && k.Uint64() == 0 {
+		return z.SetOne()
+	}
+
+	e := k
+	if k.Sign() == -1 {
+		// negative k, we invert
+		// if k < 0: xᵏ (mod q³) == (x⁻¹)ᵏ (mod q³)
+		x.Inverse(&x)
+
+		// we negate k in a temp big.Int since
+		// Int.Bit(_) of k and -k is different
+		e = pool.BigInt.Get()
+		defer pool.BigInt.Put(e)
+		e.Neg(k)
+	}
+
+	z.Set(&x)
+
+	for i := e.BitLen() - 2; i >= 0; i-- {
+		z.Square(z)
+		if e.Bit(i) == 1 {
+			z.Mul(z, &x)
+		}
+	}
+
+	return z
+}
+
+// Legendre returns the Legendre symbol of z, that of its norm in the base field
+func (z *E3) Legendre() int {
+	var n Element
+	z.Norm(&n)
+	return n.Legendre()
+}
+
+// Sqrt sets z to the square root of x and returns z. If x is not a square,
+// Sqrt returns nil and z is not modified.
+func (z *E3) Sqrt(x *E3) *E3 {
+	switch x.Legendre() {
+	case 0:
+		return z.SetZero()
+	case -1:
+		return nil
+	}
+
+	// Tonelli-Shanks, with the non-residue 7 of the base field, which is
+	// also a non-residue of E3 since its norm is 7³
+	var w, y, b, g, t E3
+	g.A0.SetUint64(7)
+	g.Exp(g, e3SqrtT)
+
+	w.Exp(*x, e3SqrtTMinusOneBy2)
+	y.Mul(x, &w)  // x^((t+1)/2)
+	b.Mul(&w, &y) // x^t
+
+	r := e3SqrtTwoAdicity
+	for !b.IsOne() {
+		m := 0
+		t.Set(&b)
+		for !t.IsOne() {
+			t.Square(&t)
+			m++
+		}
+		// g^(2^(r-m-1))
+		for i := 0; i < r-m-1; i++ {
+			g.Square(&g)
+		}
+		y.Mul(&y, &g)
+		g.Square(&g)
+		b.Mul(&b, &g)
+		r = m
+	}
+
+	return z.Set(&y)
+}
+
+// Bytes returns the big endian encodings of A0, A1 and A2
+func (z *E3) Bytes() (res [SizeOfE3]byte) {
+	BigEndian.PutElement((*[Bytes]byte)(res[:Bytes]), z.A0)
+	BigEndian.PutElement((*[Bytes]byte)(res[Bytes:2*Bytes]), z.A1)
+	BigEndian.PutElement((*[Bytes]byte)(res[2*Bytes:]), z.A2)
+	return
+}
+
+// SetBytes sets z from the big endian encodings of A0, A1 and A2.
+// It returns an error if the coordinates are not canonical.
+func (z *E3) SetBytes(e []byte) error {
+	if len(e) != SizeOfE3 {
+		return errors.New("invalid E3 encoding length")
+	}
+	var err error
+	if z.A0, err = BigEndian.Element((*[Bytes]byte)(e[:Bytes])); err != nil {
+		return err
+	}
+	if z.A1, err = BigEndian.Element((*[Bytes]byte)(e[Bytes : 2*Bytes])); err != nil {
+		return err
+	}
+	z.A2, err = BigEndian.Element((*[Bytes]byte)(e[2*Bytes:]))
+	return err
+}
+
+// SetChallenge sets z to the element derived from a Fiat-Shamir challenge,
+// such as the output of fiatshamir.Transcript.ComputeChallenge. The challenge
+// is expanded with Hash, so that the coordinates are statistically close to
+// uniform whatever the size of the challenge.
+func (z *E3) SetChallenge(challenge []byte) (*E3, error) {
+	coordinates, err := Hash(challenge, []byte(challengeDST), 3)
+	if err != nil {
+		return nil, err
+	}
+	z.A0, z.A1, z.A2 = coordinates[0], coordinates[1], coordinates[2]
+	return z, nil
+}
+
+// BatchInvertE3 returns a new slice with every element in a inverted.
+// It uses Montgomery batch inversion trick.
+//
+// if a[i] == 0, returns result[i] = a[i]
+func BatchInvertE3(a []E3) []E3 {
+	res := make([]E3, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	zeroes := make([]bool, len(a))
+	var accumulator E3
+	accumulator.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		res[i].Set(&accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	accumulator.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			continue
+		}
+		res[i].Mul(&res[i], &accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	return res
+}
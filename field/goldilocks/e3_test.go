@@ -0,0 +1,290 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldilocks
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+func genE3() gopter.Gen {
+	return func(genParams *gopter.GenParameters) *gopter.GenResult {
+		a := E3{genRandomFq(genParams), genRandomFq(genParams), genRandomFq(genParams)}
+		return gopter.NewGenResult(&a, gopter.NoShrinker)
+	}
+}
+
+func TestE3Ops(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	genA := genE3()
+	genB := genE3()
+	genC := genE3()
+
+	properties.Property("[GOLDILOCKS] mul should match the schoolbook product modulo v³-2", prop.ForAll(
+		func(a, b *E3) bool {
+			x := [3]Element{a.A0, a.A1, a.A2}
+			y := [3]Element{b.A0, b.A1, b.A2}
+			var prod [5]Element
+			var tmp Element
+			for i := range x {
+				for j := range y {
+					tmp.Mul(&x[i], &y[j])
+					prod[i+j].Add(&prod[i+j], &tmp)
+				}
+			}
+			var c, expected E3
+			c.Mul(a, b)
+			expected.A0.Double(&prod[3]).Add(&expected.A0, &prod[0])
+			expected.A1.Double(&prod[4]).Add(&expected.A1, &prod[1])
+			expected.A2 = prod[2]
+			return c.Equal(&expected)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("[GOLDILOCKS] mul should be distributive and commutative", prop.ForAll(
+		func(a, b, c *E3) bool {
+			var s, l, r, tmp E3
+			s.Add(b, c)
+			l.Mul(a, &s)
+			r.Mul(a, b)
+			tmp.Mul(c, a)
+			r.Add(&r, &tmp)
+			return l.Equal(&r)
+		},
+		genA,
+		genB,
+		genC,
+	))
+
+	properties.Property("[GOLDILOCKS] having the receiver as operand (mul) should output the same result", prop.ForAll(
+		func(a, b *E3) bool {
+			var c, d E3
+			d.Set(a)
+			c.Mul(a, b)
+			a.Mul(a, b)
+			b.Mul(&d, b)
+			return a.Equal(b) && a.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("[GOLDILOCKS] square(x) == x * x", prop.ForAll(
+		func(a *E3) bool {
+			var b, c E3
+			b.Square(a)
+			c.Mul(a, a)
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] inverse(x) * x == 1", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Inverse(a).Mul(&b, a)
+			return b.IsOne()
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] BatchInvertE3 should output the same result as Inverse", prop.ForAll(
+		func(a, b, c *E3) bool {
+			batch := BatchInvertE3([]E3{*a, *b, {}, *c})
+			var ia, ib, ic E3
+			ia.Inverse(a)
+			ib.Inverse(b)
+			ic.Inverse(c)
+			return batch[0].Equal(&ia) && batch[1].Equal(&ib) && batch[2].IsZero() && batch[3].Equal(&ic)
+		},
+		genA,
+		genB,
+		genC,
+	))
+
+	properties.Property("[GOLDILOCKS] Frobenius(x) == x^p", prop.ForAll(
+		func(a *E3) bool {
+			var b, c E3
+			b.Frobenius(a)
+			c.Exp(*a, Modulus())
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] FrobeniusSquare(x) == Frobenius(Frobenius(x))", prop.ForAll(
+		func(a *E3) bool {
+			var b, c E3
+			b.FrobeniusSquare(a)
+			c.Frobenius(a).Frobenius(&c)
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] Norm(x) == x * Frobenius(x) * FrobeniusSquare(x)", prop.ForAll(
+		func(a *E3) bool {
+			var b, c E3
+			var n Element
+			b.Frobenius(a).Mul(&b, a)
+			c.FrobeniusSquare(a)
+			b.Mul(&b, &c)
+			a.Norm(&n)
+			return b.A1.IsZero() && b.A2.IsZero() && b.A0.Equal(&n)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] Exp should match repeated multiplications and handle negative exponents", prop.ForAll(
+		func(a *E3) bool {
+			var b, c, d E3
+			b.Exp(*a, big.NewInt(5))
+			c.Square(a).Square(&c).Mul(&c, a)
+			d.Exp(*a, big.NewInt(-5)).Mul(&d, &b)
+			return b.Equal(&c) && d.IsOne()
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] sqrt(x²) == ±x and the Legendre symbol of a square is 1", prop.ForAll(
+		func(a *E3) bool {
+			var b, c, negA E3
+			b.Square(a)
+			if b.Legendre() != 1 || c.Sqrt(&b) == nil {
+				return false
+			}
+			negA.Neg(a)
+			return c.Equal(a) || c.Equal(&negA)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] sqrt of a square base field element should be in E3", prop.ForAll(
+		func(a *E3) bool {
+			var b, c E3
+			b.SetElement(&a.A0)
+			b.Square(&b)
+			if c.Sqrt(&b) == nil {
+				return false
+			}
+			c.Square(&c)
+			return c.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] non-squares should have no square root", prop.ForAll(
+		func(a *E3) bool {
+			var b, c E3
+			b.Square(a).A0.Add(&b.A0, new(Element).SetOne())
+			if b.Legendre() != -1 {
+				return true
+			}
+			return c.Sqrt(&b) == nil
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] SetBytes(Bytes(x)) == x", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			buf := a.Bytes()
+			if err := b.SetBytes(buf[:]); err != nil {
+				return false
+			}
+			return b.Equal(a) && b.SetBytes(buf[1:]) != nil
+		},
+		genA,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestE3SetChallenge(t *testing.T) {
+	fs := fiatshamir.NewTranscript(sha256.New(), "alpha", "beta")
+
+	var a E3
+	if _, err := a.SetRandom(); err != nil {
+		t.Fatal(err)
+	}
+	buf := a.Bytes()
+	if err := fs.Bind("alpha", buf[:]); err != nil {
+		t.Fatal(err)
+	}
+	alphaBytes, err := fs.ComputeChallenge("alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var alpha, alphaBis E3
+	if _, err := alpha.SetChallenge(alphaBytes); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := alphaBis.SetChallenge(alphaBytes); err != nil {
+		t.Fatal(err)
+	}
+	if !alpha.Equal(&alphaBis) {
+		t.Fatal("the challenge derivation should be deterministic")
+	}
+	if alpha.A0.IsZero() || alpha.A1.IsZero() || alpha.A2.IsZero() {
+		t.Fatal("both coordinates should be derived from the challenge")
+	}
+
+	betaBytes, err := fs.ComputeChallenge("beta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var beta E3
+	if _, err := beta.SetChallenge(betaBytes); err != nil {
+		t.Fatal(err)
+	}
+	if beta.Equal(&alpha) {
+		t.Fatal("successive challenges should differ")
+	}
+}
+
+func BenchmarkE3Mul(b *testing.B) {
+	var x, y E3
+	x.SetRandom()
+	y.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Mul(&x, &y)
+	}
+}
+
+func BenchmarkE3Inverse(b *testing.B) {
+	var x E3
+	x.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Inverse(&x)
+	}
+}
@@ -0,0 +1,385 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldilocks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+)
+
+// VectorE2 represents a slice of E2.
+//
+// It implements the following interfaces:
+//   - Stringer
+//   - io.WriterTo
+//   - io.ReaderFrom
+//   - encoding.BinaryMarshaler
+//   - encoding.BinaryUnmarshaler
+//
+// Since the FFT domains of the Goldilocks field live in the base field, the
+// discrete Fourier transform of a VectorE2 is that of its coordinates: Split
+// and Join convert between the packed form and the two coordinate vectors,
+// which can be passed directly to the fft package.
+type VectorE2 []E2
+
+// VectorE3 represents a slice of E3. See VectorE2.
+type VectorE3 []E3
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (vector *VectorE2) MarshalBinary() (data []byte, err error) {
+	var buf bytes.Buffer
+
+	if _, err = vector.WriteTo(&buf); err != nil {
+		return
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (vector *VectorE2) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	_, err := vector.ReadFrom(r)
+	return err
+}
+
+// WriteTo implements io.WriterTo and writes a vector of big endian encoded E2.
+// Length of the vector is encoded as a uint32 on the first 4 bytes.
+func (vector *VectorE2) WriteTo(w io.Writer) (int64, error) {
+	// encode slice length
+	if err := binary.Write(w, binary.BigEndian, uint32(len(*vector))); err != nil {
+		return 0, err
+	}
+
+	n := int64(4)
+
+	for i := 0; i < len(*vector); i++ {
+		buf := (*vector)[i].Bytes()
+		m, err := w.Write(buf[:])
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// ReadFrom implements io.ReaderFrom and reads a vector of big endian encoded E2.
+// Length of the vector must be encoded as a uint32 on the first 4 bytes.
+func (vector *VectorE2) ReadFrom(r io.Reader) (int64, error) {
+
+	var buf [SizeOfE2]byte
+	if read, err := io.ReadFull(r, buf[:4]); err != nil {
+		return int64(read), err
+	}
+	sliceLen := binary.BigEndian.Uint32(buf[:4])
+
+	n := int64(4)
+	(*vector) = make(VectorE2, sliceLen)
+
+	for i := 0; i < int(sliceLen); i++ {
+		read, err := io.ReadFull(r, buf[:])
+		n += int64(read)
+		if err != nil {
+			return n, err
+		}
+		if err = (*vector)[i].SetBytes(buf[:]); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// String implements fmt.Stringer interface
+func (vector VectorE2) String() string {
+	var sbb strings.Builder
+	sbb.WriteByte('[')
+	for i := 0; i < len(vector); i++ {
+		sbb.WriteString(vector[i].String())
+		if i != len(vector)-1 {
+			sbb.WriteByte(',')
+		}
+	}
+	sbb.WriteByte(']')
+	return sbb.String()
+}
+
+// Add adds two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *VectorE2) Add(a, b VectorE2) {
+	checkLengths(len(*vector), len(a), len(b))
+	for i := range a {
+		(*vector)[i].Add(&a[i], &b[i])
+	}
+}
+
+// Sub subtracts two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *VectorE2) Sub(a, b VectorE2) {
+	checkLengths(len(*vector), len(a), len(b))
+	for i := range a {
+		(*vector)[i].Sub(&a[i], &b[i])
+	}
+}
+
+// ScalarMul multiplies a vector by a scalar element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *VectorE2) ScalarMul(a VectorE2, b *E2) {
+	checkLengths(len(*vector), len(a), len(a))
+	for i := range a {
+		(*vector)[i].Mul(&a[i], b)
+	}
+}
+
+// MulByVector multiplies a vector element-wise by a vector of the base field
+// and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *VectorE2) MulByVector(a VectorE2, b Vector) {
+	checkLengths(len(*vector), len(a), len(b))
+	for i := range a {
+		(*vector)[i].MulByElement(&a[i], &b[i])
+	}
+}
+
+// Sum computes the sum of all elements in the vector.
+func (vector *VectorE2) Sum() (res E2) {
+	for i := range *vector {
+		res.Add(&res, &(*vector)[i])
+	}
+	return
+}
+
+// InnerProduct computes the inner product of two vectors.
+// It panics if the vectors don't have the same length.
+func (vector *VectorE2) InnerProduct(other VectorE2) (res E2) {
+	checkLengths(len(*vector), len(other), len(other))
+	var tmp E2
+	for i := range *vector {
+		tmp.Mul(&(*vector)[i], &other[i])
+		res.Add(&res, &tmp)
+	}
+	return
+}
+
+// Mul multiplies two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *VectorE2) Mul(a, b VectorE2) {
+	checkLengths(len(*vector), len(a), len(b))
+	for i := range a {
+		(*vector)[i].Mul(&a[i], &b[i])
+	}
+}
+
+// Split returns the coordinate vectors c such that vector[i] = c[0][i] + c[1][i]⋅u
+func (vector VectorE2) Split() (c [2]Vector) {
+	for j := range c {
+		c[j] = make(Vector, len(vector))
+	}
+	for i := range vector {
+		c[0][i] = vector[i].A0
+		c[1][i] = vector[i].A1
+	}
+	return
+}
+
+// Join sets the vector from its coordinate vectors, as returned by Split.
+// It panics if the coordinate vectors don't have the same length.
+func (vector *VectorE2) Join(c [2]Vector) {
+	checkLengths(len(c[0]), len(c[1]), len(c[1]))
+	(*vector) = make(VectorE2, len(c[0]))
+	for i := range *vector {
+		(*vector)[i].A0 = c[0][i]
+		(*vector)[i].A1 = c[1][i]
+	}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (vector *VectorE3) MarshalBinary() (data []byte, err error) {
+	var buf bytes.Buffer
+
+	if _, err = vector.WriteTo(&buf); err != nil {
+		return
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (vector *VectorE3) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	_, err := vector.ReadFrom(r)
+	return err
+}
+
+// WriteTo implements io.WriterTo and writes a vector of big endian encoded E3.
+// Length of the vector is encoded as a uint32 on the first 4 bytes.
+func (vector *VectorE3) WriteTo(w io.Writer) (int64, error) {
+	// encode slice length
+	if err := binary.Write(w, binary.BigEndian, uint32(len(*vector))); err != nil {
+		return 0, err
+	}
+
+	n := int64(4)
+
+	for i := 0; i < len(*vector); i++ {
+		buf := (*vector)[i].Bytes()
+		m, err := w.Write(buf[:])
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// ReadFrom implements io.ReaderFrom and reads a vector of big endian encoded E3.
+// Length of the vector must be encoded as a uint32 on the first 4 bytes.
+func (vector *VectorE3) ReadFrom(r io.Reader) (int64, error) {
+
+	var buf [SizeOfE3]byte
+	if read, err := io.ReadFull(r, buf[:4]); err != nil {
+		return int64(read), err
+	}
+	sliceLen := binary.BigEndian.Uint32(buf[:4])
+
+	n := int64(4)
+	(*vector) = make(VectorE3, sliceLen)
+
+	for i := 0; i < int(sliceLen); i++ {
+		read, err := io.ReadFull(r, buf[:])
+		n += int64(read)
+		if err != nil {
+			return n, err
+		}
+		if err = (*vector)[i].SetBytes(buf[:]); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// String implements fmt.Stringer interface
+func (vector VectorE3) String() string {
+	var sbb strings.Builder
+	sbb.WriteByte('[')
+	for i := 0; i < len(vector); i++ {
+		sbb.WriteString(vector[i].String())
+		if i != len(vector)-1 {
+			sbb.WriteByte(',')
+		}
+	}
+	sbb.WriteByte(']')
+	return sbb.String()
+}
+
+// Add adds two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *VectorE3) Add(a, b VectorE3) {
+	checkLengths(len(*vector), len(a), len(b))
+	for i := range a {
+		(*vector)[i].Add(&a[i], &b[i])
+	}
+}
+
+// Sub subtracts two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *VectorE3) Sub(a, b VectorE3) {
+	checkLengths(len(*vector), len(a), len(b))
+	for i := range a {
+		(*vector)[i].Sub(&a[i], &b[i])
+	}
+}
+
+// ScalarMul multiplies a vector by a scalar element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *VectorE3) ScalarMul(a VectorE3, b *E3) {
+	checkLengths(len(*vector), len(a), len(a))
+	for i := range a {
+		(*vector)[i].Mul(&a[i], b)
+	}
+}
+
+// MulByVector multiplies a vector element-wise by a vector of the base field
+// and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *VectorE3) MulByVector(a VectorE3, b Vector) {
+	checkLengths(len(*vector), len(a), len(b))
+	for i := range a {
+		(*vector)[i].MulByElement(&a[i], &b[i])
+	}
+}
+
+// Sum computes the sum of all elements in the vector.
+func (vector *VectorE3) Sum() (res E3) {
+	for i := range *vector {
+		res.Add(&res, &(*vector)[i])
+	}
+	return
+}
+
+// InnerProduct computes the inner product of two vectors.
+// It panics if the vectors don't have the same length.
+func (vector *VectorE3) InnerProduct(other VectorE3) (res E3) {
+	checkLengths(len(*vector), len(other), len(other))
+	var tmp E3
+	for i := range *vector {
+		tmp.Mul(&(*vector)[i], &other[i])
+		res.Add(&res, &tmp)
+	}
+	return
+}
+
+// Mul multiplies two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *VectorE3) Mul(a, b VectorE3) {
+	checkLengths(len(*vector), len(a), len(b))
+	for i := range a {
+		(*vector)[i].Mul(&a[i], &b[i])
+	}
+}
+
+// Split returns the coordinate vectors c such that
+// vector[i] = c[0][i] + c[1][i]⋅v + c[2][i]⋅v²
+func (vector VectorE3) Split() (c [3]Vector) {
+	for j := range c {
+		c[j] = make(Vector, len(vector))
+	}
+	for i := range vector {
+		c[0][i] = vector[i].A0
+		c[1][i] = vector[i].A1
+		c[2][i] = vector[i].A2
+	}
+	return
+}
+
+// Join sets the vector from its coordinate vectors, as returned by Split.
+// It panics if the coordinate vectors don't have the same length.
+func (vector *VectorE3) Join(c [3]Vector) {
+	checkLengths(len(c[0]), len(c[1]), len(c[2]))
+	(*vector) = make(VectorE3, len(c[0]))
+	for i := range *vector {
+		(*vector)[i].A0 = c[0][i]
+		(*vector)[i].A1 = c[1][i]
+		(*vector)[i].A2 = c[2][i]
+	}
+}
+
+func checkLengths(a, b, c int) {
+	if a != b || a != c {
+		panic("vectors don't have the same length")
+	}
+}
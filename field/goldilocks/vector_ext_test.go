@@ -0,0 +1,112 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldilocks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectorE2(t *testing.T) {
+	assert := require.New(t)
+
+	const n = 17
+	a, b := make(VectorE2, n), make(VectorE2, n)
+	base := make(Vector, n)
+	for i := 0; i < n; i++ {
+		a[i].SetRandom()
+		b[i].SetRandom()
+		base[i].SetRandom()
+	}
+
+	// serialization
+	data, err := a.MarshalBinary()
+	assert.NoError(err)
+	assert.Equal(4+n*SizeOfE2, len(data))
+	var c VectorE2
+	assert.NoError(c.UnmarshalBinary(data))
+	assert.Equal(a, c)
+
+	// packing
+	c.Join(a.Split())
+	assert.Equal(a, c)
+
+	// arithmetic
+	var expected, tmp E2
+	for i := 0; i < n; i++ {
+		tmp.Mul(&a[i], &b[i])
+		expected.Add(&expected, &tmp)
+	}
+	assert.Equal(expected, a.InnerProduct(b))
+
+	c.Mul(a, b)
+	assert.Equal(expected, c.Sum())
+
+	c.Add(a, b)
+	c.Sub(c, b)
+	assert.Equal(a, c)
+
+	c.MulByVector(a, base)
+	coordinates := a.Split()
+	coordinates[0].Mul(coordinates[0], base)
+	coordinates[1].Mul(coordinates[1], base)
+	var d VectorE2
+	d.Join(coordinates)
+	assert.Equal(d, c)
+
+	assert.Panics(func() { c.Add(a, b[1:]) })
+}
+
+func TestVectorE3(t *testing.T) {
+	assert := require.New(t)
+
+	const n = 17
+	a, b := make(VectorE3, n), make(VectorE3, n)
+	for i := 0; i < n; i++ {
+		a[i].SetRandom()
+		b[i].SetRandom()
+	}
+
+	// serialization
+	data, err := a.MarshalBinary()
+	assert.NoError(err)
+	assert.Equal(4+n*SizeOfE3, len(data))
+	var c VectorE3
+	assert.NoError(c.UnmarshalBinary(data))
+	assert.Equal(a, c)
+
+	// a non canonical coordinate is rejected
+	for i := 0; i < Bytes; i++ {
+		data[4+i] = 0xff
+	}
+	assert.Error(c.UnmarshalBinary(data))
+
+	// packing
+	c.Join(a.Split())
+	assert.Equal(a, c)
+
+	// arithmetic
+	var expected, tmp E3
+	for i := 0; i < n; i++ {
+		tmp.Mul(&a[i], &b[i])
+		expected.Add(&expected, &tmp)
+	}
+	assert.Equal(expected, a.InnerProduct(b))
+
+	c.ScalarMul(a, &b[0])
+	tmp.Mul(&a[n-1], &b[0])
+	assert.Equal(tmp, c[n-1])
+}
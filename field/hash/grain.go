@@ -0,0 +1,93 @@
+package hash
+
+import (
+	"math/big"
+)
+
+// GrainLFSR is the Grain LFSR in self-shrinking mode used to derive the round
+// constants and matrices of the Poseidon and Poseidon2 permutations over a
+// prime field.
+// https://extgit.iaik.tugraz.at/krypto/hadeshash/-/blob/master/code/generate_parameters_grain.sage
+type GrainLFSR struct {
+	state   [80]uint8
+	modulus *big.Int
+	nbBits  int
+}
+
+// NewGrainLFSR returns a Grain LFSR initialized with the parameters of a
+// permutation with the x^α S-box over the prime field of the given modulus.
+//
+// The 80 bits of the initial state are
+//
+//	field (2 bits, 1 for 𝔽ₚ) ∥ sbox (4 bits, 0 for x^α) ∥ n (12 bits) ∥ t (12 bits) ∥ R_F (10 bits) ∥ R_P (10 bits) ∥ 1…1 (30 bits)
+//
+// and the first 160 output bits are discarded.
+func NewGrainLFSR(modulus *big.Int, width, nbFullRounds, nbPartialRounds int) *GrainLFSR {
+	g := &GrainLFSR{
+		modulus: new(big.Int).Set(modulus),
+		nbBits:  modulus.BitLen(),
+	}
+	i := 0
+	appendBits := func(v, n int) {
+		for j := n - 1; j >= 0; j-- {
+			g.state[i] = uint8((v >> j) & 1)
+			i++
+		}
+	}
+	appendBits(1, 2)
+	appendBits(0, 4)
+	appendBits(g.nbBits, 12)
+	appendBits(width, 12)
+	appendBits(nbFullRounds, 10)
+	appendBits(nbPartialRounds, 10)
+	appendBits(1<<30-1, 30)
+
+	for j := 0; j < 160; j++ {
+		g.clock()
+	}
+	return g
+}
+
+// clock updates the state and returns the new bit
+func (g *GrainLFSR) clock() uint8 {
+	b := g.state[62] ^ g.state[51] ^ g.state[38] ^ g.state[23] ^ g.state[13] ^ g.state[0]
+	copy(g.state[:], g.state[1:])
+	g.state[79] = b
+	return b
+}
+
+// nextBit returns the next bit of the self-shrinking generator: bits are
+// produced in pairs, and the second one is output only if the first one is 1.
+func (g *GrainLFSR) nextBit() uint8 {
+	for {
+		first, second := g.clock(), g.clock()
+		if first == 1 {
+			return second
+		}
+	}
+}
+
+// NextBigInt returns the integer made of the next n output bits, where n is
+// the bit size of the modulus, most significant bit first. The result is not
+// reduced.
+func (g *GrainLFSR) NextBigInt() *big.Int {
+	res := new(big.Int)
+	for i := 0; i < g.nbBits; i++ {
+		res.Lsh(res, 1)
+		if g.nextBit() == 1 {
+			res.SetBit(res, 0, 1)
+		}
+	}
+	return res
+}
+
+// NextFieldElement returns the next integer smaller than the modulus, using
+// rejection sampling on NextBigInt.
+func (g *GrainLFSR) NextFieldElement() *big.Int {
+	for {
+		res := g.NextBigInt()
+		if res.Cmp(g.modulus) < 0 {
+			return res
+		}
+	}
+}
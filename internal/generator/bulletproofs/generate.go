@@ -0,0 +1,24 @@
+package bulletproofs
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+	// bulletproofs range proofs
+	conf.Package = "bulletproofs"
+	baseDir = filepath.Join(baseDir, conf.Package)
+
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "bulletproofs.go"), Templates: []string{"bulletproofs.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ipa.go"), Templates: []string{"ipa.go.tmpl"}},
+		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, "bulletproofs_test.go"), Templates: []string{"bulletproofs.test.go.tmpl"}},
+	}
+	return bgen.Generate(conf, conf.Package, "./bulletproofs/template", entries...)
+
+}
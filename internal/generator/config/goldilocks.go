@@ -0,0 +1,8 @@
+package config
+
+// GOLDILOCKS is not a curve: it describes the Goldilocks field, for which the
+// fft, polynomial and fri packages are generated. It is not registered in Curves.
+var GOLDILOCKS = Curve{
+	Name:      "goldilocks",
+	FrModulus: "18446744069414584321",
+}
@@ -0,0 +1,23 @@
+package poseidon
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+
+	conf.Package = "poseidon"
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "poseidon.go"), Templates: []string{"poseidon.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hash.go"), Templates: []string{"hash.go.tmpl"}},
+		{File: filepath.Join(baseDir, "options.go"), Templates: []string{"options.go.tmpl"}},
+		{File: filepath.Join(baseDir, "poseidon_test.go"), Templates: []string{"poseidon.test.go.tmpl"}},
+	}
+
+	return bgen.Generate(conf, conf.Package, "./crypto/hash/poseidon/template", entries...)
+
+}
@@ -54,12 +54,12 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 		return err
 	}
 
-	// put the generator in the parent dir (fr)
+	// put the generator in the parent dir (fr, or the field package)
 	frDir := filepath.Dir(baseDir)
 	entries = []bavard.Entry{
 		{File: filepath.Join(frDir, "generator.go"), Templates: []string{"fr.generator.go.tmpl"}},
 	}
-	return bgen.GenerateWithOptions(conf, "fr", "./fft/template/", bavardOpts, entries...)
+	return bgen.GenerateWithOptions(conf, filepath.Base(frDir), "./fft/template/", bavardOpts, entries...)
 }
 
 func anyToUint64(x any) uint64 {
@@ -0,0 +1,22 @@
+package gemini
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+
+	// gemini reduction of multilinear openings to kzg
+	conf.Package = "gemini"
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "gemini.go"), Templates: []string{"gemini.go.tmpl"}},
+		{File: filepath.Join(baseDir, "gemini_test.go"), Templates: []string{"gemini.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+	}
+	return bgen.Generate(conf, conf.Package, "./gemini/template/", entries...)
+
+}
@@ -9,6 +9,7 @@ import (
 type Config struct {
 	config.FieldDependency
 	GenerateTests           bool
+	GenerateMarshal         bool
 	RetainTestCaseRawInfo   bool
 	OutsideGkrPackage       bool
 	TestVectorsRelativePath string
@@ -24,5 +25,11 @@ func Generate(config Config, baseDir string, bgen *bavard.BatchGenerator) error
 			bavard.Entry{File: filepath.Join(baseDir, "gkr_test.go"), Templates: []string{"gkr.test.go.tmpl", "gkr.test.vectors.go.tmpl"}})
 	}
 
+	if config.GenerateMarshal {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "marshal_test.go"), Templates: []string{"marshal.test.go.tmpl"}})
+	}
+
 	return bgen.Generate(config, "gkr", "./gkr/template/", entries...)
 }
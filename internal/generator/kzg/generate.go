@@ -17,6 +17,25 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 		{File: filepath.Join(baseDir, "kzg_test.go"), Templates: []string{"kzg.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
 		{File: filepath.Join(baseDir, "utils.go"), Templates: []string{"utils.go.tmpl"}},
+		{File: filepath.Join(baseDir, "fk20.go"), Templates: []string{"fk20.go.tmpl"}},
+		{File: filepath.Join(baseDir, "lagrange.go"), Templates: []string{"lagrange.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hiding.go"), Templates: []string{"hiding.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ceremony.go"), Templates: []string{"ceremony.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ceremony_test.go"), Templates: []string{"ceremony.test.go.tmpl"}},
+	}
+
+	// importers of the transcripts of existing ceremonies on their curves
+	switch conf.Name {
+	case config.BN254.Name:
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "import_ptau.go"), Templates: []string{"import_ptau.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "import_ignition.go"), Templates: []string{"import_ignition.go.tmpl"}},
+		)
+	case config.BLS12_381.Name:
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "import_ptau.go"), Templates: []string{"import_ptau.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "import_ethereum.go"), Templates: []string{"import_ethereum.go.tmpl"}},
+		)
 	}
 	return bgen.Generate(conf, conf.Package, "./kzg/template/", entries...)
 
@@ -10,8 +10,12 @@ import (
 	"github.com/consensys/bavard"
 	"github.com/consensys/gnark-crypto/field/generator"
 	field "github.com/consensys/gnark-crypto/field/generator/config"
+	"github.com/consensys/gnark-crypto/internal/generator/bls"
+	"github.com/consensys/gnark-crypto/internal/generator/bulletproofs"
 	"github.com/consensys/gnark-crypto/internal/generator/config"
 	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/mimc"
+	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/poseidon"
+	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/poseidon2"
 	"github.com/consensys/gnark-crypto/internal/generator/ecc"
 	"github.com/consensys/gnark-crypto/internal/generator/ecdsa"
 	"github.com/consensys/gnark-crypto/internal/generator/edwards"
@@ -19,20 +23,24 @@ import (
 	"github.com/consensys/gnark-crypto/internal/generator/fflonk"
 	"github.com/consensys/gnark-crypto/internal/generator/fft"
 	fri "github.com/consensys/gnark-crypto/internal/generator/fri/template"
+	"github.com/consensys/gnark-crypto/internal/generator/gemini"
 	"github.com/consensys/gnark-crypto/internal/generator/gkr"
 	"github.com/consensys/gnark-crypto/internal/generator/hash_to_field"
 	"github.com/consensys/gnark-crypto/internal/generator/iop"
 	"github.com/consensys/gnark-crypto/internal/generator/kzg"
+	"github.com/consensys/gnark-crypto/internal/generator/mlkzg"
 	"github.com/consensys/gnark-crypto/internal/generator/pairing"
 	"github.com/consensys/gnark-crypto/internal/generator/pedersen"
 	"github.com/consensys/gnark-crypto/internal/generator/permutation"
 	"github.com/consensys/gnark-crypto/internal/generator/plookup"
 	"github.com/consensys/gnark-crypto/internal/generator/polynomial"
+	"github.com/consensys/gnark-crypto/internal/generator/schnorr"
 	"github.com/consensys/gnark-crypto/internal/generator/shplonk"
 	"github.com/consensys/gnark-crypto/internal/generator/sis"
 	"github.com/consensys/gnark-crypto/internal/generator/sumcheck"
 	"github.com/consensys/gnark-crypto/internal/generator/test_vector_utils"
 	"github.com/consensys/gnark-crypto/internal/generator/tower"
+	"github.com/consensys/gnark-crypto/internal/generator/transcript"
 )
 
 const (
@@ -106,6 +114,11 @@ func main() {
 			assertNoError(ecc.Generate(conf, curveDir, bgen))
 
 			if conf.Equal(config.SECP256K1) {
+				// generate schnorr (BIP-340)
+				assertNoError(schnorr.Generate(conf, curveDir, bgen))
+
+				// generate bulletproofs range proofs
+				assertNoError(bulletproofs.Generate(conf, curveDir, bgen))
 				return
 			}
 
@@ -125,9 +138,23 @@ func main() {
 				assertNoError(sis.Generate(conf, filepath.Join(curveDir, "fr", "sis"), bgen))
 			}
 
+			if conf.Equal(config.BN254) || conf.Equal(config.BLS12_381) || conf.Equal(config.BLS12_377) {
+				// generate bls signatures
+				assertNoError(bls.Generate(conf, curveDir, bgen))
+			}
+
+			// generate typed Fiat-Shamir transcripts
+			assertNoError(transcript.Generate(conf, curveDir, bgen))
+
 			// generate kzg on fr
 			assertNoError(kzg.Generate(conf, filepath.Join(curveDir, "kzg"), bgen))
 
+			// generate multilinear kzg on fr
+			assertNoError(mlkzg.Generate(conf, filepath.Join(curveDir, "mlkzg"), bgen))
+
+			// generate gemini on fr, opening multilinear polynomials with kzg
+			assertNoError(gemini.Generate(conf, filepath.Join(curveDir, "gemini"), bgen))
+
 			// generate shplonk on fr
 			assertNoError(shplonk.Generate(conf, filepath.Join(curveDir, "shplonk"), bgen))
 
@@ -137,6 +164,9 @@ func main() {
 			// generate pedersen on fr
 			assertNoError(pedersen.Generate(conf, filepath.Join(curveDir, "fr", "pedersen"), bgen))
 
+			// generate bulletproofs range proofs
+			assertNoError(bulletproofs.Generate(conf, curveDir, bgen))
+
 			// generate plookup on fr
 			assertNoError(plookup.Generate(conf, filepath.Join(curveDir, "fr", "plookup"), bgen))
 
@@ -146,6 +176,10 @@ func main() {
 			// generate mimc on fr
 			assertNoError(mimc.Generate(conf, filepath.Join(curveDir, "fr", "mimc"), bgen))
 
+			// generate poseidon and poseidon2 on fr
+			assertNoError(poseidon.Generate(conf, filepath.Join(curveDir, "fr", "poseidon"), bgen))
+			assertNoError(poseidon2.Generate(conf, filepath.Join(curveDir, "fr", "poseidon2"), bgen))
+
 			frInfo := config.FieldDependency{
 				FieldPackagePath: "github.com/consensys/gnark-crypto/ecc/" + conf.Name + "/fr",
 				FieldPackageName: "fr",
@@ -159,12 +193,13 @@ func main() {
 			assertNoError(fri.Generate(conf, filepath.Join(curveDir, "fr", "fri"), bgen))
 
 			// generate sumcheck on fr
-			assertNoError(sumcheck.Generate(frInfo, filepath.Join(curveDir, "fr", "sumcheck"), bgen))
+			assertNoError(sumcheck.Generate(frInfo, filepath.Join(curveDir, "fr", "sumcheck"), true, bgen))
 
 			// generate gkr on fr
 			assertNoError(gkr.Generate(gkr.Config{
 				FieldDependency:         frInfo,
 				GenerateTests:           true,
+				GenerateMarshal:         true,
 				TestVectorsRelativePath: "../../../../internal/generator/gkr/test_vectors",
 			}, filepath.Join(curveDir, "fr", "gkr"), bgen))
 
@@ -203,10 +238,37 @@ func main() {
 
 			// generate eddsa on companion curves
 			assertNoError(eddsa.Generate(conf, curveDir, bgen))
+
+			if conf.Package == "bandersnatch" {
+				// generate the scalar field of bandersnatch, used by the ipa commitment scheme
+				frConf, err := field.NewFieldConfig("fr", "Element", conf.Order, true)
+				assertNoError(err)
+				frIncludePath := filepath.Join(baseDir, "..", "..", "field", "asm")
+				assertNoError(generator.GenerateFF(frConf, filepath.Join(curveDir, "fr"), asmDirBuildPath, frIncludePath))
+			}
 		}(conf)
 
 	}
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// generate fft, polynomial and fri on the goldilocks field
+		conf := config.GOLDILOCKS
+		fieldDir := filepath.Join(baseDir, "field", conf.Name)
+
+		assertNoError(fft.Generate(conf, filepath.Join(fieldDir, "fft"), bgen))
+		assertNoError(fri.Generate(conf, filepath.Join(fieldDir, "fri"), bgen))
+
+		goldilocksInfo := config.FieldDependency{
+			FieldPackagePath: "github.com/consensys/gnark-crypto/field/goldilocks",
+			FieldPackageName: "goldilocks",
+			ElementType:      "goldilocks.Element",
+		}
+		assertNoError(polynomial.Generate(goldilocksInfo, filepath.Join(fieldDir, "polynomial"), true, bgen))
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -0,0 +1,22 @@
+package mlkzg
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+
+	// multilinear kzg commitment scheme
+	conf.Package = "mlkzg"
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "mlkzg.go"), Templates: []string{"mlkzg.go.tmpl"}},
+		{File: filepath.Join(baseDir, "mlkzg_test.go"), Templates: []string{"mlkzg.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+	}
+	return bgen.Generate(conf, conf.Package, "./mlkzg/template/", entries...)
+
+}
@@ -6,10 +6,18 @@ import (
 	"path/filepath"
 )
 
-func Generate(conf config.FieldDependency, baseDir string, bgen *bavard.BatchGenerator) error {
+func Generate(conf config.FieldDependency, baseDir string, generateMarshal bool, bgen *bavard.BatchGenerator) error {
 	entries := []bavard.Entry{
 		{File: filepath.Join(baseDir, "sumcheck.go"), Templates: []string{"sumcheck.go.tmpl"}},
+		{File: filepath.Join(baseDir, "virtual.go"), Templates: []string{"virtual.go.tmpl"}},
 		{File: filepath.Join(baseDir, "sumcheck_test.go"), Templates: []string{"sumcheck.test.go.tmpl"}},
 	}
+
+	if generateMarshal {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "marshal_test.go"), Templates: []string{"marshal.test.go.tmpl"}},
+		)
+	}
 	return bgen.Generate(conf, "sumcheck", "./sumcheck/template/", entries...)
 }
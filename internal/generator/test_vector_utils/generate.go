@@ -30,7 +30,7 @@ func GenerateRationals(bgen *bavard.BatchGenerator) error {
 	if err := polynomial.Generate(gkrConf.FieldDependency, baseDir+"polynomial", false, bgen); err != nil {
 		return err
 	}
-	if err := sumcheck.Generate(gkrConf.FieldDependency, baseDir+"sumcheck", bgen); err != nil {
+	if err := sumcheck.Generate(gkrConf.FieldDependency, baseDir+"sumcheck", false, bgen); err != nil {
 		return err
 	}
 	if err := gkr.Generate(gkrConf, baseDir+"gkr", bgen); err != nil {
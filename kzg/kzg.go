@@ -31,7 +31,19 @@ type BinaryDumper interface {
 	ReadDump(r io.Reader, maxPkPoints ...int) error
 }
 
-type SRS Serializable
+// SRS is a curve-typed SRS, which can be validated after deserialization to
+// reject corrupted or malicious files
+type SRS interface {
+	Serializable
+
+	// Validate checks that the points of the SRS are in the correct subgroups
+	// and are the powers of a same secret, with a constant number of pairings
+	Validate() error
+
+	// ValidateParallel is Validate with the work split among nbTasks
+	// goroutines, or among all the CPUs if nbTasks ≤ 0
+	ValidateParallel(nbTasks int) error
+}
 
 // NewSRS returns an empty curved-typed SRS object
 // that implements io.ReaderFrom and io.WriterTo interfaces